@@ -0,0 +1,312 @@
+package balance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// Side tells which of the 2 templates a parameter belongs to
+type Side int
+
+// the sides of a matchup
+const (
+	PlayerOne Side = iota
+	PlayerTwo
+)
+
+func (s Side) String() string {
+	if s == PlayerOne {
+		return "one"
+	}
+	return "two"
+}
+
+// Stat names a stat of the player templates
+type Stat string
+
+// the stats with tunable ranges
+const (
+	Health         Stat = "health"
+	Strength       Stat = "strength"
+	Defence        Stat = "defence"
+	Speed          Stat = "speed"
+	Luck           Stat = "luck"
+	Accuracy       Stat = "accuracy"
+	Evasion        Stat = "evasion"
+	CritChance     Stat = "crit-chance"
+	CritMultiplier Stat = "crit-multiplier"
+)
+
+// Stats lists the tunable stats
+var Stats = []Stat{Health, Strength, Defence, Speed, Luck, Accuracy, Evasion, CritChance, CritMultiplier}
+
+func (s Stat) rangeOf(sr *core.StatRanges) (*core.StatRange, error) {
+	switch s {
+	case Health:
+		return &sr.Health, nil
+	case Strength:
+		return &sr.Strength, nil
+	case Defence:
+		return &sr.Defence, nil
+	case Speed:
+		return &sr.Speed, nil
+	case Luck:
+		return &sr.Luck, nil
+	case Accuracy:
+		return &sr.Accuracy, nil
+	case Evasion:
+		return &sr.Evasion, nil
+	case CritChance:
+		return &sr.CritChance, nil
+	case CritMultiplier:
+		return &sr.CritMultiplier, nil
+	}
+	return nil, fmt.Errorf("unknown stat %q", s)
+}
+
+// Parameter is a knob of one of the templates; its
+// value is searched within [Min, Max]
+type Parameter struct {
+	Name string
+	Side Side
+	Min  float64
+	Max  float64
+
+	get func(*core.PlayerTemplate) (float64, error)
+	set func(*core.PlayerTemplate, float64)
+}
+
+// Value returns the value of the parameter within the template
+func (p Parameter) Value(t *core.PlayerTemplate) (float64, error) {
+	return p.get(t)
+}
+
+// StatMin tunes the lower bound of the stat's range; the upper
+// bound is raised when the lower one goes above it
+func StatMin(side Side, stat Stat, min, max float64) Parameter {
+	return Parameter{
+		Name: fmt.Sprintf("%s.%s.min", side, stat),
+		Side: side, Min: min, Max: max,
+		get: func(t *core.PlayerTemplate) (float64, error) {
+			sr, err := stat.rangeOf(&t.Stats)
+			if err != nil {
+				return 0, err
+			}
+			return sr.Min, nil
+		},
+		set: func(t *core.PlayerTemplate, v float64) {
+			sr, _ := stat.rangeOf(&t.Stats)
+			sr.Min = v
+			if sr.Max < v {
+				sr.Max = v
+			}
+		},
+	}
+}
+
+// StatMax tunes the upper bound of the stat's range; the lower
+// bound is lowered when the upper one goes below it
+func StatMax(side Side, stat Stat, min, max float64) Parameter {
+	return Parameter{
+		Name: fmt.Sprintf("%s.%s.max", side, stat),
+		Side: side, Min: min, Max: max,
+		get: func(t *core.PlayerTemplate) (float64, error) {
+			sr, err := stat.rangeOf(&t.Stats)
+			if err != nil {
+				return 0, err
+			}
+			return sr.Max, nil
+		},
+		set: func(t *core.PlayerTemplate, v float64) {
+			sr, _ := stat.rangeOf(&t.Stats)
+			sr.Max = v
+			if sr.Min > v {
+				sr.Min = v
+			}
+		},
+	}
+}
+
+// criticalStrike returns the first critical strike of the template's offensive skills
+func criticalStrike(t *core.PlayerTemplate) (*core.CriticalStrike, error) {
+	for _, skill := range t.Skills.OffensiveSkills {
+		if cs, ok := skill.(*core.CriticalStrike); ok {
+			return cs, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no Critical Strike", t.Name)
+}
+
+// resilience returns the first resilience of the template's defensive skills
+func resilience(t *core.PlayerTemplate) (*core.Resilience, error) {
+	for _, skill := range t.Skills.DefensiveSkills {
+		if r, ok := skill.(*core.Resilience); ok {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no Resilience", t.Name)
+}
+
+// skillParameter tunes a field of one of the skills of the template
+func skillParameter(name string, side Side, min, max float64, field func(*core.PlayerTemplate) (*float64, error)) Parameter {
+	return Parameter{
+		Name: fmt.Sprintf("%s.%s", side, name),
+		Side: side, Min: min, Max: max,
+		get: func(t *core.PlayerTemplate) (float64, error) {
+			f, err := field(t)
+			if err != nil {
+				return 0, err
+			}
+			return *f, nil
+		},
+		set: func(t *core.PlayerTemplate, v float64) {
+			f, _ := field(t)
+			*f = v
+		},
+	}
+}
+
+// DoubleStrikeChance tunes the double strike chance of the first Critical Strike
+func DoubleStrikeChance(side Side, min, max float64) Parameter {
+	return skillParameter("double-strike", side, min, max, func(t *core.PlayerTemplate) (*float64, error) {
+		cs, err := criticalStrike(t)
+		if err != nil {
+			return nil, err
+		}
+		return &cs.DoubleStrikeChance, nil
+	})
+}
+
+// TripleStrikeChance tunes the triple strike chance of the first Critical Strike
+func TripleStrikeChance(side Side, min, max float64) Parameter {
+	return skillParameter("triple-strike", side, min, max, func(t *core.PlayerTemplate) (*float64, error) {
+		cs, err := criticalStrike(t)
+		if err != nil {
+			return nil, err
+		}
+		return &cs.TripleStrikeChance, nil
+	})
+}
+
+// ResilienceChance tunes the chance of the first Resilience
+func ResilienceChance(side Side, min, max float64) Parameter {
+	return skillParameter("resilience", side, min, max, func(t *core.PlayerTemplate) (*float64, error) {
+		r, err := resilience(t)
+		if err != nil {
+			return nil, err
+		}
+		return &r.Chance, nil
+	})
+}
+
+// DamageReduction tunes the damage reduction of the first Resilience
+func DamageReduction(side Side, min, max float64) Parameter {
+	return skillParameter("damage-reduction", side, min, max, func(t *core.PlayerTemplate) (*float64, error) {
+		r, err := resilience(t)
+		if err != nil {
+			return nil, err
+		}
+		return &r.DamageReduction, nil
+	})
+}
+
+// ParseParameter reads a parameter written as "<side>.<knob>=<min>:<max>",
+// where side is "one" or "two" and the knob is either "<stat>.min",
+// "<stat>.max", "double-strike", "triple-strike", "resilience" or
+// "damage-reduction"; e.g. two.strength.max=60:120
+func ParseParameter(s string) (Parameter, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return Parameter{}, fmt.Errorf("parameter %q has no bounds", s)
+	}
+
+	bounds := strings.SplitN(parts[1], ":", 2)
+	if len(bounds) != 2 {
+		return Parameter{}, fmt.Errorf("the bounds of parameter %q must be written as min:max", s)
+	}
+	min, err := strconv.ParseFloat(bounds[0], 64)
+	if err != nil {
+		return Parameter{}, fmt.Errorf("invalid bounds of parameter %q: %v", s, err)
+	}
+	max, err := strconv.ParseFloat(bounds[1], 64)
+	if err != nil {
+		return Parameter{}, fmt.Errorf("invalid bounds of parameter %q: %v", s, err)
+	}
+
+	names := strings.Split(parts[0], ".")
+	var side Side
+	switch names[0] {
+	case "one":
+		side = PlayerOne
+	case "two":
+		side = PlayerTwo
+	default:
+		return Parameter{}, fmt.Errorf("parameter %q must start with one. or two.", s)
+	}
+
+	switch {
+	case len(names) == 3 && names[2] == "min":
+		if _, err := Stat(names[1]).rangeOf(&core.StatRanges{}); err != nil {
+			return Parameter{}, err
+		}
+		return StatMin(side, Stat(names[1]), min, max), nil
+	case len(names) == 3 && names[2] == "max":
+		if _, err := Stat(names[1]).rangeOf(&core.StatRanges{}); err != nil {
+			return Parameter{}, err
+		}
+		return StatMax(side, Stat(names[1]), min, max), nil
+	case len(names) == 2:
+		switch names[1] {
+		case "double-strike":
+			return DoubleStrikeChance(side, min, max), nil
+		case "triple-strike":
+			return TripleStrikeChance(side, min, max), nil
+		case "resilience":
+			return ResilienceChance(side, min, max), nil
+		case "damage-reduction":
+			return DamageReduction(side, min, max), nil
+		}
+	}
+	return Parameter{}, fmt.Errorf("unknown parameter %q", s)
+}
+
+// clone copies the template so that the parameters of its
+// skills can be changed without changing the original template
+func clone(t core.PlayerTemplate) core.PlayerTemplate {
+	t.Skills = core.PlayerSkills{
+		OffensiveSkills: cloneSkills(t.Skills.OffensiveSkills),
+		DefensiveSkills: cloneSkills(t.Skills.DefensiveSkills),
+	}
+	return t
+}
+
+// cloneSkills copies the skills; the skills that can't be
+// cloned are shared with the original template
+func cloneSkills(skills []core.Skill) []core.Skill {
+	cloned := make([]core.Skill, len(skills))
+	for i, skill := range skills {
+		cloned[i] = skill
+		if c, ok := cloneSkill(skill); ok {
+			cloned[i] = c
+		}
+	}
+	return cloned
+}
+
+// cloneSkill builds a new skill from the configuration of the skill,
+// so that no state (e.g. the program of a script) is shared; only
+// the skills of a registered type can be cloned
+func cloneSkill(skill core.Skill) (core.Skill, bool) {
+	config, err := core.NewSkillConfig(skill)
+	if err != nil {
+		return nil, false
+	}
+	c, err := config.Build()
+	if err != nil {
+		return nil, false
+	}
+	return c, true
+}
@@ -0,0 +1,94 @@
+package balance
+
+import (
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/internal/testutil"
+	"github.com/pfzero/battle-simulator/script"
+)
+
+func TestParseParameter(t *testing.T) {
+	template := testutil.Template("Hero", 100, 40, 10, 2)
+	template.Skills = core.PlayerSkills{
+		OffensiveSkills: []core.Skill{&core.CriticalStrike{DoubleStrikeChance: 0.1, TripleStrikeChance: 0.01}},
+		DefensiveSkills: []core.Skill{&core.Resilience{Chance: 0.2, DamageReduction: 0.5}},
+	}
+
+	tests := []struct {
+		spec     string
+		wantName string
+		wantSide Side
+		wantMin  float64
+		wantMax  float64
+		want     float64
+	}{
+		{spec: "one.health.min=50:150", wantName: "one.health.min", wantSide: PlayerOne, wantMin: 50, wantMax: 150, want: 100},
+		{spec: "two.defence.max=0:80", wantName: "two.defence.max", wantSide: PlayerTwo, wantMax: 80, want: 10},
+		{spec: "one.double-strike=0:0.5", wantName: "one.double-strike", wantMax: 0.5, want: 0.1},
+		{spec: "one.triple-strike=0:0.1", wantName: "one.triple-strike", wantMax: 0.1, want: 0.01},
+		{spec: "one.resilience=0:1", wantName: "one.resilience", wantMax: 1, want: 0.2},
+		{spec: "one.damage-reduction=0.1:0.9", wantName: "one.damage-reduction", wantMin: 0.1, wantMax: 0.9, want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, err := ParseParameter(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseParameter() returned an unexpected error: %v", err)
+			}
+			if p.Name != tt.wantName || p.Side != tt.wantSide || p.Min != tt.wantMin || p.Max != tt.wantMax {
+				t.Errorf("ParseParameter() = %s (%v) within [%.2f, %.2f]", p.Name, p.Side, p.Min, p.Max)
+			}
+			if got, err := p.Value(&template); err != nil || got != tt.want {
+				t.Errorf("Value() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	for _, spec := range []string{"one.health.min", "one.health.min=1", "three.health.min=0:1", "one.charm.max=0:1", "one.fireball=0:1", "one.speed.min=a:1"} {
+		if _, err := ParseParameter(spec); err == nil {
+			t.Errorf("ParseParameter(%q) should return an error", spec)
+		}
+	}
+}
+
+func TestParameter_KeepsRangesValid(t *testing.T) {
+	template := testutil.Template("Hero", 100, 40, 10, 2)
+	StatMin(PlayerOne, Strength, 0, 100).set(&template, 60)
+	if template.Stats.Strength != (core.StatRange{Min: 60, Max: 60}) {
+		t.Errorf("StatMin() should raise the upper bound; got %+v", template.Stats.Strength)
+	}
+	StatMax(PlayerOne, Strength, 0, 100).set(&template, 20)
+	if template.Stats.Strength != (core.StatRange{Min: 20, Max: 20}) {
+		t.Errorf("StatMax() should lower the lower bound; got %+v", template.Stats.Strength)
+	}
+}
+
+type unregistered struct{ core.Luck }
+
+func TestClone(t *testing.T) {
+	rage, err := script.Compile("rage", "used('rage')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	skill := &unregistered{}
+	template := testutil.Template("Hero", 100, 40, 10, 2)
+	template.Skills = core.PlayerSkills{
+		OffensiveSkills: []core.Skill{&core.CriticalStrike{DoubleStrikeChance: 0.1}, rage},
+		DefensiveSkills: []core.Skill{skill},
+	}
+
+	cloned := clone(template)
+	for i, s := range cloned.Skills.OffensiveSkills {
+		if s == template.Skills.OffensiveSkills[i] {
+			t.Errorf("the offensive skill %d wasn't cloned", i)
+		}
+	}
+	if used := cloned.Skills.OffensiveSkills[1].GetModifier(core.NewPlayer("Hero", core.PlayerStats{}, core.PlayerSkills{}))(core.NewAttack(10)).UsedOffensiveSkills; len(used) != 1 {
+		t.Errorf("the cloned script used %v, want rage", used)
+	}
+	if cloned.Skills.DefensiveSkills[0] != skill {
+		t.Errorf("the skills that can't be cloned should be shared")
+	}
+}
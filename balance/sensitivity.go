@@ -0,0 +1,206 @@
+package balance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/simulation"
+)
+
+// Knob is a value of a template that is perturbed by a percentage:
+// the range of a stat or a numeric parameter of a skill
+type Knob struct {
+	Name string
+	Side Side
+
+	scale func(t *core.PlayerTemplate, factor float64)
+}
+
+// Knobs lists the stats with non-zero ranges and the non-zero
+// numeric parameters of the skills of the template
+func Knobs(t core.PlayerTemplate, side Side) []Knob {
+	knobs := []Knob{}
+	for _, stat := range Stats {
+		stat := stat
+		if sr, _ := stat.rangeOf(&t.Stats); *sr == (core.StatRange{}) {
+			continue
+		}
+		knobs = append(knobs, Knob{
+			Name: fmt.Sprintf("%s.%s", side, stat),
+			Side: side,
+			scale: func(t *core.PlayerTemplate, factor float64) {
+				sr, _ := stat.rangeOf(&t.Stats)
+				sr.Min *= factor
+				sr.Max *= factor
+			},
+		})
+	}
+
+	knobs = append(knobs, skillKnobs(side, "offensive", t.Skills.OffensiveSkills, func(t *core.PlayerTemplate) []core.Skill {
+		return t.Skills.OffensiveSkills
+	})...)
+	knobs = append(knobs, skillKnobs(side, "defensive", t.Skills.DefensiveSkills, func(t *core.PlayerTemplate) []core.Skill {
+		return t.Skills.DefensiveSkills
+	})...)
+	return knobs
+}
+
+// skillKnobs lists the exported non-zero float fields of the skills
+// that are pointers to structs and can be cloned; the knobs change
+// the i-th skill of the (cloned) template
+func skillKnobs(side Side, kind string, skills []core.Skill, of func(*core.PlayerTemplate) []core.Skill) []Knob {
+	knobs := []Knob{}
+	for i, skill := range skills {
+		v := reflect.ValueOf(skill)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		if _, ok := cloneSkill(skill); !ok {
+			continue
+		}
+
+		st := v.Elem().Type()
+		for j := 0; j < st.NumField(); j++ {
+			field := st.Field(j)
+			if field.PkgPath != "" || field.Type.Kind() != reflect.Float64 || v.Elem().Field(j).Float() == 0 {
+				continue
+			}
+
+			i, j := i, j
+			knobs = append(knobs, Knob{
+				Name: fmt.Sprintf("%s.%s.%s.%s", side, kind, st.Name(), field.Name),
+				Side: side,
+				scale: func(t *core.PlayerTemplate, factor float64) {
+					f := reflect.ValueOf(of(t)[i]).Elem().Field(j)
+					f.SetFloat(f.Float() * factor)
+				},
+			})
+		}
+	}
+	return knobs
+}
+
+// Sensitivity measures how much the win rate of the first player
+// changes when every knob of both templates is perturbed
+type Sensitivity struct {
+	PlayerOne core.PlayerTemplate
+	PlayerTwo core.PlayerTemplate
+	// Perturbation is the percentage every knob is lowered
+	// and raised by, e.g. 0.1 for ±10%
+	Perturbation float64
+
+	Duels  int
+	Rounds int
+	// Seed, when set, reseeds math/rand before every simulation so that
+	// all of them use the same random numbers (less noisy comparisons).
+	// The duels draw from the global source of math/rand, so this
+	// changes the random numbers of the whole process: Run must not
+	// overlap with other duels (e.g. the seeded replays of the server),
+	// and math/rand is reseeded from the clock when it returns
+	Seed int64
+
+	DamageFormula core.DamageFormula
+}
+
+// Effect is the win rate of the first player with a knob lowered and raised
+type Effect struct {
+	Knob string  `json:"knob"`
+	Down float64 `json:"down"`
+	Up   float64 `json:"up"`
+}
+
+// Impact returns the average change of the win rate for a perturbation
+func (e Effect) Impact() float64 {
+	return math.Abs(e.Up-e.Down) / 2
+}
+
+// Report holds the effects of all the knobs, ordered by impact
+type Report struct {
+	Perturbation float64          `json:"perturbation"`
+	Baseline     simulation.Stats `json:"baseline"`
+	Effects      []Effect         `json:"effects"`
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+	p := r.Baseline.PlayerOneWinRate()
+	fmt.Fprintf(&sb, "Baseline win rate %.2f%% (±%.2f%% standard error): %s\n",
+		p*100, math.Sqrt(p*(1-p)/float64(r.Baseline.Duels))*100, r.Baseline)
+	fmt.Fprintf(&sb, "%-4s %-48s %9s %9s %9s\n", "Rank", "Knob",
+		fmt.Sprintf("-%.0f%%", r.Perturbation*100), fmt.Sprintf("+%.0f%%", r.Perturbation*100), "Impact")
+	for i, e := range r.Effects {
+		fmt.Fprintf(&sb, "%-4d %-48s %8.2f%% %8.2f%% %8.2f%%\n", i+1, e.Knob, e.Down*100, e.Up*100, e.Impact()*100)
+	}
+	return sb.String()
+}
+
+// ErrInvalidPerturbation is returned for perturbations outside (0, 1)
+var ErrInvalidPerturbation = errors.New("balance: the perturbation must be within (0, 1)")
+
+// Run simulates the duels of the baseline and of every perturbed knob
+func (s *Sensitivity) Run() (*Report, error) {
+	if s.Perturbation <= 0 || s.Perturbation >= 1 {
+		return nil, ErrInvalidPerturbation
+	}
+	if s.Duels <= 0 {
+		return nil, ErrInvalidDuels
+	}
+
+	if s.Seed != 0 {
+		defer rand.Seed(time.Now().UnixNano())
+	}
+
+	report := &Report{Perturbation: s.Perturbation, Baseline: s.simulate(s.PlayerOne, s.PlayerTwo)}
+
+	knobs := append(Knobs(s.PlayerOne, PlayerOne), Knobs(s.PlayerTwo, PlayerTwo)...)
+	for _, knob := range knobs {
+		effect := Effect{Knob: knob.Name}
+		for _, factor := range []float64{1 - s.Perturbation, 1 + s.Perturbation} {
+			one, two := clone(s.PlayerOne), clone(s.PlayerTwo)
+			if knob.Side == PlayerOne {
+				knob.scale(&one, factor)
+			} else {
+				knob.scale(&two, factor)
+			}
+
+			rate := s.simulate(one, two).PlayerOneWinRate()
+			if factor < 1 {
+				effect.Down = rate
+			} else {
+				effect.Up = rate
+			}
+		}
+		report.Effects = append(report.Effects, effect)
+	}
+
+	sort.SliceStable(report.Effects, func(i, j int) bool {
+		return report.Effects[i].Impact() > report.Effects[j].Impact()
+	})
+	return report, nil
+}
+
+func (s *Sensitivity) simulate(one, two core.PlayerTemplate) simulation.Stats {
+	if s.Seed != 0 {
+		rand.Seed(s.Seed)
+	}
+
+	rounds := s.Rounds
+	if rounds == 0 {
+		rounds = 20
+	}
+	sim := &simulation.Simulation{
+		PlayerOne:     one,
+		PlayerTwo:     two,
+		Duels:         s.Duels,
+		Rounds:        rounds,
+		DamageFormula: s.DamageFormula,
+	}
+	return sim.Run()
+}
@@ -0,0 +1,81 @@
+package balance
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/internal/testutil"
+)
+
+func TestKnobs(t *testing.T) {
+	template := testutil.Template("Hero", 100, 60, 0, 2)
+	template.Skills = core.PlayerSkills{
+		OffensiveSkills: []core.Skill{&core.CriticalStrike{DoubleStrikeChance: 0.5}},
+		DefensiveSkills: []core.Skill{&core.Resilience{Chance: 0.2, DamageReduction: 0.5}},
+	}
+
+	want := []string{
+		"one.health", "one.strength", "one.speed",
+		"one.offensive.CriticalStrike.DoubleStrikeChance",
+		"one.defensive.Resilience.Chance", "one.defensive.Resilience.DamageReduction",
+	}
+	got := []string{}
+	for _, knob := range Knobs(template, PlayerOne) {
+		got = append(got, knob.Name)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Knobs() = %v, want %v", got, want)
+	}
+
+	knobs := Knobs(template, PlayerOne)
+	cloned := clone(template)
+	knobs[3].scale(&cloned, 1.5)
+	if cloned.Skills.OffensiveSkills[0].(*core.CriticalStrike).DoubleStrikeChance != 0.75 ||
+		template.Skills.OffensiveSkills[0].(*core.CriticalStrike).DoubleStrikeChance != 0.5 {
+		t.Errorf("scaling a knob should only change the cloned template")
+	}
+}
+
+func TestSensitivity_Run(t *testing.T) {
+	// both fighters need 10 rounds to knock the other out; the hero hits first
+	s := &Sensitivity{
+		PlayerOne:    testutil.Template("Hero", 100, 60, 0, 2),
+		PlayerTwo:    testutil.Template("Villain", 100, 10, 50, 1),
+		Perturbation: 0.1,
+		Duels:        5,
+	}
+
+	report, err := s.Run()
+	if err != nil {
+		t.Fatalf("Run() returned an unexpected error: %v", err)
+	}
+	if report.Baseline.PlayerOneWinRate() != 1 {
+		t.Errorf("Run() baseline = %s, want the hero to win", report.Baseline)
+	}
+
+	want := map[string]Effect{
+		"one.health":   {Knob: "one.health", Down: 0, Up: 1},
+		"one.strength": {Knob: "one.strength", Down: 0, Up: 1},
+		"one.speed":    {Knob: "one.speed", Down: 1, Up: 1},
+		"two.health":   {Knob: "two.health", Down: 1, Up: 0},
+		"two.strength": {Knob: "two.strength", Down: 1, Up: 1},
+		"two.defence":  {Knob: "two.defence", Down: 1, Up: 0},
+		"two.speed":    {Knob: "two.speed", Down: 1, Up: 1},
+	}
+	if len(report.Effects) != len(want) {
+		t.Fatalf("Run() = %s, want %d effects", report, len(want))
+	}
+	for i, e := range report.Effects {
+		if e != want[e.Knob] {
+			t.Errorf("effect of %s = %+v, want %+v", e.Knob, e, want[e.Knob])
+		}
+		if wantImpact := i < 4; (e.Impact() == 0.5) != wantImpact {
+			t.Errorf("effect %d is %s with %.2f impact; the knobs should be ranked by impact", i+1, e.Knob, e.Impact())
+		}
+	}
+
+	if _, err := (&Sensitivity{PlayerOne: s.PlayerOne, PlayerTwo: s.PlayerTwo, Perturbation: 1, Duels: 5}).Run(); err == nil {
+		t.Errorf("Run() should return an error for perturbations of 100%%")
+	}
+}
@@ -0,0 +1,280 @@
+// Package balance searches and measures the parameters of two
+// player templates through simulated duels
+package balance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/simulation"
+)
+
+// Method represents the search algorithm of the tuner
+type Method int
+
+// the supported search algorithms
+const (
+	// CoordinateDescent moves one parameter at a time by a step
+	// that is halved after every pass without improvements
+	CoordinateDescent Method = iota
+	// Evolution mutates all the parameters of the best candidate
+	// and keeps the best of every generation
+	Evolution
+)
+
+func (m Method) String() string {
+	if m == CoordinateDescent {
+		return "coordinate-descent"
+	}
+	return "evolution"
+}
+
+// Tuner searches the parameters for which the first player
+// wins the given percentage of the duels
+type Tuner struct {
+	PlayerOne core.PlayerTemplate
+	PlayerTwo core.PlayerTemplate
+	// Target is the win rate of the first player, within [0, 1]
+	Target     float64
+	Parameters []Parameter
+
+	Method Method
+	// Duels are simulated for every candidate
+	Duels  int
+	Rounds int
+	// Iterations is the number of passes over the parameters, for
+	// coordinate descent, or the number of generations, for evolution
+	Iterations int
+	// Population is the number of candidates of every generation
+	Population int
+	// Tolerance stops the search once the win rate is that close to the target
+	Tolerance float64
+
+	DamageFormula core.DamageFormula
+}
+
+// Value is the value of a parameter before and after tuning
+type Value struct {
+	Parameter string  `json:"parameter"`
+	Before    float64 `json:"before"`
+	After     float64 `json:"after"`
+}
+
+// Result is the tuned configuration
+type Result struct {
+	PlayerOne core.PlayerTemplate `json:"-"`
+	PlayerTwo core.PlayerTemplate `json:"-"`
+
+	Values      []Value          `json:"values"`
+	Stats       simulation.Stats `json:"stats"`
+	Target      float64          `json:"target"`
+	Evaluations int              `json:"evaluations"`
+}
+
+// WinRate returns the win rate of the first player with the tuned parameters
+func (r *Result) WinRate() float64 {
+	return r.Stats.PlayerOneWinRate()
+}
+
+func (r *Result) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-32s %10s %10s\n", "Parameter", "Before", "After")
+	for _, v := range r.Values {
+		fmt.Fprintf(&sb, "%-32s %10.4f %10.4f\n", v.Parameter, v.Before, v.After)
+	}
+	fmt.Fprintf(&sb, "Win rate %.2f%% (target %.2f%%) after %d evaluations: %s\n",
+		r.WinRate()*100, r.Target*100, r.Evaluations, r.Stats)
+	return sb.String()
+}
+
+var (
+	// ErrNoParameters is returned when there's nothing to tune
+	ErrNoParameters = errors.New("balance: there are no parameters to tune")
+	// ErrInvalidTarget is returned for win rates outside [0, 1]
+	ErrInvalidTarget = errors.New("balance: the target win rate must be within [0, 1]")
+	// ErrInvalidDuels is returned when no duels would be simulated
+	ErrInvalidDuels = errors.New("balance: every candidate needs at least one duel")
+)
+
+// candidate holds the values of the parameters and how far
+// the win rate they lead to is from the target
+type candidate struct {
+	values []float64
+	stats  simulation.Stats
+	error  float64
+}
+
+// search holds the state of a tuning
+type search struct {
+	*Tuner
+	evaluations int
+}
+
+// Run searches the parameters and returns the best configuration found
+func (t *Tuner) Run() (*Result, error) {
+	if len(t.Parameters) == 0 {
+		return nil, ErrNoParameters
+	}
+	if t.Target < 0 || t.Target > 1 {
+		return nil, ErrInvalidTarget
+	}
+	if t.Duels <= 0 {
+		return nil, ErrInvalidDuels
+	}
+
+	initial := make([]float64, len(t.Parameters))
+	for i, p := range t.Parameters {
+		if p.Min > p.Max {
+			return nil, fmt.Errorf("balance: the bounds of %s are reversed", p.Name)
+		}
+		template := t.template(p.Side)
+		v, err := p.Value(&template)
+		if err != nil {
+			return nil, fmt.Errorf("balance: %s: %v", p.Name, err)
+		}
+		initial[i] = p.clamp(v)
+	}
+
+	s := &search{Tuner: t}
+	best := s.evaluate(initial)
+	switch t.Method {
+	case CoordinateDescent:
+		best = s.coordinateDescent(best)
+	case Evolution:
+		best = s.evolution(best)
+	default:
+		return nil, fmt.Errorf("balance: unknown method %v", t.Method)
+	}
+
+	one, two := t.apply(best.values)
+	result := &Result{
+		PlayerOne:   one,
+		PlayerTwo:   two,
+		Stats:       best.stats,
+		Target:      t.Target,
+		Evaluations: s.evaluations,
+	}
+	for i, p := range t.Parameters {
+		result.Values = append(result.Values, Value{Parameter: p.Name, Before: initial[i], After: best.values[i]})
+	}
+	return result, nil
+}
+
+func (t *Tuner) template(side Side) core.PlayerTemplate {
+	if side == PlayerOne {
+		return t.PlayerOne
+	}
+	return t.PlayerTwo
+}
+
+func (p Parameter) clamp(v float64) float64 {
+	return math.Max(p.Min, math.Min(p.Max, v))
+}
+
+// apply returns copies of the templates with the given parameter values
+func (t *Tuner) apply(values []float64) (core.PlayerTemplate, core.PlayerTemplate) {
+	one, two := clone(t.PlayerOne), clone(t.PlayerTwo)
+	for i, p := range t.Parameters {
+		if p.Side == PlayerOne {
+			p.set(&one, values[i])
+		} else {
+			p.set(&two, values[i])
+		}
+	}
+	return one, two
+}
+
+// evaluate simulates the duels of the candidate
+func (s *search) evaluate(values []float64) candidate {
+	s.evaluations++
+	one, two := s.apply(values)
+	sim := &simulation.Simulation{
+		PlayerOne:     one,
+		PlayerTwo:     two,
+		Duels:         s.Duels,
+		Rounds:        s.rounds(),
+		DamageFormula: s.DamageFormula,
+	}
+	stats := sim.Run()
+	return candidate{values: values, stats: stats, error: math.Abs(stats.PlayerOneWinRate() - s.Target)}
+}
+
+func (s *search) rounds() int {
+	if s.Rounds == 0 {
+		return 20
+	}
+	return s.Rounds
+}
+
+func (s *search) iterations() int {
+	if s.Iterations == 0 {
+		return 10
+	}
+	return s.Iterations
+}
+
+func (s *search) done(c candidate) bool {
+	return c.error <= s.Tolerance
+}
+
+func (s *search) coordinateDescent(best candidate) candidate {
+	steps := make([]float64, len(s.Parameters))
+	for i, p := range s.Parameters {
+		steps[i] = (p.Max - p.Min) / 4
+	}
+
+	for pass := 0; pass < s.iterations() && !s.done(best); pass++ {
+		improved := false
+		for i, p := range s.Parameters {
+			for _, direction := range []float64{1, -1} {
+				values := append([]float64{}, best.values...)
+				values[i] = p.clamp(values[i] + direction*steps[i])
+				if values[i] == best.values[i] {
+					continue
+				}
+
+				if c := s.evaluate(values); c.error < best.error {
+					best, improved = c, true
+					break
+				}
+			}
+			if s.done(best) {
+				return best
+			}
+		}
+
+		if !improved {
+			for i := range steps {
+				steps[i] /= 2
+			}
+		}
+	}
+	return best
+}
+
+func (s *search) evolution(best candidate) candidate {
+	population := s.Population
+	if population <= 0 {
+		population = 8
+	}
+
+	// the mutations shrink from a quarter of the
+	// bounds to a sixteenth of them
+	for generation := 0; generation < s.iterations() && !s.done(best); generation++ {
+		spread := 0.25 / math.Pow(4, float64(generation)/float64(s.iterations()))
+		for i := 0; i < population; i++ {
+			values := make([]float64, len(best.values))
+			for j, p := range s.Parameters {
+				values[j] = p.clamp(best.values[j] + rand.NormFloat64()*spread*(p.Max-p.Min))
+			}
+			if c := s.evaluate(values); c.error < best.error {
+				best = c
+			}
+		}
+	}
+	return best
+}
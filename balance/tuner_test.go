@@ -0,0 +1,101 @@
+package balance
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/internal/testutil"
+)
+
+func TestTuner_Run(t *testing.T) {
+	rand.Seed(1)
+
+	tests := []struct {
+		name   string
+		method Method
+	}{
+		{name: "coordinate descent", method: CoordinateDescent},
+		{name: "evolution", method: Evolution},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the hero needs 60 strength to knock the villain out
+			// before being knocked out on round 10
+			tuner := &Tuner{
+				PlayerOne:  testutil.Template("Hero", 100, 40, 0, 2),
+				PlayerTwo:  testutil.Template("Villain", 100, 10, 50, 1),
+				Target:     1,
+				Parameters: []Parameter{StatMin(PlayerOne, Strength, 0, 200)},
+				Method:     tt.method,
+				Duels:      5,
+				Iterations: 20,
+			}
+
+			result, err := tuner.Run()
+			if err != nil {
+				t.Fatalf("Run() returned an unexpected error: %v", err)
+			}
+			if result.WinRate() != 1 || result.Values[0].Before != 40 || result.Values[0].After < 60 {
+				t.Errorf("Run() = %s, want the hero to win with at least 60 strength", result)
+			}
+			if strength := result.PlayerOne.Stats.Strength; strength.Min != result.Values[0].After || strength.Max < strength.Min {
+				t.Errorf("Run() tuned template has strength %+v, want it to start at %.2f", strength, result.Values[0].After)
+			}
+			if tuner.PlayerOne.Stats.Strength.Min != 40 {
+				t.Errorf("Run() should not change the original templates")
+			}
+		})
+	}
+}
+
+func TestTuner_RunSkills(t *testing.T) {
+	hero := testutil.Template("Hero", 100, 60, 0, 2)
+	hero.Skills.OffensiveSkills = []core.Skill{&core.CriticalStrike{}}
+
+	// only a double strike knocks the villain out on round 1
+	tuner := &Tuner{
+		PlayerOne:  hero,
+		PlayerTwo:  testutil.Template("Villain", 100, 100, 0, 1),
+		Target:     1,
+		Parameters: []Parameter{DoubleStrikeChance(PlayerOne, 0, 1)},
+		Duels:      5,
+		Iterations: 10,
+	}
+
+	result, err := tuner.Run()
+	if err != nil {
+		t.Fatalf("Run() returned an unexpected error: %v", err)
+	}
+	if result.WinRate() != 1 || result.Values[0].After == 0 {
+		t.Errorf("Run() = %s, want double strikes to win every duel", result)
+	}
+	if hero.Skills.OffensiveSkills[0].(*core.CriticalStrike).DoubleStrikeChance != 0 {
+		t.Errorf("Run() should not change the skills of the original templates")
+	}
+}
+
+func TestTuner_Errors(t *testing.T) {
+	hero, villain := testutil.Template("Hero", 100, 40, 0, 2), testutil.Template("Villain", 100, 10, 50, 1)
+	strength := StatMax(PlayerOne, Strength, 0, 100)
+
+	tests := []struct {
+		name  string
+		tuner *Tuner
+	}{
+		{name: "no parameters", tuner: &Tuner{PlayerOne: hero, PlayerTwo: villain, Target: 0.5, Duels: 10}},
+		{name: "invalid target", tuner: &Tuner{PlayerOne: hero, PlayerTwo: villain, Target: 1.5, Duels: 10, Parameters: []Parameter{strength}}},
+		{name: "no duels", tuner: &Tuner{PlayerOne: hero, PlayerTwo: villain, Target: 0.5, Parameters: []Parameter{strength}}},
+		{name: "reversed bounds", tuner: &Tuner{PlayerOne: hero, PlayerTwo: villain, Target: 0.5, Duels: 10, Parameters: []Parameter{StatMax(PlayerOne, Strength, 100, 0)}}},
+		{name: "missing skill", tuner: &Tuner{PlayerOne: hero, PlayerTwo: villain, Target: 0.5, Duels: 10, Parameters: []Parameter{DamageReduction(PlayerTwo, 0, 1)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.tuner.Run(); err == nil {
+				t.Errorf("Run() should return an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package campaign
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// Outcome represents how an encounter ended for the hero
+type Outcome string
+
+// the possible outcomes of an encounter
+const (
+	Victory Outcome = "victory"
+	Defeat  Outcome = "defeat"
+	// Standoff means that all rounds were exhausted without a knockout
+	Standoff Outcome = "standoff"
+	// Escape means that the hero fled the duel and walks on
+	Escape Outcome = "escape"
+)
+
+// Encounter represents a duel between the hero and a villain
+type Encounter struct {
+	Number  int
+	Villain string
+	Outcome Outcome
+	Rounds  int
+
+	// HeroHealth is the health left after the duel, before resting
+	HeroHealth float64
+	// Healed is the health recovered while resting after the duel
+	Healed float64
+	// LevelsGained is the number of levels the hero gained by winning the duel
+	LevelsGained int
+	Level        int
+}
+
+// Summary represents the whole walk of the hero through the valley
+type Summary struct {
+	Hero       string
+	Encounters []Encounter
+	Defeated   int
+	Survived   bool
+}
+
+func (s *Summary) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s faced %d villains and defeated %d of them\n", s.Hero, len(s.Encounters), s.Defeated)
+	for _, e := range s.Encounters {
+		fmt.Fprintf(&b, "%d. %s: %s after %d rounds, %.2f health left", e.Number, e.Villain, e.Outcome, e.Rounds, e.HeroHealth)
+		if e.LevelsGained > 0 {
+			fmt.Fprintf(&b, ", reached level %d", e.Level)
+		}
+		if e.Healed > 0 {
+			fmt.Fprintf(&b, " (rested and healed %.2f)", e.Healed)
+		}
+		b.WriteString("\n")
+	}
+
+	if s.Survived {
+		fmt.Fprintf(&b, "%s made it out of The Terminal Valley alive\n", s.Hero)
+	} else {
+		fmt.Fprintf(&b, "%s fell in The Terminal Valley\n", s.Hero)
+	}
+	return b.String()
+}
+
+// Campaign represents the hero's walk through The Terminal Valley, where
+// he meets randomly generated villains one after another. The hero keeps
+// his remaining health between encounters
+type Campaign struct {
+	Hero     *core.Player
+	Villains []core.PlayerTemplate
+
+	// Encounters limits the number of villains met; 0 means that the
+	// walk ends with the hero's death or after MaxEncounters villains
+	Encounters int
+	// Rest is the percentage of the hero's max health
+	// recovered between encounters
+	Rest float64
+	// Progression grants the hero experience for every defeated
+	// villain; the hero doesn't level up when it's nil
+	Progression *core.Progression
+
+	Rounds      int
+	RoundsDelay time.Duration
+	AttackDelay time.Duration
+	// DamageFormula is passed to every duel
+	DamageFormula core.DamageFormula
+
+	// Commentator is passed to every duel of the campaign
+	Commentator core.Commentator
+}
+
+// MaxEncounters bounds the walks without a number of encounters,
+// which never end for heroes who never lose, e.g. by fleeing every duel
+const MaxEncounters = 1000
+
+var (
+	// ErrNoHero is returned when the campaign doesn't have a hero
+	ErrNoHero = errors.New("campaign: there is no hero")
+	// ErrNoVillains is returned when there are no villains to meet
+	ErrNoVillains = errors.New("campaign: there are no villains")
+)
+
+// Run walks the hero through the valley until he dies or
+// the number of encounters, or MaxEncounters, is reached
+func (c *Campaign) Run() (*Summary, error) {
+	if c.Hero == nil {
+		return nil, ErrNoHero
+	}
+	if len(c.Villains) == 0 {
+		return nil, ErrNoVillains
+	}
+
+	maxHealth := c.Hero.Health
+	summary := &Summary{Hero: c.Hero.Name}
+
+	encounters := c.Encounters
+	if encounters == 0 {
+		encounters = MaxEncounters
+	}
+	for n := 1; n <= encounters; n++ {
+		villain := c.Villains[rand.Intn(len(c.Villains))].Summon()
+		villain.Name = fmt.Sprintf("%s #%d", villain.Name, n)
+
+		dm := &core.DuelMaster{
+			Rounds:      c.Rounds,
+			RoundsDelay: c.RoundsDelay,
+			AttackDelay: c.AttackDelay,
+			PlayerOne:   c.Hero,
+			PlayerTwo:   villain,
+
+			DamageFormula: c.DamageFormula,
+		}
+
+		var result *core.DuelResult
+		if c.Commentator != nil {
+			result = dm.StartDuel(c.Commentator)
+		} else {
+			result = dm.StartDuel()
+		}
+
+		encounter := Encounter{
+			Number:     n,
+			Villain:    villain.Name,
+			Outcome:    Standoff,
+			Rounds:     result.Rounds,
+			HeroHealth: c.Hero.Health,
+		}
+		switch {
+		case result.Winner == c.Hero:
+			encounter.Outcome = Victory
+			summary.Defeated++
+
+			if c.Progression != nil {
+				health := c.Hero.Health
+				encounter.LevelsGained = c.Progression.Award(result)
+				encounter.Level = c.Hero.Level
+				maxHealth += c.Hero.Health - health
+			}
+		case result.Loser == c.Hero && result.Fled:
+			encounter.Outcome = Escape
+		case result.Loser == c.Hero:
+			encounter.Outcome = Defeat
+		}
+
+		if encounter.Outcome != Defeat && n != encounters {
+			healed := math.Min(maxHealth-c.Hero.Health, c.Rest*maxHealth)
+			c.Hero.Health += healed
+			encounter.Healed = healed
+		}
+
+		summary.Encounters = append(summary.Encounters, encounter)
+		if encounter.Outcome == Defeat {
+			return summary, nil
+		}
+	}
+
+	summary.Survived = true
+	return summary, nil
+}
@@ -0,0 +1,197 @@
+package campaign
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+func villain(name string, health, strength float64) core.PlayerTemplate {
+	return core.PlayerTemplate{
+		Name: name,
+		Stats: core.StatRanges{
+			Health:   core.StatRange{Min: health, Max: health},
+			Strength: core.StatRange{Min: strength, Max: strength},
+			Speed:    core.StatRange{Min: 20, Max: 20},
+		},
+	}
+}
+
+func hero(health, strength float64) *core.Player {
+	return core.NewPlayer("Hero", core.PlayerStats{
+		Health:   health,
+		Strength: strength,
+		Speed:    10,
+	}, core.PlayerSkills{})
+}
+
+func TestCampaign_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		c              *Campaign
+		wantEncounters int
+		wantDefeated   int
+		wantSurvived   bool
+		wantHealth     float64
+	}{
+		{
+			name: "the hero carries the remaining health between encounters until he dies",
+			c: &Campaign{
+				Hero:     hero(100, 10),
+				Villains: []core.PlayerTemplate{villain("Peanut", 10, 40)},
+				Rounds:   20,
+			},
+			// every villain hits first for 40 and dies on the hero's first hit
+			wantEncounters: 3,
+			wantDefeated:   2,
+			wantHealth:     0,
+		},
+		{
+			name: "the hero rests between encounters",
+			c: &Campaign{
+				Hero:       hero(100, 10),
+				Villains:   []core.PlayerTemplate{villain("Peanut", 10, 40)},
+				Rounds:     20,
+				Rest:       0.5,
+				Encounters: 4,
+			},
+			wantEncounters: 4,
+			wantDefeated:   4,
+			wantSurvived:   true,
+			wantHealth:     60,
+		},
+		{
+			name: "the walk ends after the given number of encounters",
+			c: &Campaign{
+				Hero:       hero(100, 10),
+				Villains:   []core.PlayerTemplate{villain("Peanut", 100, 1)},
+				Rounds:     2,
+				Encounters: 3,
+			},
+			wantEncounters: 3,
+			wantSurvived:   true,
+			wantHealth:     94,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.Run()
+			if err != nil {
+				t.Fatalf("Run() returned an unexpected error: %v", err)
+			}
+			if len(got.Encounters) != tt.wantEncounters || got.Defeated != tt.wantDefeated || got.Survived != tt.wantSurvived {
+				t.Errorf("Run() = %d encounters, %d defeated, survived %v; want %d, %d, %v",
+					len(got.Encounters), got.Defeated, got.Survived, tt.wantEncounters, tt.wantDefeated, tt.wantSurvived)
+			}
+			if tt.c.Hero.Health != tt.wantHealth {
+				t.Errorf("Run() left the hero with %.2f health, want %.2f", tt.c.Hero.Health, tt.wantHealth)
+			}
+		})
+	}
+}
+
+func TestCampaign_Run_MaxEncounters(t *testing.T) {
+	tests := []struct {
+		name        string
+		hero        *core.Player
+		wantOutcome Outcome
+	}{
+		// neither the hero nor the villains can hurt the other
+		{"every duel is a tie", hero(100, 0), Standoff},
+		{"the hero flees every duel", hero(100, 10), Escape},
+	}
+	tests[1].hero.Strategy = core.Defensive{FleeBelow: 2}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Campaign{
+				Hero:     tt.hero,
+				Villains: []core.PlayerTemplate{villain("Peanut", 100, 0)},
+				Rounds:   1,
+			}
+			got, err := c.Run()
+			if err != nil {
+				t.Fatalf("Run() returned an unexpected error: %v", err)
+			}
+			if len(got.Encounters) != MaxEncounters || !got.Survived {
+				t.Errorf("Run() = %d encounters, survived %v; want %d, true", len(got.Encounters), got.Survived, MaxEncounters)
+			}
+			if last := got.Encounters[len(got.Encounters)-1]; last.Outcome != tt.wantOutcome {
+				t.Errorf("Run() ended with a %s, want a %s", last.Outcome, tt.wantOutcome)
+			}
+		})
+	}
+}
+
+func TestCampaign_Run_RestDoesNotExceedMaxHealth(t *testing.T) {
+	c := &Campaign{
+		Hero:       hero(100, 10),
+		Villains:   []core.PlayerTemplate{villain("Peanut", 10, 20)},
+		Rounds:     20,
+		Rest:       1,
+		Encounters: 2,
+	}
+
+	got, err := c.Run()
+	if err != nil {
+		t.Fatalf("Run() returned an unexpected error: %v", err)
+	}
+	if got.Encounters[0].Healed != 20 || c.Hero.Health != 80 {
+		t.Errorf("Run() healed %.2f and left %.2f health, want 20 healed and 80 health", got.Encounters[0].Healed, c.Hero.Health)
+	}
+}
+
+func TestCampaign_Run_Errors(t *testing.T) {
+	if _, err := (&Campaign{}).Run(); err != ErrNoHero {
+		t.Errorf("Run() error = %v, want %v", err, ErrNoHero)
+	}
+	if _, err := (&Campaign{Hero: hero(1, 1)}).Run(); err != ErrNoVillains {
+		t.Errorf("Run() error = %v, want %v", err, ErrNoVillains)
+	}
+}
+
+func TestSummary_String(t *testing.T) {
+	s := &Summary{
+		Hero:     "Hero",
+		Defeated: 1,
+		Encounters: []Encounter{
+			{Number: 1, Villain: "Peanut #1", Outcome: Victory, Rounds: 2, HeroHealth: 50, Healed: 10},
+			{Number: 2, Villain: "Peanut #2", Outcome: Defeat, Rounds: 4},
+		},
+	}
+
+	got := s.String()
+	for _, want := range []string{"defeated 1", "Peanut #1: victory", "healed 10.00", "Peanut #2: defeat", "fell"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestCampaign_Run_Progression(t *testing.T) {
+	c := &Campaign{
+		Hero:       hero(100, 10),
+		Villains:   []core.PlayerTemplate{villain("Peanut", 10, 20)},
+		Rounds:     20,
+		Rest:       1,
+		Encounters: 3,
+		Progression: &core.Progression{
+			XPReward: func(*core.Player) int { return 100 },
+			Growth:   core.StatGrowth{Health: core.LinearGrowth(50)},
+		},
+	}
+
+	got, err := c.Run()
+	if err != nil {
+		t.Fatalf("Run() returned an unexpected error: %v", err)
+	}
+	if c.Hero.Level != 3 || got.Encounters[0].LevelsGained != 1 || got.Encounters[2].Level != 3 {
+		t.Errorf("Run() left the hero on level %d, want 3", c.Hero.Level)
+	}
+	// 100 base health, 50 more for every level and fully rested before the last duel
+	if c.Hero.Health != 180 {
+		t.Errorf("Run() left the hero with %.2f health, want 180", c.Hero.Health)
+	}
+}
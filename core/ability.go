@@ -0,0 +1,131 @@
+package core
+
+import "math"
+
+// Ability is a skill that players use on purpose on their turn,
+// instead of a plain attack; its modifier is applied to the attack
+// before the player's offensive skills. Once used, the ability
+// can't be used again for Cooldown turns. Abilities with a Cost
+// can only be used when the player can afford it
+type Ability struct {
+	Skill    Skill
+	Cooldown int
+	Cost     Cost
+}
+
+// ActionKind represents what a player does on his turn
+type ActionKind int
+
+// the actions a player can take on his turn
+const (
+	// AttackAction is a plain attack
+	AttackAction ActionKind = iota
+	// AbilityAction is an attack enhanced by one of the player's abilities
+	AbilityAction
+	// DefendAction raises the player's defence until his next turn
+	DefendAction
+	// HealAction recovers part of the player's health
+	HealAction
+	// FleeAction ends the duel; the player who flees loses it
+	FleeAction
+)
+
+func (ak ActionKind) String() string {
+	switch ak {
+	case AttackAction:
+		return "attack"
+	case AbilityAction:
+		return "ability"
+	case DefendAction:
+		return "defend"
+	case HealAction:
+		return "heal"
+	case FleeAction:
+		return "flee"
+	}
+	return "unknown"
+}
+
+// Action represents the choice of a player for his turn;
+// Ability must be set for AbilityAction
+type Action struct {
+	Kind    ActionKind
+	Ability *Ability
+}
+
+// IsReady checks whether the ability can be used this turn;
+// abilities the player doesn't have are never ready
+func (p *Player) IsReady(ability *Ability) bool {
+	for _, a := range p.Abilities {
+		if a == ability {
+			return p.cooldowns[ability] == 0 && p.CanAfford(ability.Cost)
+		}
+	}
+	return false
+}
+
+// Cooldown returns, during the player's turn, the
+// number of turns until the ability is ready
+func (p *Player) Cooldown(ability *Ability) int {
+	return p.cooldowns[ability]
+}
+
+// ReadyAbilities returns the abilities that can be used this turn
+func (p *Player) ReadyAbilities() []*Ability {
+	ready := []*Ability{}
+	for _, a := range p.Abilities {
+		if p.IsReady(a) {
+			ready = append(ready, a)
+		}
+	}
+	return ready
+}
+
+// GenerateAbilityAttack generates an attack enhanced by the ability,
+// pays its cost and puts the ability on cooldown
+func (p *Player) GenerateAbilityAttack(ability *Ability) *Attack {
+	p.spend(ability.Cost)
+	if ability.Cooldown > 0 {
+		if p.cooldowns == nil {
+			p.cooldowns = map[*Ability]int{}
+		}
+		// the cooldown ticks at the start of every turn, this one included
+		p.cooldowns[ability] = ability.Cooldown + 1
+	}
+
+	baseAttack := NewAttack(p.Strength)
+	baseAttack.Accuracy = p.Accuracy
+	baseAttack = p.during(OnAttack, ability.Skill.GetModifier(p))(baseAttack)
+	return p.applyCriticalHits(p.offensiveAttackModifier(baseAttack))
+}
+
+// StartingHealth returns the health the player had when the duel started
+func (p *Player) StartingHealth() float64 {
+	return p.startingHealth
+}
+
+// Defend raises the player's defence by the given percentage until his next turn
+func (p *Player) Defend(bonus float64) {
+	p.guard = bonus * p.Defence
+}
+
+// Heal recovers the given percentage of the player's starting health,
+// without going above it, and returns the recovered health
+func (p *Player) Heal(ratio float64) float64 {
+	healed := math.Max(0, math.Min(ratio*p.startingHealth, p.startingHealth-p.Health))
+	p.Health += healed
+	return healed
+}
+
+// startTurn ends the effects lasting until the player's turn, applies
+// his statuses, cools down his abilities and regenerates his resources
+func (p *Player) startTurn() {
+	p.guard = 0
+	p.sufferStatuses()
+	p.regenerate()
+	for ability, turns := range p.cooldowns {
+		if turns > 0 {
+			p.cooldowns[ability] = turns - 1
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlayer_GenerateAbilityAttack(t *testing.T) {
+	whirlwind := &Ability{Skill: &CriticalStrike{DoubleStrikeChance: 1, TripleStrikeChance: 1}, Cooldown: 2}
+	p := NewPlayer("Hero", PlayerStats{Strength: 30}, PlayerSkills{})
+	p.Abilities = []*Ability{whirlwind}
+	p.prepareForDuel(nil)
+
+	want := &Attack{
+		Hits:                []Hit{NewHit(30), NewHit(30), NewHit(30)},
+		UsedOffensiveSkills: []string{"CriticalStrike(3x)"},
+		UsedDefensiveSkills: []string{},
+	}
+	if got := p.GenerateAbilityAttack(whirlwind); !reflect.DeepEqual(got, want) {
+		t.Errorf("GenerateAbilityAttack() = %+v, want %+v", got, want)
+	}
+
+	for turn, wantReady := range []bool{false, false, true} {
+		p.startTurn()
+		if got := p.IsReady(whirlwind); got != wantReady {
+			t.Errorf("IsReady() after %d turns = %v, want %v", turn+1, got, wantReady)
+		}
+	}
+
+	if p.IsReady(&Ability{Skill: &CriticalStrike{}}) {
+		t.Errorf("IsReady() should be false for abilities the player doesn't have")
+	}
+}
+
+func TestPlayer_DefendAndHeal(t *testing.T) {
+	p := NewPlayer("Hero", PlayerStats{Health: 100, Defence: 40}, PlayerSkills{})
+	p.prepareForDuel(nil)
+
+	p.Defend(0.5)
+	if p.EffectiveDefence() != 60 {
+		t.Errorf("Defend() defence = %.2f, want 60", p.EffectiveDefence())
+	}
+	p.startTurn()
+	if p.EffectiveDefence() != 40 {
+		t.Errorf("Defend() should last until the next turn; got %.2f defence", p.EffectiveDefence())
+	}
+
+	p.Health = 70
+	if healed := p.Heal(0.2); healed != 20 || p.Health != 90 {
+		t.Errorf("Heal() = %.2f and %.2f health, want 20 and 90", healed, p.Health)
+	}
+	if healed := p.Heal(0.2); healed != 10 || p.Health != 100 {
+		t.Errorf("Heal() should not go above the starting health; got %.2f and %.2f health", healed, p.Health)
+	}
+}
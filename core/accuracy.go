@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// defaultCritMultiplier is used by players with a crit chance
+// but without a crit multiplier
+const defaultCritMultiplier = 2
+
+// MissChance returns the chance of a hit to miss the defender: the
+// defender's evasion lowered by the attacker's accuracy, within [0, 1]
+func MissChance(accuracy, evasion float64) float64 {
+	return math.Min(1, math.Max(0, evasion-accuracy))
+}
+
+// evasion is the defensive skill every player has;
+// it makes hits miss based on the player's evasion
+// and the attacker's accuracy
+type evasion struct{}
+
+func (e *evasion) GetDescription() string {
+	return `Evasion (chance to evade hits)`
+}
+
+func (e *evasion) GetBattleDescription() string {
+	return `Evaded (you missed)`
+}
+
+// GetModifier reads the player's evasion on every attack
+// so it follows the changes of the player's stats
+func (e *evasion) GetModifier(p *Player) AttackModifier {
+	return func(attack *Attack) *Attack {
+		chance := MissChance(attack.Accuracy, p.Evasion)
+		for i := 0; i < len(attack.Hits); i++ {
+			hit := &attack.Hits[i]
+			if rand.Float64() < chance {
+				hit.Scale(0)
+				hit.UsedDefensiveSkills = append(hit.UsedDefensiveSkills, e.GetBattleDescription())
+			}
+		}
+		return attack
+	}
+}
+
+func (p *Player) critMultiplier() float64 {
+	if p.CritMultiplier == 0 {
+		return defaultCritMultiplier
+	}
+	return p.CritMultiplier
+}
+
+// applyCriticalHits turns every hit of the attack into a
+// critical hit with the player's crit chance
+func (p *Player) applyCriticalHits(attack *Attack) *Attack {
+	if p.CritChance <= 0 {
+		return attack
+	}
+
+	multiplier := p.critMultiplier()
+	for i := 0; i < len(attack.Hits); i++ {
+		hit := &attack.Hits[i]
+		if rand.Float64() < p.CritChance {
+			hit.Scale(multiplier)
+			hit.UsedOffensiveSkills = append(hit.UsedOffensiveSkills, fmt.Sprintf(`Critical Hit(%.2fx)`, multiplier))
+		}
+	}
+	return attack
+}
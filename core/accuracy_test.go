@@ -0,0 +1,90 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissChance(t *testing.T) {
+	tests := []struct {
+		name     string
+		accuracy float64
+		evasion  float64
+		want     float64
+	}{
+		{name: "without accuracy the miss chance is the evasion", accuracy: 0, evasion: 0.3, want: 0.3},
+		{name: "accuracy lowers the evasion", accuracy: 0.25, evasion: 0.75, want: 0.5},
+		{name: "the miss chance doesn't go below 0", accuracy: 0.5, evasion: 0.3, want: 0},
+		{name: "the miss chance doesn't go above 1", accuracy: 0, evasion: 1.5, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MissChance(tt.accuracy, tt.evasion); got != tt.want {
+				t.Errorf("MissChance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlayer_GenerateAttack_CriticalHits(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *Player
+		want *Attack
+	}{
+		{
+			name: "critical hits use the default multiplier",
+			p:    NewPlayer("Crit Creep", PlayerStats{Strength: 20, CritChance: 1, Accuracy: 0.2}, PlayerSkills{}),
+			want: &Attack{
+				Hits:                []Hit{{PotentialDamage: 40, UsedOffensiveSkills: []string{"Critical Hit(2.00x)"}, UsedDefensiveSkills: []string{}}},
+				Accuracy:            0.2,
+				UsedOffensiveSkills: []string{},
+				UsedDefensiveSkills: []string{},
+			},
+		},
+		{
+			name: "critical hits apply to the hits added by skills",
+			p: NewPlayer("Crit Hero", PlayerStats{Strength: 20, CritChance: 1, CritMultiplier: 1.5}, PlayerSkills{
+				OffensiveSkills: []Skill{&CriticalStrike{DoubleStrikeChance: 1}},
+			}),
+			want: &Attack{
+				Hits: []Hit{
+					{PotentialDamage: 30, UsedOffensiveSkills: []string{"Critical Hit(1.50x)"}, UsedDefensiveSkills: []string{}},
+					{PotentialDamage: 30, UsedOffensiveSkills: []string{"Critical Hit(1.50x)"}, UsedDefensiveSkills: []string{}},
+				},
+				UsedOffensiveSkills: []string{"CriticalStrike(2x)"},
+				UsedDefensiveSkills: []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.GenerateAttack(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenerateAttack() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_evasion_GetModifier(t *testing.T) {
+	p := &Player{PlayerStats: PlayerStats{Evasion: 1}}
+	mod := (&evasion{}).GetModifier(p)
+
+	attack := mod(&Attack{Hits: []Hit{NewHit(50)}})
+	if attack.Hits[0].PotentialDamage != 0 || !reflect.DeepEqual(attack.Hits[0].UsedDefensiveSkills, []string{"Evaded (you missed)"}) {
+		t.Errorf("evasion should evade every hit; got %+v", attack.Hits[0])
+	}
+
+	attack = mod(&Attack{Hits: []Hit{NewHit(50)}, Accuracy: 1})
+	if attack.Hits[0].PotentialDamage != 50 {
+		t.Errorf("accuracy should prevent evading; got %+v", attack.Hits[0])
+	}
+
+	p.Evasion = 0
+	attack = mod(&Attack{Hits: []Hit{NewHit(50)}})
+	if attack.Hits[0].PotentialDamage != 50 {
+		t.Errorf("evasion should follow the player's stats; got %+v", attack.Hits[0])
+	}
+}
@@ -2,7 +2,23 @@ package core
 
 // Hit represents the description of a single hit
 type Hit struct {
-	PotentialDamage     float64
+	// PotentialDamage is the physical damage of the hit
+	PotentialDamage float64
+	// ElementalDamage holds the potential damage of the other types
+	ElementalDamage Damage
+	// Damage holds the damage dealt by type, once the hit was defended
+	Damage Damage
+
+	// Penetration ignores a flat amount of the defender's defence while
+	// PenetrationRatio ignores a percentage of it (applied first)
+	Penetration      float64
+	PenetrationRatio float64
+	// Sunder permanently reduces the defender's defence, for the rest
+	// of the duel, once the hit lands
+	Sunder float64
+	// Statuses are applied to the defender once the hit lands
+	Statuses []Status
+
 	UsedOffensiveSkills []string
 	UsedDefensiveSkills []string
 }
@@ -18,7 +34,10 @@ func NewHit(strength float64) Hit {
 
 // Attack represents a player's attack
 type Attack struct {
-	Hits                []Hit
+	Hits []Hit
+	// Accuracy is the attacker's accuracy, lowering the defender's evasion
+	Accuracy float64
+
 	UsedOffensiveSkills []string
 	UsedDefensiveSkills []string
 }
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Budget is a point-buy system keeping custom builds fair: every unit of
+// the stats, the resistances and the skill parameters costs points and a
+// build can't cost more than the budget's Points
+type Budget struct {
+	Points float64 `json:"points"`
+	// Stats are the costs of the stats, by name, e.g. {"Strength": 1, "Luck": 100};
+	// the stats that aren't priced are free
+	Stats map[string]float64 `json:"stats"`
+	// Resistances are the costs of the resistances, by damage type, for
+	// blocking all the damage, e.g. {"physical": 500}; the resistances
+	// that aren't priced can't be bought while vulnerabilities are free
+	Resistances map[DamageType]float64 `json:"resistances"`
+	// Skills are the costs of the skill parameters, by skill type and
+	// parameter, e.g. {"CriticalStrike": {"DoubleStrikeChance": 50}}; the
+	// skills of types that aren't priced can't be bought and the parameters
+	// that aren't priced are free. Declarative skills are priced by the
+	// type of their effects, e.g. {"Declarative": {"multiplyDamage": 300}}
+	// (see declarativeCost), and their effects that aren't priced can't be
+	// bought. Skills without any parameter to price, like scripts, can't
+	// be bought either
+	Skills map[string]map[string]float64 `json:"skills"`
+}
+
+// BudgetError is a build the budget can't pay for
+type BudgetError struct {
+	Name   string
+	Cost   float64
+	Points float64
+	// Unpriced are the skill types and the resistances
+	// of the build that can't be bought
+	Unpriced []string
+}
+
+func (e *BudgetError) Error() string {
+	if len(e.Unpriced) > 0 {
+		return fmt.Sprintf("%s: %s can't be bought", e.Name, strings.Join(e.Unpriced, ", "))
+	}
+	return fmt.Sprintf("%s: the build costs %g points, above the budget of %g", e.Name, e.Cost, e.Points)
+}
+
+// Cost returns the points the template costs, paying every stat for the
+// maximum of its range, together with the resistances and the types of
+// the skills, including the abilities and the skills they wrap, that
+// can't be bought
+func (b Budget) Cost(pt PlayerTemplate) (float64, []string) {
+	cost := 0.0
+	for i, r := range pt.Stats.list() {
+		cost += b.Stats[statDomains[i].name] * r.Max
+	}
+
+	unpriced := map[string]bool{}
+	for t, r := range pt.Resistances {
+		if r <= 0 {
+			continue
+		}
+		price, ok := b.Resistances[t]
+		if !ok {
+			unpriced[fmt.Sprintf("%s resistance", t)] = true
+		}
+		cost += price * r
+	}
+
+	skills := append(append([]Skill{}, pt.Skills.OffensiveSkills...), pt.Skills.DefensiveSkills...)
+	for _, ability := range pt.Abilities {
+		skills = append(skills, ability.Skill)
+	}
+	for _, skill := range skills {
+		cost += b.skillCost(reflect.ValueOf(skill), unpriced)
+	}
+
+	types := []string{}
+	for t := range unpriced {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return cost, types
+}
+
+// skillCost prices the float and int parameters of the skill
+// and, recursively, the skills it wraps
+func (b Budget) skillCost(skill reflect.Value, unpriced map[string]bool) float64 {
+	v := reflect.Indirect(skill)
+	if v.Kind() != reflect.Struct {
+		unpriced[v.Type().String()] = true
+		return 0
+	}
+	costs, ok := b.Skills[v.Type().Name()]
+	if !ok {
+		unpriced[v.Type().Name()] = true
+	}
+	if d, isDeclarative := skill.Interface().(*Declarative); isDeclarative {
+		if !ok {
+			return 0
+		}
+		return declarativeCost(d.Spec, costs, unpriced)
+	}
+
+	cost, priced := 0.0, false
+	skillType := reflect.TypeOf((*Skill)(nil)).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		switch {
+		case field.PkgPath != "" || field.Anonymous:
+		case field.Type.Kind() == reflect.Float64:
+			cost, priced = cost+costs[field.Name]*value.Float(), true
+		case field.Type.Kind() == reflect.Int:
+			cost, priced = cost+costs[field.Name]*float64(value.Int()), true
+		case field.Type == skillType && !value.IsNil():
+			cost, priced = cost+b.skillCost(value.Elem(), unpriced), true
+		}
+	}
+	if !priced {
+		unpriced[v.Type().Name()] = true
+	}
+	return cost
+}
+
+// declarativeCost prices every effect for each unit of its amount: the
+// number of hits added, the damage added by a multiplier (e.g. 1 for
+// doubling the damage), the percentage of damage blocked or of health
+// healed, and a unit for every status applied. The effects are paid for
+// the chance they have to be applied, and skills recovering for the
+// attacks they can fire on, once every Recovery+1 attacks
+func declarativeCost(spec SkillSpec, costs map[string]float64, unpriced map[string]bool) float64 {
+	rate := 1.0
+	if spec.Chance != nil {
+		rate = *spec.Chance
+	}
+	if spec.Recovery > 0 {
+		rate /= float64(1 + spec.Recovery)
+	}
+
+	cost := 0.0
+	for _, effect := range spec.Effects {
+		price, ok := costs[string(effect.Type)]
+		if !ok {
+			unpriced[fmt.Sprintf("Declarative(%s)", effect.Type)] = true
+			continue
+		}
+
+		amount := effect.Amount
+		switch effect.Type {
+		case AddHit:
+			amount = math.Max(1, math.Floor(amount))
+		case MultiplyDamage:
+			amount = math.Max(0, amount-1)
+		case ApplyStatus:
+			amount = 1
+		}
+		if effect.Chance != nil {
+			amount *= *effect.Chance
+		}
+		cost += price * amount
+	}
+	return cost * rate
+}
+
+// Check checks that the budget pays for the template;
+// the error is a *BudgetError
+func (b Budget) Check(pt PlayerTemplate) error {
+	cost, unpriced := b.Cost(pt)
+	if len(unpriced) > 0 || cost > b.Points {
+		return &BudgetError{Name: pt.Name, Cost: cost, Points: b.Points, Unpriced: unpriced}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBudget_Cost(t *testing.T) {
+	budget := Budget{
+		Points:      200,
+		Stats:       map[string]float64{"Health": 1, "Strength": 1, "Luck": 100},
+		Resistances: map[DamageType]float64{Physical: 500, Fire: 200},
+		Skills: map[string]map[string]float64{
+			"CriticalStrike": {"DoubleStrikeChance": 100, "TripleStrikeChance": 200},
+			"Triggered":      {"Priority": 5},
+			"Declarative":    {"addHit": 100, "multiplyDamage": 200, "heal": 100},
+			"evasion":        {},
+		},
+	}
+	half := 0.5
+
+	tests := []struct {
+		name         string
+		template     PlayerTemplate
+		wantCost     float64
+		wantUnpriced []string
+	}{
+		{"stats at their maximum", PlayerTemplate{Stats: StatRanges{
+			Health:   StatRange{Min: 70, Max: 100},
+			Strength: StatRange{Min: 50, Max: 50},
+			Defence:  StatRange{Min: 50, Max: 50},
+			Luck:     StatRange{Min: 0.1, Max: 0.2},
+		}}, 170, []string{}},
+		{"resistances", PlayerTemplate{
+			Resistances: Resistances{Physical: 0.1, Fire: 0.25},
+		}, 100, []string{}},
+		{"vulnerabilities", PlayerTemplate{
+			Resistances: Resistances{Physical: 0.1, Frost: -0.5},
+		}, 50, []string{}},
+		{"unpriced resistances", PlayerTemplate{
+			Resistances: Resistances{Physical: 1, Poison: 1, Frost: 0.5},
+		}, 500, []string{"frost resistance", "poison resistance"}},
+		{"skills", PlayerTemplate{Skills: PlayerSkills{
+			OffensiveSkills: []Skill{&CriticalStrike{DoubleStrikeChance: 0.1, TripleStrikeChance: 0.05}},
+		}}, 20, []string{}},
+		{"wrapped skills and abilities", PlayerTemplate{
+			Skills: PlayerSkills{
+				OffensiveSkills: []Skill{&Triggered{Skill: &CriticalStrike{DoubleStrikeChance: 0.5}, HealthBelow: 0.3}},
+			},
+			Abilities: []*Ability{{Skill: &CriticalStrike{DoubleStrikeChance: 1}}},
+		}, 150, []string{}},
+		{"unpriced skills", PlayerTemplate{Skills: PlayerSkills{
+			OffensiveSkills: []Skill{&SunderArmor{Chance: 1, Amount: 5}},
+			DefensiveSkills: []Skill{&Resilience{Chance: 0.2}, &Triggered{Skill: &Luck{Chance: 0.1}}},
+		}}, 0, []string{"Luck", "Resilience", "SunderArmor"}},
+		{"int parameters", PlayerTemplate{Skills: PlayerSkills{
+			OffensiveSkills: []Skill{&Triggered{Skill: &CriticalStrike{DoubleStrikeChance: 0.1}, Priority: 2}},
+		}}, 20, []string{}},
+		// 2 units of damage, 1 hit and half of 20% health, paid
+		// for firing on half of every other attack
+		{"declarative effects", PlayerTemplate{Skills: PlayerSkills{
+			OffensiveSkills: []Skill{&Declarative{Spec: SkillSpec{
+				Name:     "Fury",
+				Chance:   &half,
+				Recovery: 1,
+				Effects: []EffectSpec{
+					{Type: MultiplyDamage, Amount: 3},
+					{Type: AddHit},
+					{Type: Heal, Amount: 0.2, Chance: &half},
+				},
+			}}},
+		}}, 127.5, []string{}},
+		{"unpriced effects", PlayerTemplate{Skills: PlayerSkills{
+			DefensiveSkills: []Skill{&Declarative{Spec: SkillSpec{Name: "Ward", Effects: []EffectSpec{{Type: ReduceDamage, Amount: 0.5}}}}},
+		}}, 0, []string{"Declarative(reduceDamage)"}},
+		{"skills without parameters", PlayerTemplate{Skills: PlayerSkills{
+			DefensiveSkills: []Skill{&evasion{}},
+		}}, 0, []string{"evasion"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, unpriced := budget.Cost(tt.template)
+			if math.Abs(cost-tt.wantCost) > 1e-9 || !reflect.DeepEqual(unpriced, tt.wantUnpriced) {
+				t.Errorf("Cost() = %v, %v; want %v, %v", cost, unpriced, tt.wantCost, tt.wantUnpriced)
+			}
+		})
+	}
+}
+
+func TestBudget_Check(t *testing.T) {
+	budget := Budget{Points: 150, Stats: map[string]float64{"Health": 1, "Strength": 1}}
+
+	tests := []struct {
+		name     string
+		strength float64
+		skills   []Skill
+		wantErr  string
+	}{
+		{"within the budget", 50, nil, ""},
+		{"above the budget", 60, nil, "Hero: the build costs 160 points, above the budget of 150"},
+		{"unpriced skills", 10, []Skill{&CriticalStrike{}}, "Hero: CriticalStrike can't be bought"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := PlayerTemplate{
+				Name: "Hero",
+				Stats: StatRanges{
+					Health:   StatRange{Min: 100, Max: 100},
+					Strength: StatRange{Min: tt.strength, Max: tt.strength},
+				},
+				Skills: PlayerSkills{OffensiveSkills: tt.skills},
+			}
+			err := budget.Check(pt)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check() = %v", err)
+				}
+				return
+			}
+			var berr *BudgetError
+			if !errors.As(err, &berr) || err.Error() != tt.wantErr {
+				t.Errorf("Check() = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+// Commentators passes every event of a duel to all of its commentators, in order
+type Commentators []Commentator
+
+// Start comments the starting of the duel
+func (cs Commentators) Start() {
+	for _, c := range cs {
+		c.Start()
+	}
+}
+
+// PresentPlayers presents the fighters
+func (cs Commentators) PresentPlayers(first, second *Player) {
+	for _, c := range cs {
+		c.PresentPlayers(first, second)
+	}
+}
+
+// PresentRound presents the start of a round
+func (cs Commentators) PresentRound(round int) {
+	for _, c := range cs {
+		c.PresentRound(round)
+	}
+}
+
+// PresentResources presents the resources of the fighters
+func (cs Commentators) PresentResources(first, second *Player) {
+	for _, c := range cs {
+		c.PresentResources(first, second)
+	}
+}
+
+// PresentAttack presents an attack once it was defended
+func (cs Commentators) PresentAttack(attack *Attack, attacker, defender *Player) {
+	for _, c := range cs {
+		c.PresentAttack(attack, attacker, defender)
+	}
+}
+
+// PresentAction presents an action other than a plain attack
+func (cs Commentators) PresentAction(action Action, player *Player) {
+	for _, c := range cs {
+		c.PresentAction(action, player)
+	}
+}
+
+// EndDuelKnockout presents the end of a duel by knockout
+func (cs Commentators) EndDuelKnockout(round int, winner, loser *Player) {
+	for _, c := range cs {
+		c.EndDuelKnockout(round, winner, loser)
+	}
+}
+
+// EndDuelFlee presents the end of a duel by fleeing
+func (cs Commentators) EndDuelFlee(round int, fled, winner *Player) {
+	for _, c := range cs {
+		c.EndDuelFlee(round, fled, winner)
+	}
+}
+
+// EndDuelTie presents the end of a duel without a winner
+func (cs Commentators) EndDuelTie(round int, player1, player2 *Player) {
+	for _, c := range cs {
+		c.EndDuelTie(round, player1, player2)
+	}
+}
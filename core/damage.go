@@ -0,0 +1,188 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// DamageType represents the kind of damage a hit does
+type DamageType string
+
+// the supported damage types; physical damage is reduced by defence
+// while true damage ignores both defence and resistances
+const (
+	Physical DamageType = "physical"
+	Fire     DamageType = "fire"
+	Frost    DamageType = "frost"
+	Poison   DamageType = "poison"
+	True     DamageType = "true"
+)
+
+// DamageTypes lists the damage types in the order they are applied
+var DamageTypes = []DamageType{Physical, Fire, Frost, Poison, True}
+
+// Resistances holds the percentage of damage blocked for every
+// damage type; negative values are vulnerabilities (-0.5 means
+// 50% more damage)
+type Resistances map[DamageType]float64
+
+// Damage holds an amount of damage for every damage type
+type Damage map[DamageType]float64
+
+// Total returns the sum of the damage of all types
+func (d Damage) Total() float64 {
+	total := 0.0
+	for _, t := range DamageTypes {
+		total += d[t]
+	}
+	return total
+}
+
+func (d Damage) String() string {
+	parts := []string{}
+	for _, t := range DamageTypes {
+		if v, ok := d[t]; ok {
+			parts = append(parts, fmt.Sprintf("%.2f %s", v, t))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// AddDamage adds damage of the given type to the hit
+func (h *Hit) AddDamage(t DamageType, amount float64) {
+	if t == Physical {
+		h.PotentialDamage += amount
+		return
+	}
+	if h.ElementalDamage == nil {
+		h.ElementalDamage = Damage{}
+	}
+	h.ElementalDamage[t] += amount
+}
+
+// Scale multiplies the damage of all types by the given factor
+func (h *Hit) Scale(factor float64) {
+	h.PotentialDamage *= factor
+	for t := range h.ElementalDamage {
+		h.ElementalDamage[t] *= factor
+	}
+}
+
+// Reduce blocks the given percentage of the damage of all types
+func (h *Hit) Reduce(ratio float64) {
+	h.PotentialDamage -= ratio * h.PotentialDamage
+	for t := range h.ElementalDamage {
+		h.ElementalDamage[t] -= ratio * h.ElementalDamage[t]
+	}
+}
+
+// PotentialDamages returns the potential damage of the hit by type
+func (h *Hit) PotentialDamages() Damage {
+	damage := Damage{Physical: h.PotentialDamage}
+	for t, v := range h.ElementalDamage {
+		damage[t] += v
+	}
+	return damage
+}
+
+// TotalPotentialDamage returns the potential damage of all types
+func (h *Hit) TotalPotentialDamage() float64 {
+	return h.PotentialDamages().Total()
+}
+
+// defenceAgainst returns the part of the given defence that
+// isn't ignored by the hit's armor penetration
+func (h *Hit) defenceAgainst(defence float64) float64 {
+	if defence <= 0 {
+		return defence
+	}
+	return math.Max(0, defence*(1-h.PenetrationRatio)-h.Penetration)
+}
+
+// resist applies the player's defence and resistances
+// to the potential damage of the hit
+func (p *Player) resist(hit *Hit) Damage {
+	damage := Damage{}
+	for t, potential := range hit.PotentialDamages() {
+		if potential == 0 {
+			continue
+		}
+		if t == True {
+			damage[t] = potential
+			continue
+		}
+		if t == Physical {
+			potential = p.damageFormula().Damage(potential, hit.defenceAgainst(p.EffectiveDefence()))
+		}
+		damage[t] = math.Max(0, potential*(1-p.Resistances[t]))
+	}
+	return damage
+}
+
+// ElementalInfusion is an offensive skill
+// it has a given chance to add elemental damage to every hit of an attack
+type ElementalInfusion struct {
+	Type   DamageType
+	Chance float64
+	Damage float64
+}
+
+// GetDescription returns the long description of the skill
+func (ei *ElementalInfusion) GetDescription() string {
+	return fmt.Sprintf(`Elemental Infusion (%.2f%% chance to add %.2f %s damage)`, ei.Chance*100, ei.Damage, ei.Type)
+}
+
+// GetBattleDescription returns the short (battle) description of the skill
+func (ei *ElementalInfusion) GetBattleDescription() string {
+	return fmt.Sprintf(`Infusion(+%.2f %s)`, ei.Damage, ei.Type)
+}
+
+// GetModifier returns the skill in a chainable form
+func (ei *ElementalInfusion) GetModifier(*Player) AttackModifier {
+	return func(attack *Attack) *Attack {
+		if rand.Float64() < ei.Chance {
+			for i := range attack.Hits {
+				attack.Hits[i].AddDamage(ei.Type, ei.Damage)
+			}
+			attack.UsedOffensiveSkills = append(attack.UsedOffensiveSkills, ei.GetBattleDescription())
+		}
+		return attack
+	}
+}
+
+// ElementalConversion is an offensive skill
+// it has a given chance to convert a percentage of the physical
+// damage of every hit of an attack into elemental damage
+type ElementalConversion struct {
+	Type   DamageType
+	Chance float64
+	Ratio  float64
+}
+
+// GetDescription returns the long description of the skill
+func (ec *ElementalConversion) GetDescription() string {
+	return fmt.Sprintf(`Elemental Conversion (%.2f%% chance to convert %.2f%% damage to %s)`, ec.Chance*100, ec.Ratio*100, ec.Type)
+}
+
+// GetBattleDescription returns the short (battle) description of the skill
+func (ec *ElementalConversion) GetBattleDescription() string {
+	return fmt.Sprintf(`Conversion(%.2f%% to %s)`, ec.Ratio*100, ec.Type)
+}
+
+// GetModifier returns the skill in a chainable form
+func (ec *ElementalConversion) GetModifier(*Player) AttackModifier {
+	return func(attack *Attack) *Attack {
+		if rand.Float64() < ec.Chance {
+			for i := range attack.Hits {
+				hit := &attack.Hits[i]
+				converted := hit.PotentialDamage * ec.Ratio
+				hit.PotentialDamage -= converted
+				hit.AddDamage(ec.Type, converted)
+			}
+			attack.UsedOffensiveSkills = append(attack.UsedOffensiveSkills, ec.GetBattleDescription())
+		}
+		return attack
+	}
+}
@@ -0,0 +1,65 @@
+package core
+
+import "math"
+
+// DamageFormula computes the physical damage a hit with the
+// given potential damage does to a defender with the given defence
+type DamageFormula interface {
+	Damage(potential, defence float64) float64
+}
+
+// Subtractive is the classic formula: damage = strength - defence,
+// without going below 0
+type Subtractive struct{}
+
+// Damage subtracts the defence from the potential damage
+func (Subtractive) Damage(potential, defence float64) float64 {
+	return math.Max(0, potential-defence)
+}
+
+// Mitigation blocks a percentage of the damage given by
+// defence / (defence + K); the higher K is, the less defence matters
+type Mitigation struct {
+	K float64
+}
+
+// Damage reduces the potential damage by the mitigated percentage
+func (m Mitigation) Damage(potential, defence float64) float64 {
+	defence = math.Max(0, defence)
+	if defence+m.K <= 0 {
+		return 0
+	}
+	return potential * m.K / (defence + m.K)
+}
+
+// MinimumDamage guarantees that every hit (that wasn't evaded) does at
+// least Flat damage or Ratio of its potential damage, whichever is higher,
+// but never more than its potential damage
+type MinimumDamage struct {
+	Formula DamageFormula
+	Flat    float64
+	Ratio   float64
+}
+
+// Damage applies the wrapped formula and the floor
+func (md MinimumDamage) Damage(potential, defence float64) float64 {
+	damage := md.Formula.Damage(potential, defence)
+	if potential <= 0 {
+		return damage
+	}
+
+	floor := math.Min(potential, math.Max(md.Flat, md.Ratio*potential))
+	return math.Max(damage, floor)
+}
+
+// Variance randomly changes the damage of the wrapped formula
+// by up to Spread percent, in both directions
+type Variance struct {
+	Formula DamageFormula
+	Spread  float64
+}
+
+// Damage applies the wrapped formula and the random variance
+func (v Variance) Damage(potential, defence float64) float64 {
+	return v.Formula.Damage(potential, defence) * Range(1-v.Spread, 1+v.Spread)
+}
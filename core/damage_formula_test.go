@@ -0,0 +1,80 @@
+package core
+
+import "testing"
+
+func TestDamageFormulas(t *testing.T) {
+	tests := []struct {
+		name      string
+		formula   DamageFormula
+		potential float64
+		defence   float64
+		want      float64
+	}{
+		{name: "subtractive subtracts the defence", formula: Subtractive{}, potential: 80, defence: 50, want: 30},
+		{name: "subtractive doesn't go below 0", formula: Subtractive{}, potential: 40, defence: 50, want: 0},
+		{name: "mitigation blocks defence / (defence + K)", formula: Mitigation{K: 50}, potential: 80, defence: 50, want: 40},
+		{name: "mitigation always lets some damage through", formula: Mitigation{K: 100}, potential: 40, defence: 300, want: 10},
+		{name: "mitigation ignores negative defence", formula: Mitigation{K: 100}, potential: 40, defence: -50, want: 40},
+		{
+			name:      "minimum damage applies a flat floor",
+			formula:   MinimumDamage{Formula: Subtractive{}, Flat: 5},
+			potential: 40, defence: 50, want: 5,
+		},
+		{
+			name:      "minimum damage applies a percentage floor",
+			formula:   MinimumDamage{Formula: Subtractive{}, Flat: 5, Ratio: 0.25},
+			potential: 40, defence: 50, want: 10,
+		},
+		{
+			name:      "minimum damage keeps higher damage",
+			formula:   MinimumDamage{Formula: Subtractive{}, Flat: 5},
+			potential: 80, defence: 50, want: 30,
+		},
+		{
+			name:      "minimum damage doesn't exceed the potential damage",
+			formula:   MinimumDamage{Formula: Subtractive{}, Flat: 5},
+			potential: 2, defence: 50, want: 2,
+		},
+		{
+			name:      "minimum damage doesn't apply to evaded hits",
+			formula:   MinimumDamage{Formula: Subtractive{}, Flat: 5},
+			potential: 0, defence: 50, want: 0,
+		},
+		{
+			name:      "variance without spread keeps the damage",
+			formula:   Variance{Formula: Subtractive{}},
+			potential: 80, defence: 50, want: 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formula.Damage(tt.potential, tt.defence); got != tt.want {
+				t.Errorf("Damage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariance_Damage(t *testing.T) {
+	v := Variance{Formula: Subtractive{}, Spread: 0.2}
+	for i := 0; i < 100; i++ {
+		if got := v.Damage(80, 30); got < 40 || got > 60 {
+			t.Fatalf("Damage() = %v, want between 40 and 60", got)
+		}
+	}
+}
+
+func TestDuelMaster_DamageFormula(t *testing.T) {
+	dm := &DuelMaster{
+		Rounds:        1,
+		PlayerOne:     NewPlayer("Hero", PlayerStats{Health: 100, Strength: 40, Defence: 60, Speed: 2}, PlayerSkills{}),
+		PlayerTwo:     NewPlayer("Villain", PlayerStats{Health: 100, Strength: 40, Defence: 60, Speed: 1}, PlayerSkills{}),
+		DamageFormula: MinimumDamage{Formula: Subtractive{}, Flat: 10},
+	}
+
+	dm.StartDuel()
+	if dm.PlayerOne.Health != 90 || dm.PlayerTwo.Health != 90 {
+		t.Errorf("StartDuel() should use the duel's formula; got %.2f and %.2f health", dm.PlayerOne.Health, dm.PlayerTwo.Health)
+	}
+}
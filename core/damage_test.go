@@ -0,0 +1,193 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlayer_resist(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *Player
+		hit  Hit
+		want Damage
+	}{
+		{
+			name: "defence only reduces physical damage",
+			p:    &Player{PlayerStats: PlayerStats{Defence: 30}},
+			hit:  Hit{PotentialDamage: 50, ElementalDamage: Damage{Fire: 20}},
+			want: Damage{Physical: 20, Fire: 20},
+		},
+		{
+			name: "resistances reduce damage and vulnerabilities increase it",
+			p: &Player{
+				PlayerStats: PlayerStats{Defence: 10},
+				Resistances: Resistances{Physical: 0.5, Frost: 0.25, Poison: -0.5},
+			},
+			hit:  Hit{PotentialDamage: 50, ElementalDamage: Damage{Frost: 20, Poison: 10}},
+			want: Damage{Physical: 20, Frost: 15, Poison: 15},
+		},
+		{
+			name: "true damage ignores defence and resistances",
+			p: &Player{
+				PlayerStats: PlayerStats{Defence: 100},
+				Resistances: Resistances{True: 1},
+			},
+			hit:  Hit{ElementalDamage: Damage{True: 20}},
+			want: Damage{True: 20},
+		},
+		{
+			name: "full immunity blocks all the damage",
+			p:    &Player{Resistances: Resistances{Fire: 1.5}},
+			hit:  Hit{ElementalDamage: Damage{Fire: 20}},
+			want: Damage{Fire: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.resist(&tt.hit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resist() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlayer_DefendAttack_DamageTypes(t *testing.T) {
+	p := NewPlayer("Frost Giant", PlayerStats{Health: 100, Defence: 10}, PlayerSkills{})
+	p.Resistances = Resistances{Frost: 1, Fire: -1}
+
+	attack := NewAttack(30)
+	attack.Hits[0].AddDamage(Fire, 10)
+	attack.Hits[0].AddDamage(Frost, 50)
+
+	p.DefendAttack(attack)
+	if p.Health != 60 {
+		t.Errorf("DefendAttack() left %.2f health, want 60", p.Health)
+	}
+	if want := (Damage{Physical: 20, Fire: 20, Frost: 0}); !reflect.DeepEqual(attack.Hits[0].Damage, want) {
+		t.Errorf("DefendAttack() recorded %v damage, want %v", attack.Hits[0].Damage, want)
+	}
+}
+
+func TestHit_DamageHelpers(t *testing.T) {
+	hit := NewHit(40)
+	hit.AddDamage(Physical, 10)
+	hit.AddDamage(Poison, 20)
+
+	if got := hit.TotalPotentialDamage(); got != 70 {
+		t.Errorf("TotalPotentialDamage() = %v, want 70", got)
+	}
+
+	hit.Reduce(0.5)
+	if want := (Damage{Physical: 25, Poison: 10}); !reflect.DeepEqual(hit.PotentialDamages(), want) {
+		t.Errorf("Reduce() = %v, want %v", hit.PotentialDamages(), want)
+	}
+
+	hit.Scale(0)
+	if got := hit.TotalPotentialDamage(); got != 0 {
+		t.Errorf("Scale(0) = %v, want 0", got)
+	}
+
+	if got := (Damage{Physical: 1, Fire: 2.5}).String(); got != "1.00 physical, 2.50 fire" {
+		t.Errorf("Damage.String() = %q", got)
+	}
+}
+
+func TestElementalSkills_GetModifier(t *testing.T) {
+	tests := []struct {
+		name  string
+		skill Skill
+		want  *Attack
+	}{
+		{
+			name:  "Elemental Infusion adds elemental damage to every hit",
+			skill: &ElementalInfusion{Type: Fire, Chance: 1, Damage: 15},
+			want: &Attack{
+				Hits: []Hit{
+					{PotentialDamage: 40, ElementalDamage: Damage{Fire: 15}, UsedOffensiveSkills: []string{}, UsedDefensiveSkills: []string{}},
+					{PotentialDamage: 40, ElementalDamage: Damage{Fire: 15}, UsedOffensiveSkills: []string{}, UsedDefensiveSkills: []string{}},
+				},
+				UsedOffensiveSkills: []string{"Infusion(+15.00 fire)"},
+				UsedDefensiveSkills: []string{},
+			},
+		},
+		{
+			name:  "Elemental Conversion converts physical damage",
+			skill: &ElementalConversion{Type: Frost, Chance: 1, Ratio: 0.25},
+			want: &Attack{
+				Hits: []Hit{
+					{PotentialDamage: 30, ElementalDamage: Damage{Frost: 10}, UsedOffensiveSkills: []string{}, UsedDefensiveSkills: []string{}},
+					{PotentialDamage: 30, ElementalDamage: Damage{Frost: 10}, UsedOffensiveSkills: []string{}, UsedDefensiveSkills: []string{}},
+				},
+				UsedOffensiveSkills: []string{"Conversion(25.00% to frost)"},
+				UsedDefensiveSkills: []string{},
+			},
+		},
+		{
+			name:  "elemental skills don't trigger without chance",
+			skill: &ElementalInfusion{Type: Fire, Chance: 0, Damage: 15},
+			want: &Attack{
+				Hits:                []Hit{NewHit(40), NewHit(40)},
+				UsedOffensiveSkills: []string{},
+				UsedDefensiveSkills: []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attack := &Attack{Hits: []Hit{NewHit(40), NewHit(40)}, UsedOffensiveSkills: []string{}, UsedDefensiveSkills: []string{}}
+			if got := tt.skill.GetModifier(&Player{})(attack); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetModifier() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHit_defenceAgainst(t *testing.T) {
+	tests := []struct {
+		name    string
+		hit     Hit
+		defence float64
+		want    float64
+	}{
+		{name: "no penetration keeps the defence", hit: Hit{}, defence: 50, want: 50},
+		{name: "flat penetration ignores an amount of defence", hit: Hit{Penetration: 20}, defence: 50, want: 30},
+		{name: "percentage penetration is applied before flat", hit: Hit{Penetration: 5, PenetrationRatio: 0.5}, defence: 50, want: 20},
+		{name: "penetration doesn't make defence negative", hit: Hit{Penetration: 80}, defence: 50, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hit.defenceAgainst(tt.defence); got != tt.want {
+				t.Errorf("defenceAgainst() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlayer_DefendAttack_Sunder(t *testing.T) {
+	p := NewPlayer("Knight", PlayerStats{Health: 100, Defence: 40}, PlayerSkills{})
+
+	attack := &Attack{Hits: []Hit{NewHit(50), NewHit(50)}}
+	attack.Hits[0].Sunder = 10
+	attack.Hits[1].Sunder = 10
+	p.DefendAttack(attack)
+
+	// the first hit does 10 damage, the second one 20 since the defence was sundered
+	if p.Health != 70 || p.EffectiveDefence() != 20 || p.Defence != 40 {
+		t.Errorf("DefendAttack() left %.2f health and %.2f defence, want 70 health and 20 defence", p.Health, p.EffectiveDefence())
+	}
+
+	evaded := &Attack{Hits: []Hit{{Sunder: 10}}}
+	p.DefendAttack(evaded)
+	if p.EffectiveDefence() != 20 {
+		t.Errorf("DefendAttack() shouldn't sunder armor with evaded hits; got %.2f defence", p.EffectiveDefence())
+	}
+
+	p.prepareForDuel(nil)
+	if p.EffectiveDefence() != 40 {
+		t.Errorf("prepareForDuel() should restore the sundered defence; got %.2f", p.EffectiveDefence())
+	}
+}
@@ -0,0 +1,238 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// EffectType represents what an effect of a declarative skill does
+type EffectType string
+
+// the supported effects; the amount of every effect means:
+//   - AddHit: the number of hits of the player's strength added (1 when not set)
+//   - MultiplyDamage: the factor the potential damage of every hit is multiplied by
+//   - ReduceDamage: the percentage of the potential damage of every hit blocked
+//   - ApplyStatus: not used, the Status is added to every hit
+//   - Heal: the percentage of the player's starting health recovered
+const (
+	AddHit         EffectType = "addHit"
+	MultiplyDamage EffectType = "multiplyDamage"
+	ReduceDamage   EffectType = "reduceDamage"
+	ApplyStatus    EffectType = "applyStatus"
+	Heal           EffectType = "heal"
+)
+
+// EffectSpec describes an effect of a declarative skill;
+// Chance is the chance of the effect once the skill fired
+// (always applied when not set)
+type EffectSpec struct {
+	Type   EffectType `json:"type"`
+	Chance *float64   `json:"chance,omitempty"`
+	Amount float64    `json:"amount,omitempty"`
+	Status *Status    `json:"status,omitempty"`
+}
+
+// Condition describes when a declarative skill may fire;
+// see Triggered for the meaning of the fields
+type Condition struct {
+	On          Event   `json:"on,omitempty"`
+	HealthBelow float64 `json:"healthBelow,omitempty"`
+	Round       int     `json:"round,omitempty"`
+}
+
+// SkillSpec describes a skill without Go code; once its conditions
+// are met the skill fires with the given Chance (always when not set)
+// and applies its effects in order. Skills that fired skip the next
+// Recovery attacks going through the pipe. The name is reported in
+// the used offensive skills or, for defensive skills, in the used
+// defensive skills of the attack
+type SkillSpec struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Defensive   bool         `json:"defensive,omitempty"`
+	When        Condition    `json:"when,omitempty"`
+	Chance      *float64     `json:"chance,omitempty"`
+	Recovery    int          `json:"recovery,omitempty"`
+	Priority    int          `json:"priority,omitempty"`
+	Effects     []EffectSpec `json:"effects"`
+}
+
+// Validate checks that the skill can be compiled
+func (spec SkillSpec) Validate() error {
+	if spec.Name == "" {
+		return fmt.Errorf("skill without name")
+	}
+	if len(spec.Effects) == 0 {
+		return fmt.Errorf("skill %q has no effects", spec.Name)
+	}
+	if err := spec.When.On.validate(); err != nil {
+		return fmt.Errorf("skill %q: %v", spec.Name, err)
+	}
+	if spec.When.On == OnAttack && spec.Defensive || spec.When.On == OnDefend && !spec.Defensive {
+		return fmt.Errorf("skill %q fires on %s, out of its pipe", spec.Name, spec.When.On)
+	}
+
+	for i, effect := range spec.Effects {
+		switch effect.Type {
+		case AddHit, MultiplyDamage, ReduceDamage, Heal:
+		case ApplyStatus:
+			if effect.Status == nil {
+				return fmt.Errorf("effect %d of skill %q applies no status", i+1, spec.Name)
+			}
+		default:
+			return fmt.Errorf("effect %d of skill %q has unknown type %q", i+1, spec.Name, effect.Type)
+		}
+	}
+	return nil
+}
+
+// Compile turns the description into a skill
+func (spec SkillSpec) Compile() (Skill, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &Declarative{Spec: spec}, nil
+}
+
+// Declarative is a skill described by a SkillSpec
+type Declarative struct {
+	Spec SkillSpec
+}
+
+// GetDescription returns the long description of the skill
+func (d *Declarative) GetDescription() string {
+	if d.Spec.Description != "" {
+		return d.Spec.Description
+	}
+	return d.Spec.Name
+}
+
+func (d *Declarative) priority() int {
+	return d.Spec.Priority
+}
+
+// chance checks whether something with the given chance happens
+func chance(c *float64) bool {
+	return c == nil || rand.Float64() < *c
+}
+
+// GetModifier compiles the effects into an attack modifier
+func (d *Declarative) GetModifier(player *Player) AttackModifier {
+	state := player.newSkillState()
+	modifier := func(attack *Attack) *Attack {
+		if state.Recovering > 0 {
+			state.Recovering--
+			return attack
+		}
+		if !chance(d.Spec.Chance) {
+			return attack
+		}
+
+		state.Recovering = d.Spec.Recovery
+		for _, effect := range d.Spec.Effects {
+			if chance(effect.Chance) {
+				d.apply(effect, player, attack)
+			}
+		}
+
+		if d.Spec.Defensive {
+			attack.UsedDefensiveSkills = append(attack.UsedDefensiveSkills, d.Spec.Name)
+		} else {
+			attack.UsedOffensiveSkills = append(attack.UsedOffensiveSkills, d.Spec.Name)
+		}
+		return attack
+	}
+
+	when := d.Spec.When
+	if when == (Condition{}) {
+		return modifier
+	}
+	triggered := &Triggered{
+		Skill:       skillFunc(modifier),
+		On:          when.On,
+		HealthBelow: when.HealthBelow,
+		Round:       when.Round,
+	}
+	return triggered.modifier(player, state)
+}
+
+func (d *Declarative) apply(effect EffectSpec, player *Player, attack *Attack) {
+	switch effect.Type {
+	case AddHit:
+		count := int(effect.Amount)
+		if count < 1 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			attack.Hits = append(attack.Hits, NewHit(player.Strength))
+		}
+	case MultiplyDamage:
+		for i := range attack.Hits {
+			attack.Hits[i].Scale(effect.Amount)
+		}
+	case ReduceDamage:
+		for i := range attack.Hits {
+			attack.Hits[i].Reduce(effect.Amount)
+		}
+	case ApplyStatus:
+		for i := range attack.Hits {
+			attack.Hits[i].Statuses = append(attack.Hits[i].Statuses, *effect.Status)
+		}
+	case Heal:
+		player.Heal(effect.Amount)
+	}
+}
+
+// MarshalJSON encodes the description of the skill
+func (d *Declarative) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Spec)
+}
+
+// UnmarshalJSON decodes and validates the description of the skill
+func (d *Declarative) UnmarshalJSON(data []byte) error {
+	var spec SkillSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	d.Spec = spec
+	return nil
+}
+
+// skillFunc turns an attack modifier into a skill
+type skillFunc AttackModifier
+
+func (sf skillFunc) GetDescription() string {
+	return ""
+}
+
+func (sf skillFunc) GetModifier(*Player) AttackModifier {
+	return AttackModifier(sf)
+}
+
+// LoadSkills reads a list of skill descriptions
+// and returns the compiled skills by name;
+// the names must be unique
+func LoadSkills(r io.Reader) (map[string]Skill, error) {
+	specs := []SkillSpec{}
+	if err := json.NewDecoder(r).Decode(&specs); err != nil {
+		return nil, err
+	}
+
+	skills := map[string]Skill{}
+	for _, spec := range specs {
+		skill, err := spec.Compile()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := skills[spec.Name]; ok {
+			return nil, fmt.Errorf("skill %q is defined more than once", spec.Name)
+		}
+		skills[spec.Name] = skill
+	}
+	return skills, nil
+}
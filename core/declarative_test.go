@@ -0,0 +1,199 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeclarative_GetModifier(t *testing.T) {
+	always, never := 1.0, 0.0
+	burning := Status{Name: "Burning", Turns: 2, Type: Fire, Damage: 5}
+
+	tests := []struct {
+		name   string
+		spec   SkillSpec
+		health float64
+		want   []*Attack
+	}{
+		{
+			name: "adds hits like Critical Strike",
+			spec: SkillSpec{Name: "Crit", Effects: []EffectSpec{{Type: AddHit}, {Type: AddHit, Chance: &never}}},
+			want: []*Attack{{
+				Hits:                []Hit{NewHit(50), NewHit(50)},
+				UsedOffensiveSkills: []string{"Crit"},
+				UsedDefensiveSkills: []string{},
+			}},
+		},
+		{
+			name: "doesn't fire without luck",
+			spec: SkillSpec{Name: "Crit", Chance: &never, Effects: []EffectSpec{{Type: AddHit, Amount: 2}}},
+			want: []*Attack{{
+				Hits:                []Hit{NewHit(50)},
+				UsedOffensiveSkills: []string{},
+				UsedDefensiveSkills: []string{},
+			}},
+		},
+		{
+			name: "multiplies the damage",
+			spec: SkillSpec{Name: "Rage", Chance: &always, Effects: []EffectSpec{{Type: MultiplyDamage, Amount: 1.5}}},
+			want: []*Attack{{
+				Hits:                []Hit{NewHit(75)},
+				UsedOffensiveSkills: []string{"Rage"},
+				UsedDefensiveSkills: []string{},
+			}},
+		},
+		{
+			name: "reduces the damage like Resilience",
+			spec: SkillSpec{Name: "Block", Defensive: true, Recovery: 1, Effects: []EffectSpec{{Type: ReduceDamage, Amount: 0.5}}},
+			want: []*Attack{
+				{
+					Hits:                []Hit{NewHit(25)},
+					UsedOffensiveSkills: []string{},
+					UsedDefensiveSkills: []string{"Block"},
+				},
+				{
+					Hits:                []Hit{NewHit(50)},
+					UsedOffensiveSkills: []string{},
+					UsedDefensiveSkills: []string{},
+				},
+				{
+					Hits:                []Hit{NewHit(25)},
+					UsedOffensiveSkills: []string{},
+					UsedDefensiveSkills: []string{"Block"},
+				},
+			},
+		},
+		{
+			name: "applies a status to the hits",
+			spec: SkillSpec{Name: "Ignite", Effects: []EffectSpec{{Type: ApplyStatus, Status: &burning}}},
+			want: []*Attack{{
+				Hits: []Hit{{
+					PotentialDamage:     50,
+					Statuses:            []Status{burning},
+					UsedOffensiveSkills: []string{},
+					UsedDefensiveSkills: []string{},
+				}},
+				UsedOffensiveSkills: []string{"Ignite"},
+				UsedDefensiveSkills: []string{},
+			}},
+		},
+		{
+			name:   "fires only when the conditions are met",
+			spec:   SkillSpec{Name: "Last Stand", When: Condition{HealthBelow: 0.5}, Effects: []EffectSpec{{Type: AddHit}}},
+			health: 80,
+			want: []*Attack{{
+				Hits:                []Hit{NewHit(50)},
+				UsedOffensiveSkills: []string{},
+				UsedDefensiveSkills: []string{},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer("Designer", PlayerStats{Health: 100, Strength: 50}, PlayerSkills{})
+			p.prepareForDuel(nil)
+			if tt.health > 0 {
+				p.Health = tt.health
+			}
+
+			skill, err := tt.spec.Compile()
+			if err != nil {
+				t.Fatalf("Compile() returned an unexpected error: %v", err)
+			}
+
+			modifier := skill.GetModifier(p)
+			for i, want := range tt.want {
+				if got := modifier(NewAttack(50)); !reflect.DeepEqual(got, want) {
+					t.Errorf("attack %d = %+v, want %+v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDeclarative_HealAndStatus(t *testing.T) {
+	hero := NewPlayer("Hero", PlayerStats{Health: 100, Strength: 20, Speed: 2}, PlayerSkills{})
+	hero.prepareForDuel(nil)
+	hero.Health = 50
+
+	heal, _ := SkillSpec{Name: "Drain", Effects: []EffectSpec{{Type: Heal, Amount: 0.1}}}.Compile()
+	heal.GetModifier(hero)(NewAttack(20))
+	if hero.Health != 60 {
+		t.Errorf("heal effect health = %.2f, want 60", hero.Health)
+	}
+
+	villain := NewPlayer("Peanut", PlayerStats{Health: 100}, PlayerSkills{})
+	villain.Resistances = Resistances{Fire: 0.5}
+	villain.prepareForDuel(nil)
+
+	attack := NewAttack(20)
+	attack.Hits[0].Statuses = []Status{{Name: "Burning", Turns: 2, Type: Fire, Damage: 10}, {Name: "Exposed", Turns: 1, Defence: -5}}
+	villain.DefendAttack(attack)
+
+	for turn, wantHealth := range []float64{75, 70, 70} {
+		villain.startTurn()
+		if villain.Health != wantHealth {
+			t.Errorf("health after %d turns = %.2f, want %.2f", turn+1, villain.Health, wantHealth)
+		}
+	}
+	if len(villain.Statuses()) != 0 {
+		t.Errorf("Statuses() = %v, want them to wear off", villain.Statuses())
+	}
+}
+
+func TestSkillSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec SkillSpec
+	}{
+		{name: "without name", spec: SkillSpec{Effects: []EffectSpec{{Type: AddHit}}}},
+		{name: "without effects", spec: SkillSpec{Name: "Nothing"}},
+		{name: "unknown effect", spec: SkillSpec{Name: "Teleport", Effects: []EffectSpec{{Type: "teleport"}}}},
+		{name: "status missing", spec: SkillSpec{Name: "Hex", Effects: []EffectSpec{{Type: ApplyStatus}}}},
+		{name: "unknown event", spec: SkillSpec{Name: "Rush", When: Condition{On: "parry"}, Effects: []EffectSpec{{Type: AddHit}}}},
+		{name: "defensive event", spec: SkillSpec{Name: "Rush", When: Condition{On: OnDefend}, Effects: []EffectSpec{{Type: AddHit}}}},
+		{name: "offensive event", spec: SkillSpec{Name: "Ward", Defensive: true, When: Condition{On: OnAttack}, Effects: []EffectSpec{{Type: ReduceDamage, Amount: 0.5}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.Validate(); err == nil {
+				t.Errorf("Validate() should return an error")
+			}
+		})
+	}
+}
+
+func TestLoadSkills(t *testing.T) {
+	config := `[
+		{"name": "Critical Strike", "chance": 0.1, "effects": [{"type": "addHit"}]},
+		{"name": "Second Wind", "defensive": true, "when": {"on": "hit"}, "effects": [{"type": "heal", "amount": 0.3}]}
+	]`
+
+	skills, err := LoadSkills(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("LoadSkills() returned an unexpected error: %v", err)
+	}
+
+	chance := 0.1
+	want := map[string]Skill{
+		"Critical Strike": &Declarative{Spec: SkillSpec{Name: "Critical Strike", Chance: &chance, Effects: []EffectSpec{{Type: AddHit}}}},
+		"Second Wind": &Declarative{Spec: SkillSpec{
+			Name: "Second Wind", Defensive: true, When: Condition{On: OnHit},
+			Effects: []EffectSpec{{Type: Heal, Amount: 0.3}},
+		}},
+	}
+	if !reflect.DeepEqual(skills, want) {
+		t.Errorf("LoadSkills() = %+v, want %+v", skills, want)
+	}
+
+	if _, err := LoadSkills(strings.NewReader(`[{"name": "Broken", "effects": [{"type": "explode"}]}]`)); err == nil {
+		t.Errorf("LoadSkills() should return an error for invalid skills")
+	}
+	duplicate := `[{"name": "Rage", "effects": [{"type": "addHit"}]}, {"name": "Rage", "effects": [{"type": "heal"}]}]`
+	if _, err := LoadSkills(strings.NewReader(duplicate)); err == nil {
+		t.Errorf("LoadSkills() should return an error for duplicate names")
+	}
+}
@@ -1,6 +1,9 @@
 package core
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Commentator represents an entity that can log / render / animate
 // every event within a duel
@@ -8,8 +11,11 @@ type Commentator interface {
 	Start()
 	PresentPlayers(first, second *Player)
 	PresentRound(int)
+	PresentResources(first, second *Player)
 	PresentAttack(attack *Attack, attacker, defender *Player)
+	PresentAction(action Action, player *Player)
 	EndDuelKnockout(round int, winner, loser *Player)
+	EndDuelFlee(round int, fled, winner *Player)
 	EndDuelTie(round int, player1, player2 *Player)
 }
 
@@ -20,10 +26,37 @@ type dummyCommentator struct {
 func (dc *dummyCommentator) Start()                                                   {}
 func (dc *dummyCommentator) PresentPlayers(first, second *Player)                     {}
 func (dc *dummyCommentator) PresentRound(int)                                         {}
+func (dc *dummyCommentator) PresentResources(first, second *Player)                   {}
 func (dc *dummyCommentator) PresentAttack(attack *Attack, attacker, defender *Player) {}
+func (dc *dummyCommentator) PresentAction(Action, *Player)                            {}
 func (dc *dummyCommentator) EndDuelKnockout(int, *Player, *Player)                    {}
+func (dc *dummyCommentator) EndDuelFlee(int, *Player, *Player)                        {}
 func (dc *dummyCommentator) EndDuelTie(int, *Player, *Player)                         {}
 
+// DuelResult represents the outcome of a duel
+// Winner and Loser are nil when the duel ended with a tie
+// or was paused
+type DuelResult struct {
+	Rounds   int
+	Knockout bool
+	// Fled is true when the loser fled the duel
+	Fled bool
+	// Paused is true when the duel was paused after Rounds rounds;
+	// it has no winner yet
+	Paused bool
+
+	First  *Player
+	Second *Player
+	Winner *Player
+	Loser  *Player
+}
+
+// IsTie checks whether the duel ended without a winner;
+// paused duels haven't ended yet
+func (dr *DuelResult) IsTie() bool {
+	return dr.Winner == nil && !dr.Paused
+}
+
 // DuelMaster contains logic for the duel
 type DuelMaster struct {
 	Rounds      int
@@ -32,6 +65,92 @@ type DuelMaster struct {
 
 	PlayerOne *Player
 	PlayerTwo *Player
+
+	// DamageFormula computes the damage of every hit;
+	// defaults to the Subtractive formula
+	DamageFormula DamageFormula
+
+	// DefendBonus is the percentage by which defending raises the
+	// defence and HealRatio the percentage of the starting health
+	// recovered by healing; they default to 50% and 20%
+	DefendBonus float64
+	HealRatio   float64
+
+	// PauseAfter, when set, pauses the duel after the given round; the
+	// players can be saved, e.g. as JSON, and the duel resumed later
+	// with ResumeDuel
+	PauseAfter int
+}
+
+func (dm *DuelMaster) defendBonus() float64 {
+	if dm.DefendBonus == 0 {
+		return 0.5
+	}
+	return dm.DefendBonus
+}
+
+func (dm *DuelMaster) healRatio() float64 {
+	if dm.HealRatio == 0 {
+		return 0.2
+	}
+	return dm.HealRatio
+}
+
+// takeTurn lets the attacker choose and take his action;
+// it returns true if the attacker fled
+func (dm *DuelMaster) takeTurn(round int, attacker, defender *Player, commentator Commentator) bool {
+	attacker.startTurn()
+	if attacker.IsDead() {
+		return false
+	}
+
+	action := Action{Kind: AttackAction}
+	if attacker.Strategy != nil {
+		action = attacker.Strategy.ChooseAction(DuelState{
+			Round:    round,
+			Rounds:   dm.Rounds,
+			Self:     attacker,
+			Opponent: defender,
+		})
+	}
+
+	var attack *Attack
+	switch action.Kind {
+	case DefendAction:
+		attacker.Defend(dm.defendBonus())
+	case HealAction:
+		attacker.Heal(dm.healRatio())
+	case FleeAction:
+		commentator.PresentAction(action, attacker)
+		return true
+	case AbilityAction:
+		if action.Ability != nil && attacker.IsReady(action.Ability) {
+			attack = attacker.GenerateAbilityAttack(action.Ability)
+			break
+		}
+		// abilities that aren't ready fall back to a plain attack
+		action = Action{Kind: AttackAction}
+		attack = attacker.GenerateAttack()
+	default:
+		attack = attacker.GenerateAttack()
+	}
+
+	if attack == nil {
+		commentator.PresentAction(action, attacker)
+		return false
+	}
+
+	health := defender.Health
+	defender.DefendAttack(attack)
+	attacker.dealtDamage(health - defender.Health)
+	if defender.IsDead() {
+		attacker.notify(OnKill)
+	}
+	if action.Kind == AbilityAction {
+		commentator.PresentAction(action, attacker)
+	}
+	commentator.PresentAttack(attack, attacker, defender)
+	return false
 }
 
 func (dm *DuelMaster) getPlayersInOrder() (*Player, *Player) {
@@ -52,51 +171,109 @@ func (dm *DuelMaster) getPlayersInOrder() (*Player, *Player) {
 	return dm.PlayerOne, dm.PlayerTwo
 }
 
+// knockout returns the winner and the loser once one of the players
+// died on the attacker's turn; the attacker can die from his statuses
+func knockout(attacker, defender *Player) (winner, loser *Player) {
+	switch {
+	case defender.IsDead():
+		return attacker, defender
+	case attacker.IsDead():
+		return defender, attacker
+	}
+	return nil, nil
+}
+
 // StartDuel contains the logic for the duel between 2 combatants
-func (dm *DuelMaster) StartDuel(c ...Commentator) {
+// and returns its outcome
+func (dm *DuelMaster) StartDuel(c ...Commentator) *DuelResult {
+	player1, player2 := dm.getPlayersInOrder()
+	player1.prepareForDuel(dm.DamageFormula)
+	player2.prepareForDuel(dm.DamageFormula)
+	return dm.fight(0, c)
+}
+
+// ResumeDuel continues a duel paused after the given round; the players
+// keep the state they had when the duel was paused, e.g. once decoded
+// from JSON. The commentator continues commenting the paused duel from
+// the next round on, so the duel isn't started and the players aren't
+// presented again. The round must be the one both players were paused
+// after and must leave rounds to fight
+func (dm *DuelMaster) ResumeDuel(round int, c ...Commentator) (*DuelResult, error) {
+	player1, player2 := dm.getPlayersInOrder()
+	if round < 1 || round >= dm.Rounds {
+		return nil, fmt.Errorf("a duel of %d rounds can't be resumed after round %d", dm.Rounds, round)
+	}
+	if player1.round != round || player2.round != round {
+		return nil, fmt.Errorf("%s and %s were paused after rounds %d and %d, not after round %d",
+			player1.Name, player2.Name, player1.round, player2.round, round)
+	}
 
+	player1.formula, player2.formula = dm.DamageFormula, dm.DamageFormula
+	return dm.fight(round, c), nil
+}
+
+// fight fights the rounds after the given one; new duels,
+// fought from round 0, are announced to the commentator
+func (dm *DuelMaster) fight(round int, c []Commentator) *DuelResult {
 	var commentator Commentator = &dummyCommentator{}
 	if len(c) > 0 {
 		commentator = c[0]
 	}
 
 	player1, player2 := dm.getPlayersInOrder()
+	player1.opponent, player2.opponent = player2, player1
+
+	if round == 0 {
+		commentator.Start()
+		commentator.PresentPlayers(player1, player2)
+	}
 
-	commentator.Start()
-	commentator.PresentPlayers(player1, player2)
+	result := &DuelResult{First: player1, Second: player2}
 
-	var round int
-	var knockout bool
-	for i := 1; i <= dm.Rounds; i++ {
+	for i := round + 1; i <= dm.Rounds; i++ {
 		time.Sleep(dm.RoundsDelay)
 
 		round = i
+		player1.round, player2.round = round, round
 		commentator.PresentRound(round)
-		attack := player1.GenerateAttack()
-		player2.DefendAttack(attack)
+		commentator.PresentResources(player1, player2)
 
-		commentator.PresentAttack(attack, player1, player2)
+		if dm.takeTurn(round, player1, player2, commentator) {
+			commentator.EndDuelFlee(round, player1, player2)
+			result.Fled, result.Winner, result.Loser = true, player2, player1
+			break
+		}
 
-		if player2.IsDead() {
-			commentator.EndDuelKnockout(round, player1, player2)
-			knockout = true
+		if winner, loser := knockout(player1, player2); loser != nil {
+			commentator.EndDuelKnockout(round, winner, loser)
+			result.Knockout, result.Winner, result.Loser = true, winner, loser
 			break
 		}
 
 		time.Sleep(dm.AttackDelay)
 
-		attack = player2.GenerateAttack()
-		player1.DefendAttack(attack)
+		if dm.takeTurn(round, player2, player1, commentator) {
+			commentator.EndDuelFlee(round, player2, player1)
+			result.Fled, result.Winner, result.Loser = true, player1, player2
+			break
+		}
+
+		if winner, loser := knockout(player2, player1); loser != nil {
+			commentator.EndDuelKnockout(round, winner, loser)
+			result.Knockout, result.Winner, result.Loser = true, winner, loser
+			break
+		}
 
-		commentator.PresentAttack(attack, player2, player1)
-		if player1.IsDead() {
-			commentator.EndDuelKnockout(round, player2, player1)
-			knockout = true
+		if round == dm.PauseAfter && round < dm.Rounds {
+			result.Paused = true
 			break
 		}
 	}
 
-	if !knockout {
+	result.Rounds = round
+	if result.IsTie() {
 		commentator.EndDuelTie(round, player1, player2)
 	}
+
+	return result
 }
@@ -62,6 +62,7 @@ func TestDuelMaster_StartDuel(t *testing.T) {
 		args          args
 		player1Health float64
 		player2Health float64
+		winner        string
 	}{
 		{
 			name: "It should end if the first player knockouts second",
@@ -85,6 +86,7 @@ func TestDuelMaster_StartDuel(t *testing.T) {
 			args:          args{commentator: &dummyCommentator{}},
 			player1Health: 100,
 			player2Health: 0,
+			winner:        "Winner",
 		},
 		{
 			name: "It should end if the second player knockouts first",
@@ -108,6 +110,7 @@ func TestDuelMaster_StartDuel(t *testing.T) {
 			args:          args{commentator: &dummyCommentator{}},
 			player1Health: 0,
 			player2Health: 100,
+			winner:        "Loser",
 		},
 		{
 			name: "It should end after the number of rounds specified",
@@ -136,13 +139,24 @@ func TestDuelMaster_StartDuel(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.dm.StartDuel(tt.args.commentator)
+			result := tt.dm.StartDuel(tt.args.commentator)
 			if tt.dm.PlayerOne.Health != tt.player1Health {
 				t.Errorf("Expected first player's health to be %.2f but got %.2f", tt.player1Health, tt.dm.PlayerOne.Health)
 			}
 			if tt.dm.PlayerTwo.Health != tt.player2Health {
 				t.Errorf("Expected second player's health to be %.2f but got %.2f", tt.player2Health, tt.dm.PlayerTwo.Health)
 			}
+
+			winner := ""
+			if result.Winner != nil {
+				winner = result.Winner.Name
+			}
+			if winner != tt.winner {
+				t.Errorf("Expected the winner to be %q but got %q", tt.winner, winner)
+			}
+			if result.IsTie() != (tt.winner == "") {
+				t.Errorf("Expected tie to be %v but got %v", tt.winner == "", result.IsTie())
+			}
 		})
 	}
 }
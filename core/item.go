@@ -0,0 +1,209 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Slot represents the place where an item is equipped;
+// a player can wear a single item in every slot
+type Slot string
+
+// the common equipment slots
+const (
+	Weapon  Slot = "weapon"
+	Armor   Slot = "armor"
+	Trinket Slot = "trinket"
+)
+
+// Item represents a piece of equipment; it adds flat and percentage
+// (0.1 means +10%) bonuses to the player's stats and can come with
+// its own skills, chained after the player's skills
+type Item struct {
+	Name    string
+	Slot    Slot
+	Flat    PlayerStats
+	Percent PlayerStats
+
+	PlayerSkills
+}
+
+// GetDescription returns the long description of the item
+func (it *Item) GetDescription() string {
+	bonuses := []string{}
+	stats := []struct {
+		name          string
+		flat, percent float64
+	}{
+		{"Health", it.Flat.Health, it.Percent.Health},
+		{"Strength", it.Flat.Strength, it.Percent.Strength},
+		{"Defence", it.Flat.Defence, it.Percent.Defence},
+		{"Speed", it.Flat.Speed, it.Percent.Speed},
+		{"Luck", it.Flat.Luck, it.Percent.Luck},
+		{"Accuracy", it.Flat.Accuracy, it.Percent.Accuracy},
+		{"Evasion", it.Flat.Evasion, it.Percent.Evasion},
+		{"Crit Chance", it.Flat.CritChance, it.Percent.CritChance},
+		{"Crit Multiplier", it.Flat.CritMultiplier, it.Percent.CritMultiplier},
+	}
+	for _, stat := range stats {
+		if stat.flat != 0 {
+			bonuses = append(bonuses, fmt.Sprintf("%+.2f %s", stat.flat, stat.name))
+		}
+		if stat.percent != 0 {
+			bonuses = append(bonuses, fmt.Sprintf("%+.2f%% %s", stat.percent*100, stat.name))
+		}
+	}
+
+	for _, skill := range append(append([]Skill{}, it.OffensiveSkills...), it.DefensiveSkills...) {
+		bonuses = append(bonuses, skill.GetDescription())
+	}
+
+	return fmt.Sprintf("%s (%s: %s)", it.Name, it.Slot, strings.Join(bonuses, ", "))
+}
+
+// bonus returns the stats added by the item to the given stats
+func (it *Item) bonus(stats PlayerStats) PlayerStats {
+	return PlayerStats{
+		Health:   it.Flat.Health + it.Percent.Health*stats.Health,
+		Strength: it.Flat.Strength + it.Percent.Strength*stats.Strength,
+		Defence:  it.Flat.Defence + it.Percent.Defence*stats.Defence,
+		Speed:    it.Flat.Speed + it.Percent.Speed*stats.Speed,
+		Luck:     it.Flat.Luck + it.Percent.Luck*stats.Luck,
+
+		Accuracy:       it.Flat.Accuracy + it.Percent.Accuracy*stats.Accuracy,
+		Evasion:        it.Flat.Evasion + it.Percent.Evasion*stats.Evasion,
+		CritChance:     it.Flat.CritChance + it.Percent.CritChance*stats.CritChance,
+		CritMultiplier: it.Flat.CritMultiplier + it.Percent.CritMultiplier*stats.CritMultiplier,
+	}
+}
+
+func (ps *PlayerStats) add(other PlayerStats, sign float64) {
+	ps.Health += sign * other.Health
+	ps.Strength += sign * other.Strength
+	ps.Defence += sign * other.Defence
+	ps.Speed += sign * other.Speed
+	ps.Luck += sign * other.Luck
+	ps.Accuracy += sign * other.Accuracy
+	ps.Evasion += sign * other.Evasion
+	ps.CritChance += sign * other.CritChance
+	ps.CritMultiplier += sign * other.CritMultiplier
+}
+
+// equipped is an item worn by a player together
+// with the stats it added when it was equipped
+type equipped struct {
+	item  *Item
+	bonus PlayerStats
+}
+
+// Equip equips the item, replacing the one worn in the same slot;
+// percentage bonuses apply to the stats the player has when equipping
+// the item. It returns the replaced item, if any
+func (p *Player) Equip(item *Item) *Item {
+	previous := p.unequip(item.Slot)
+
+	bonus := item.bonus(p.PlayerStats)
+	p.PlayerStats.add(bonus, 1)
+	p.equipment = append(p.equipment, equipped{item: item, bonus: bonus})
+
+	p.buildAttackModifiers()
+	return previous
+}
+
+// Unequip removes the item worn in the given slot together with its
+// bonuses and returns it; it returns nil if the slot is empty. Losing
+// the health bonus leaves the player with at least 1 health (or the
+// health he had, if lower) since taking off an item never knocks him out
+func (p *Player) Unequip(slot Slot) *Item {
+	item := p.unequip(slot)
+	if item != nil {
+		p.buildAttackModifiers()
+	}
+	return item
+}
+
+func (p *Player) unequip(slot Slot) *Item {
+	for i, e := range p.equipment {
+		if e.item.Slot == slot {
+			health := p.Health
+			p.PlayerStats.add(e.bonus, -1)
+			if health > 0 && p.Health < 1 {
+				p.Health = math.Min(1, health)
+			}
+			p.equipment = append(p.equipment[:i:i], p.equipment[i+1:]...)
+			return e.item
+		}
+	}
+	return nil
+}
+
+// Equipment returns the items worn by the player in the order they were equipped
+func (p *Player) Equipment() []*Item {
+	items := []*Item{}
+	for _, e := range p.equipment {
+		items = append(items, e.item)
+	}
+	return items
+}
+
+// ItemConfig represents an item within configuration files
+type ItemConfig struct {
+	Name            string        `json:"name"`
+	Slot            Slot          `json:"slot"`
+	Flat            PlayerStats   `json:"flat"`
+	Percent         PlayerStats   `json:"percent"`
+	OffensiveSkills []SkillConfig `json:"offensiveSkills"`
+	DefensiveSkills []SkillConfig `json:"defensiveSkills"`
+}
+
+// Build creates the configured item
+func (ic ItemConfig) Build() (*Item, error) {
+	if ic.Name == "" || ic.Slot == "" {
+		return nil, fmt.Errorf("items need a name and a slot; got %q in slot %q", ic.Name, ic.Slot)
+	}
+
+	offensiveSkills, err := BuildSkills(ic.OffensiveSkills)
+	if err != nil {
+		return nil, fmt.Errorf("item %q: %v", ic.Name, err)
+	}
+	defensiveSkills, err := BuildSkills(ic.DefensiveSkills)
+	if err != nil {
+		return nil, fmt.Errorf("item %q: %v", ic.Name, err)
+	}
+
+	return &Item{
+		Name:    ic.Name,
+		Slot:    ic.Slot,
+		Flat:    ic.Flat,
+		Percent: ic.Percent,
+		PlayerSkills: PlayerSkills{
+			OffensiveSkills: offensiveSkills,
+			DefensiveSkills: defensiveSkills,
+		},
+	}, nil
+}
+
+// LoadItems reads a JSON list of item configurations and
+// returns the items indexed by name
+func LoadItems(r io.Reader) (map[string]*Item, error) {
+	configs := []ItemConfig{}
+	if err := json.NewDecoder(r).Decode(&configs); err != nil {
+		return nil, err
+	}
+
+	items := map[string]*Item{}
+	for _, config := range configs {
+		item, err := config.Build()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := items[item.Name]; ok {
+			return nil, fmt.Errorf("item %q is defined more than once", item.Name)
+		}
+		items[item.Name] = item
+	}
+	return items, nil
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlayer_Equip(t *testing.T) {
+	sword := &Item{Name: "Sword", Slot: Weapon, Flat: PlayerStats{Strength: 10}, Percent: PlayerStats{Speed: 0.5}}
+	axe := &Item{Name: "Axe", Slot: Weapon, Flat: PlayerStats{Strength: 20}}
+	mail := &Item{Name: "Mail", Slot: Armor, Percent: PlayerStats{Defence: 0.1, Health: 0.2}}
+
+	tests := []struct {
+		name          string
+		items         []*Item
+		wantStats     PlayerStats
+		wantEquipment []*Item
+	}{
+		{
+			name:          "adds flat and percentage bonuses",
+			items:         []*Item{sword, mail},
+			wantStats:     PlayerStats{Health: 120, Strength: 60, Defence: 44, Speed: 15},
+			wantEquipment: []*Item{sword, mail},
+		},
+		{
+			name:          "replaces the item worn in the same slot",
+			items:         []*Item{sword, mail, axe},
+			wantStats:     PlayerStats{Health: 120, Strength: 70, Defence: 44, Speed: 10},
+			wantEquipment: []*Item{mail, axe},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer("Hero", PlayerStats{Health: 100, Strength: 50, Defence: 40, Speed: 10}, PlayerSkills{})
+			for _, item := range tt.items {
+				p.Equip(item)
+			}
+			if !reflect.DeepEqual(p.PlayerStats, tt.wantStats) {
+				t.Errorf("Equip() stats = %+v, want %+v", p.PlayerStats, tt.wantStats)
+			}
+			if !reflect.DeepEqual(p.Equipment(), tt.wantEquipment) {
+				t.Errorf("Equipment() = %v, want %v", p.Equipment(), tt.wantEquipment)
+			}
+		})
+	}
+}
+
+func TestPlayer_Unequip(t *testing.T) {
+	stats := PlayerStats{Health: 100, Strength: 50, Defence: 40, Speed: 10}
+	p := NewPlayer("Hero", stats, PlayerSkills{})
+	sword := &Item{Name: "Sword", Slot: Weapon, Flat: PlayerStats{Strength: 10}, Percent: PlayerStats{Speed: 0.5}}
+
+	p.Equip(sword)
+	if got := p.Unequip(Weapon); got != sword {
+		t.Errorf("Unequip() = %v, want %v", got, sword)
+	}
+	if got := p.Unequip(Weapon); got != nil {
+		t.Errorf("Unequip() on an empty slot = %v, want nil", got)
+	}
+	if !reflect.DeepEqual(p.PlayerStats, stats) {
+		t.Errorf("Unequip() stats = %+v, want %+v", p.PlayerStats, stats)
+	}
+
+	// the hero took 110 damage wearing the mail
+	p.Equip(&Item{Name: "Mail", Slot: Armor, Flat: PlayerStats{Health: 20}, Percent: PlayerStats{Health: 0.1}})
+	p.Health = 20
+	p.Unequip(Armor)
+	if p.Health != 1 {
+		t.Errorf("Unequip() left %.2f health, want 1", p.Health)
+	}
+	p.Equip(&Item{Name: "Amulet", Slot: Trinket, Flat: PlayerStats{Health: 5}})
+	p.Health = 0.5
+	p.Unequip(Trinket)
+	if p.Health != 0.5 {
+		t.Errorf("Unequip() left %.2f health, want 0.5", p.Health)
+	}
+}
+
+func TestPlayer_Equip_Skills(t *testing.T) {
+	p := NewPlayer("Hero", PlayerStats{Health: 100, Strength: 50}, PlayerSkills{})
+	p.Equip(&Item{
+		Name:         "Twin Blades",
+		Slot:         Weapon,
+		PlayerSkills: PlayerSkills{OffensiveSkills: []Skill{&CriticalStrike{DoubleStrikeChance: 1}}},
+	})
+	p.Equip(&Item{
+		Name:         "Tower Shield",
+		Slot:         Armor,
+		PlayerSkills: PlayerSkills{DefensiveSkills: []Skill{&Resilience{Chance: 1, DamageReduction: 0.5}}},
+	})
+
+	attack := p.GenerateAttack()
+	if len(attack.Hits) != 2 {
+		t.Errorf("GenerateAttack() should use the item's skills; got %d hits", len(attack.Hits))
+	}
+
+	p.DefendAttack(NewAttack(100))
+	if p.Health != 50 {
+		t.Errorf("DefendAttack() should use the item's skills; got %.2f health", p.Health)
+	}
+
+	p.Unequip(Weapon)
+	if attack := p.GenerateAttack(); len(attack.Hits) != 1 {
+		t.Errorf("Unequip() should remove the item's skills; got %d hits", len(attack.Hits))
+	}
+}
+
+func TestItem_GetDescription(t *testing.T) {
+	item := &Item{
+		Name:         "Sword",
+		Slot:         Weapon,
+		Flat:         PlayerStats{Strength: 10},
+		Percent:      PlayerStats{Speed: 0.05},
+		PlayerSkills: PlayerSkills{OffensiveSkills: []Skill{&CriticalStrike{DoubleStrikeChance: 0.1}}},
+	}
+
+	want := "Sword (weapon: +10.00 Strength, +5.00% Speed, Critical Strike(10.00% chance for 2x; 0.00% chance for 3x))"
+	if got := item.GetDescription(); got != want {
+		t.Errorf("GetDescription() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadItems(t *testing.T) {
+	config := `[
+		{"name": "Sword", "slot": "weapon", "flat": {"strength": 5}, "offensiveSkills": [{"type": "CriticalStrike", "params": {"DoubleStrikeChance": 0.1}}]},
+		{"name": "Lucky Charm", "slot": "trinket", "percent": {"luck": 0.25}}
+	]`
+
+	items, err := LoadItems(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("LoadItems() returned an unexpected error: %v", err)
+	}
+
+	want := map[string]*Item{
+		"Sword": {
+			Name:         "Sword",
+			Slot:         Weapon,
+			Flat:         PlayerStats{Strength: 5},
+			PlayerSkills: PlayerSkills{OffensiveSkills: []Skill{&CriticalStrike{DoubleStrikeChance: 0.1}}, DefensiveSkills: []Skill{}},
+		},
+		"Lucky Charm": {
+			Name:         "Lucky Charm",
+			Slot:         Trinket,
+			Percent:      PlayerStats{Luck: 0.25},
+			PlayerSkills: PlayerSkills{OffensiveSkills: []Skill{}, DefensiveSkills: []Skill{}},
+		},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("LoadItems() = %+v, want %+v", items, want)
+	}
+}
+
+func TestLoadItems_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{name: "invalid JSON", config: `[{`},
+		{name: "missing slot", config: `[{"name": "Sword"}]`},
+		{name: "unknown skill", config: `[{"name": "Sword", "slot": "weapon", "offensiveSkills": [{"type": "Fireball"}]}]`},
+		{name: "duplicate items", config: `[{"name": "Sword", "slot": "weapon"}, {"name": "Sword", "slot": "weapon"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadItems(strings.NewReader(tt.config)); err == nil {
+				t.Errorf("LoadItems() should return an error")
+			}
+		})
+	}
+}
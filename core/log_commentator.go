@@ -27,18 +27,26 @@ func (lc *LogsCommentator) getPlayerPresentation(p *Player) string {
 	}
 
 	playerPresentation := fmt.Sprintf(`
-		%s has the following stats:
+		%s (level %d) has the following stats:
 		Health: %.2f
 		Strength: %.2f
 		Defence: %.2f
 		Speed: %.2f
 		Luck: %.2f%%
+		Accuracy: %.2f%%
+		Evasion: %.2f%%
+		Critical hits: %.2f%% chance for %.2fx damage
 	`, p.Name,
+		p.Level,
 		p.Health,
 		p.Strength,
 		p.Defence,
 		p.Speed,
 		p.Luck*100,
+		p.Accuracy*100,
+		p.Evasion*100,
+		p.CritChance*100,
+		p.critMultiplier(),
 	)
 
 	if len(offensiveSkills) > 0 || len(defensiveSkills) > 0 {
@@ -53,6 +61,26 @@ func (lc *LogsCommentator) getPlayerPresentation(p *Player) string {
 		playerPresentation = playerPresentation + fmt.Sprintf("Defensive: %s\n", strings.Join(defensiveSkills, ", "))
 	}
 
+	pools := []string{}
+	for _, r := range ResourceTypes {
+		if pool, ok := p.Pools[r]; ok {
+			pools = append(pools, fmt.Sprintf("%.2f / %.2f %s", p.Resource(r), pool.Max, r))
+		}
+	}
+
+	if len(pools) > 0 {
+		playerPresentation = playerPresentation + fmt.Sprintf("Resources: %s\n", strings.Join(pools, ", "))
+	}
+
+	items := []string{}
+	for _, item := range p.Equipment() {
+		items = append(items, item.GetDescription())
+	}
+
+	if len(items) > 0 {
+		playerPresentation = playerPresentation + fmt.Sprintf("Equipment: %s\n", strings.Join(items, ", "))
+	}
+
 	return playerPresentation
 }
 
@@ -71,6 +99,22 @@ func (lc *LogsCommentator) PresentRound(round int) {
 	log.Printf("\n\nRound %d! Start!", round)
 }
 
+// PresentResources presents the resources left to the
+// players using resources at the start of the round
+func (lc *LogsCommentator) PresentResources(first, second *Player) {
+	for _, p := range []*Player{first, second} {
+		resources := []string{}
+		for _, r := range ResourceTypes {
+			if _, ok := p.Pools[r]; ok {
+				resources = append(resources, fmt.Sprintf("%.2f %s", p.Resource(r), r))
+			}
+		}
+		if len(resources) > 0 {
+			log.Printf("%s has %s\n", p.Name, strings.Join(resources, ", "))
+		}
+	}
+}
+
 // PresentAttack presents an attack between 2 opponents
 func (lc *LogsCommentator) PresentAttack(attack *Attack, attacker, defender *Player) {
 	hits := "hits"
@@ -81,7 +125,15 @@ func (lc *LogsCommentator) PresentAttack(attack *Attack, attacker, defender *Pla
 	log.Printf("%s attacks %s. The attack contained %d %s\n", attacker.Name, defender.Name, len(attack.Hits), hits)
 
 	for i, hit := range attack.Hits {
-		log.Printf("Hit %d with %.2f potential damage on %s\n", i+1, hit.PotentialDamage, defender.Name)
+		log.Printf("Hit %d with %.2f potential damage on %s\n", i+1, hit.TotalPotentialDamage(), defender.Name)
+		if len(hit.Damage) > 0 {
+			log.Printf("%s took %.2f damage (%s)\n", defender.Name, hit.Damage.Total(), hit.Damage)
+		}
+		if hit.TotalPotentialDamage() > 0 {
+			for _, status := range hit.Statuses {
+				log.Printf("%s is afflicted by %s\n", defender.Name, status)
+			}
+		}
 		usedOffensiveSkills := strings.Join(hit.UsedOffensiveSkills, ", ")
 		usedDefensiveSkills := strings.Join(hit.UsedDefensiveSkills, ", ")
 		if usedOffensiveSkills != "" {
@@ -100,9 +152,36 @@ func (lc *LogsCommentator) PresentAttack(attack *Attack, attacker, defender *Pla
 		log.Printf("%s used the following defensive skills on this attack: %s\n", defender.Name, strings.Join(attack.UsedDefensiveSkills, ", "))
 	}
 
+	if defender.sundered > 0 {
+		log.Printf("%s's armor is sundered, %.2f defence left\n", defender.Name, defender.EffectiveDefence())
+	}
+
 	log.Printf("%s has %.2f remaining health\n\n", defender.Name, defender.Health)
 }
 
+// PresentAction presents the action a player took instead of a plain attack
+func (lc *LogsCommentator) PresentAction(action Action, player *Player) {
+	switch action.Kind {
+	case AbilityAction:
+		if action.Ability.Cost.Amount > 0 {
+			log.Printf("%s uses %s for %s\n", player.Name, action.Ability.Skill.GetDescription(), action.Ability.Cost)
+		} else {
+			log.Printf("%s uses %s\n", player.Name, action.Ability.Skill.GetDescription())
+		}
+	case DefendAction:
+		log.Printf("%s raises his guard, his defence is %.2f until his next turn\n\n", player.Name, player.EffectiveDefence())
+	case HealAction:
+		log.Printf("%s heals and has %.2f health\n\n", player.Name, player.Health)
+	case FleeAction:
+		log.Printf("%s flees the duel!\n", player.Name)
+	}
+}
+
+// EndDuelFlee ends the duel in case a player fled
+func (lc *LogsCommentator) EndDuelFlee(round int, fled, winner *Player) {
+	log.Printf("%s fled in round %d! Congratulations to %s for winning the duel\n", fled.Name, round, winner.Name)
+}
+
 // EndDuelKnockout ends the duel in case a knockout was registered
 func (lc *LogsCommentator) EndDuelKnockout(round int, winner, loser *Player) {
 	log.Printf("Knockout in round %d!! %s is dead! Congratulations to %s for winning the duel before the last round\n", round, loser.Name, winner.Name)
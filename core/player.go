@@ -1,8 +1,6 @@
 package core
 
-import (
-	"math"
-)
+import "math"
 
 // PlayerStats represents the stats of a player
 type PlayerStats struct {
@@ -10,7 +8,16 @@ type PlayerStats struct {
 	Strength float64
 	Defence  float64
 	Speed    float64
-	Luck     float64
+	// Luck decides who hits first between players with the same speed
+	Luck float64
+
+	// Evasion is the chance to evade a hit, lowered by the attacker's Accuracy
+	Accuracy float64
+	Evasion  float64
+	// CritChance is the chance of every hit to do CritMultiplier
+	// times more damage (2x when not set)
+	CritChance     float64
+	CritMultiplier float64
 }
 
 // PlayerSkills represents the player's skills
@@ -21,10 +28,51 @@ type PlayerSkills struct {
 
 // Player represents a duel fighter
 type Player struct {
-	Name string
+	Name  string
+	Level int
+	XP    int
 
 	PlayerStats
 	PlayerSkills
+	Resistances Resistances
+	Abilities   []*Ability
+	Pools       Pools
+
+	// Strategy chooses the player's action on every turn;
+	// players without a strategy always attack
+	Strategy Strategy
+
+	equipment []equipped
+
+	// formula is the damage formula used when defending attacks;
+	// it is set by the DuelMaster and defaults to Subtractive
+	formula DamageFormula
+	// sundered is the defence lost during the current duel
+	sundered float64
+	// guard is the defence gained by defending until the next turn
+	guard float64
+	// startingHealth is the health the player had when the duel started
+	startingHealth float64
+	// cooldowns holds the turns left until the abilities are ready
+	cooldowns map[*Ability]int
+	// resources holds the amount left in every pool
+	resources map[Resource]float64
+	// events counts the events that happened to the player
+	// and round is the current round of the duel
+	events map[Event]int
+	round  int
+	// acting is the event of the pipe the player's attack modifiers are
+	// running in, OnAttack or OnDefend, and empty outside of them
+	acting Event
+	// opponent is the player fought in the current duel
+	opponent *Player
+	// statuses holds the lasting effects on the player
+	statuses []Status
+	// skillStates are the states of the stateful skills chained into the
+	// attack modifiers, in the order they were chained; chaining is set
+	// while buildAttackModifiers chains them
+	skillStates []*SkillState
+	chaining    bool
 
 	offensiveAttackModifier AttackModifier
 	defensiveAttackModifier AttackModifier
@@ -34,16 +82,90 @@ type Player struct {
 func NewPlayer(name string, stats PlayerStats, skills PlayerSkills) *Player {
 	p := &Player{
 		Name:         name,
+		Level:        1,
 		PlayerStats:  stats,
 		PlayerSkills: skills,
 	}
 
-	p.offensiveAttackModifier = pipeSkills(p, p.OffensiveSkills)
-	p.defensiveAttackModifier = pipeSkills(p, append([]Skill{&Luck{Chance: p.Luck}}, p.DefensiveSkills...))
+	p.buildAttackModifiers()
 
 	return p
 }
 
+// buildAttackModifiers chains the player's skills followed by the skills
+// of the equipped items; it must be called again every time the player's
+// skills or equipment change
+func (p *Player) buildAttackModifiers() {
+	offensiveSkills := append([]Skill{}, p.OffensiveSkills...)
+	defensiveSkills := append([]Skill{&evasion{}}, p.DefensiveSkills...)
+	for _, e := range p.equipment {
+		offensiveSkills = append(offensiveSkills, e.item.OffensiveSkills...)
+		defensiveSkills = append(defensiveSkills, e.item.DefensiveSkills...)
+	}
+
+	p.skillStates, p.chaining = nil, true
+	p.offensiveAttackModifier = p.during(OnAttack, pipeSkills(p, offensiveSkills))
+	p.defensiveAttackModifier = p.during(OnDefend, pipeSkills(p, defensiveSkills))
+	p.chaining = false
+}
+
+// scratch returns a copy of the player with his own duel state and
+// resources and freshly built attack modifiers, continuing from the states of his
+// skills, so that attacks can be simulated without changing him
+func (p *Player) scratch() *Player {
+	c := *p
+	if p.events != nil {
+		c.events = map[Event]int{}
+		for event, n := range p.events {
+			c.events[event] = n
+		}
+	}
+	if p.resources != nil {
+		c.resources = map[Resource]float64{}
+		for r, amount := range p.resources {
+			c.resources[r] = amount
+		}
+	}
+	c.statuses = append([]Status(nil), p.statuses...)
+
+	c.buildAttackModifiers()
+	for i, state := range p.skillStates {
+		if i < len(c.skillStates) {
+			*c.skillStates[i] = *state
+		}
+	}
+	return &c
+}
+
+// prepareForDuel sets the rules of a new duel, restores the defence
+// lost during previous duels, refills the resource pools and forgets
+// the events of the previous duel but the kill
+func (p *Player) prepareForDuel(formula DamageFormula) {
+	p.formula = formula
+	p.sundered = 0
+	p.guard = 0
+	p.startingHealth = p.Health
+	p.round = 0
+	p.statuses = nil
+	p.cooldowns = map[*Ability]int{}
+	p.fillPools()
+	p.resetEvents()
+}
+
+// EffectiveDefence returns the player's defence without the defence lost
+// during the current duel and with the defence gained by defending
+// and from statuses
+func (p *Player) EffectiveDefence() float64 {
+	return math.Max(0, p.Defence-p.sundered+p.guard+p.statusDefence())
+}
+
+func (p *Player) damageFormula() DamageFormula {
+	if p.formula == nil {
+		return Subtractive{}
+	}
+	return p.formula
+}
+
 // IsDead checks wether the player has died
 func (p *Player) IsDead() bool {
 	return p.Health <= 0
@@ -52,7 +174,8 @@ func (p *Player) IsDead() bool {
 // GenerateAttack generates player's attack
 func (p *Player) GenerateAttack() *Attack {
 	baseAttack := NewAttack(p.Strength)
-	return p.offensiveAttackModifier(baseAttack)
+	baseAttack.Accuracy = p.Accuracy
+	return p.applyCriticalHits(p.offensiveAttackModifier(baseAttack))
 }
 
 // DefendAttack represents the logic for defending oponent player's
@@ -62,14 +185,41 @@ func (p *Player) DefendAttack(attack *Attack) {
 		return
 	}
 
+	potential := make([]float64, len(attack.Hits))
+	for i := range attack.Hits {
+		potential[i] = attack.Hits[i].TotalPotentialDamage()
+	}
+
 	attackAfterDefense := p.defensiveAttackModifier(attack)
 
-	for _, hit := range attackAfterDefense.Hits {
+	evaded, damaged := false, false
+	for i := range attackAfterDefense.Hits {
 		if p.IsDead() {
 			break
 		}
 
-		damage := math.Max(0, hit.PotentialDamage-p.Defence)
-		p.Health = math.Max(0, p.Health-damage)
+		hit := &attackAfterDefense.Hits[i]
+		hit.Damage = p.resist(hit)
+		p.Health = math.Max(0, p.Health-hit.Damage.Total())
+		p.tookDamage(hit.Damage.Total())
+
+		if hit.TotalPotentialDamage() > 0 {
+			p.sundered += hit.Sunder
+			for _, status := range hit.Statuses {
+				p.afflict(status)
+			}
+		} else if i < len(potential) && potential[i] > 0 {
+			evaded = true
+		}
+		if hit.Damage.Total() > 0 {
+			damaged = true
+		}
+	}
+
+	if evaded {
+		p.notify(OnEvade)
+	}
+	if damaged {
+		p.notify(OnHit)
 	}
 }
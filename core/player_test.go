@@ -190,13 +190,13 @@ func TestPlayer_DefendAttack(t *testing.T) {
 			wantHealth: 1, // 2 - (4 * 0.5 (damage after skills applied) - 1 (defence)) = 1
 		},
 		{
-			name: "applies luck to incoming attack and upates health accordingly",
-			p: NewPlayer("Lucky Jim", PlayerStats{
+			name: "applies evasion to incoming attack and upates health accordingly",
+			p: NewPlayer("Evasive Jim", PlayerStats{
 				Health:   1,
 				Strength: 25,
 				Defence:  30,
 				Speed:    85,
-				Luck:     1,
+				Evasion:  1,
 			}, PlayerSkills{
 				OffensiveSkills: []Skill{},
 				DefensiveSkills: []Skill{},
@@ -206,6 +206,40 @@ func TestPlayer_DefendAttack(t *testing.T) {
 			},
 			wantHealth: 1,
 		},
+		{
+			name: "attacker's accuracy lowers the evasion",
+			p: NewPlayer("Clumsy Jim", PlayerStats{
+				Health:   100,
+				Strength: 25,
+				Defence:  30,
+				Speed:    85,
+				Evasion:  0.5,
+			}, PlayerSkills{
+				OffensiveSkills: []Skill{},
+				DefensiveSkills: []Skill{},
+			}),
+			args: args{
+				attack: &Attack{Hits: []Hit{NewHit(100)}, Accuracy: 0.5},
+			},
+			wantHealth: 30,
+		},
+		{
+			name: "luck doesn't evade incoming attacks",
+			p: NewPlayer("Lucky Jim", PlayerStats{
+				Health:   100,
+				Strength: 25,
+				Defence:  30,
+				Speed:    85,
+				Luck:     1,
+			}, PlayerSkills{
+				OffensiveSkills: []Skill{},
+				DefensiveSkills: []Skill{},
+			}),
+			args: args{
+				attack: NewAttack(100),
+			},
+			wantHealth: 30,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
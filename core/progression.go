@@ -0,0 +1,147 @@
+package core
+
+import "math"
+
+// GrowthCurve returns the total bonus a stat gained
+// when reaching the given level (0 on level 1)
+type GrowthCurve func(level int) float64
+
+// LinearGrowth creates a growth curve adding the same bonus every level
+func LinearGrowth(perLevel float64) GrowthCurve {
+	return func(level int) float64 {
+		return perLevel * float64(level-1)
+	}
+}
+
+// ExponentialGrowth creates a growth curve where the first level up
+// adds the given bonus and every other level up adds rate times more
+// than the previous one
+func ExponentialGrowth(first, rate float64) GrowthCurve {
+	return func(level int) float64 {
+		total, bonus := 0.0, first
+		for l := 2; l <= level; l++ {
+			total += bonus
+			bonus *= rate
+		}
+		return total
+	}
+}
+
+// StatGrowth holds the growth curve of every stat;
+// stats without a curve don't grow
+type StatGrowth struct {
+	Health   GrowthCurve
+	Strength GrowthCurve
+	Defence  GrowthCurve
+	Speed    GrowthCurve
+	Luck     GrowthCurve
+
+	Accuracy   GrowthCurve
+	Evasion    GrowthCurve
+	CritChance GrowthCurve
+}
+
+// SkillUnlock represents skills learned when reaching a level
+type SkillUnlock struct {
+	Level  int
+	Skills PlayerSkills
+}
+
+// Progression describes how players gain experience and levels
+type Progression struct {
+	// XPForLevel returns the total experience needed to reach a level;
+	// defaults to 100 more experience for every level. The curve ends
+	// where it stops increasing: the levels that don't need more
+	// experience than the previous one are never reached
+	XPForLevel func(level int) int
+	// XPReward returns the experience gained by defeating a player;
+	// defaults to 50 experience for every level of the defeated player
+	XPReward func(defeated *Player) int
+
+	Growth  StatGrowth
+	Unlocks []SkillUnlock
+}
+
+// LinearXP creates an experience curve where every level
+// needs step more experience than the previous one
+func LinearXP(step int) func(level int) int {
+	return func(level int) int {
+		return step * (level - 1) * level / 2
+	}
+}
+
+func (pr *Progression) xpForLevel(level int) int {
+	if pr.XPForLevel == nil {
+		return LinearXP(100)(level)
+	}
+	return pr.XPForLevel(level)
+}
+
+func (pr *Progression) xpReward(defeated *Player) int {
+	if pr.XPReward == nil {
+		return 50 * defeated.Level
+	}
+	return pr.XPReward(defeated)
+}
+
+func growth(curve GrowthCurve, from, to int) float64 {
+	if curve == nil {
+		return 0
+	}
+	return curve(to) - curve(from)
+}
+
+// GainXP adds experience to the player and levels him up as long as he
+// has enough experience; every level up grows his stats (current health
+// included) and teaches the skills unlocked at that level. It returns
+// the number of levels gained
+func (pr *Progression) GainXP(p *Player, xp int) int {
+	if p.Level < 1 {
+		p.Level = 1
+	}
+	p.XP += xp
+
+	from := p.Level
+	for {
+		next := pr.xpForLevel(p.Level + 1)
+		if p.XP < next || next <= pr.xpForLevel(p.Level) {
+			break
+		}
+		p.Level++
+		for _, unlock := range pr.Unlocks {
+			if unlock.Level != p.Level {
+				continue
+			}
+			// the skill slices might be shared with other players (eg. summoned
+			// from the same template) so they are copied before learning skills
+			p.OffensiveSkills = append(append([]Skill{}, p.OffensiveSkills...), unlock.Skills.OffensiveSkills...)
+			p.DefensiveSkills = append(append([]Skill{}, p.DefensiveSkills...), unlock.Skills.DefensiveSkills...)
+		}
+	}
+
+	if p.Level == from {
+		return 0
+	}
+
+	p.Health += growth(pr.Growth.Health, from, p.Level)
+	p.Strength += growth(pr.Growth.Strength, from, p.Level)
+	p.Defence += growth(pr.Growth.Defence, from, p.Level)
+	p.Speed += growth(pr.Growth.Speed, from, p.Level)
+	p.Luck = math.Min(1, p.Luck+growth(pr.Growth.Luck, from, p.Level))
+	p.Accuracy += growth(pr.Growth.Accuracy, from, p.Level)
+	p.Evasion = math.Min(1, p.Evasion+growth(pr.Growth.Evasion, from, p.Level))
+	p.CritChance = math.Min(1, p.CritChance+growth(pr.Growth.CritChance, from, p.Level))
+	p.buildAttackModifiers()
+
+	return p.Level - from
+}
+
+// Award grants the winner of the duel the experience for defeating
+// the loser; ties and paused duels don't grant any experience. It
+// returns the number of levels gained by the winner
+func (pr *Progression) Award(result *DuelResult) int {
+	if result.Winner == nil {
+		return 0
+	}
+	return pr.GainXP(result.Winner, pr.xpReward(result.Loser))
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGrowthCurves(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve GrowthCurve
+		level int
+		want  float64
+	}{
+		{name: "linear growth has no bonus on level 1", curve: LinearGrowth(5), level: 1, want: 0},
+		{name: "linear growth adds the bonus every level", curve: LinearGrowth(5), level: 4, want: 15},
+		{name: "exponential growth adds the first bonus on level 2", curve: ExponentialGrowth(2, 2), level: 2, want: 2},
+		{name: "exponential growth multiplies the bonus every level", curve: ExponentialGrowth(2, 2), level: 4, want: 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve(tt.level); got != tt.want {
+				t.Errorf("GrowthCurve(%d) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinearXP(t *testing.T) {
+	xp := LinearXP(100)
+	for level, want := range map[int]int{1: 0, 2: 100, 3: 300, 4: 600} {
+		if got := xp(level); got != want {
+			t.Errorf("LinearXP(100)(%d) = %d, want %d", level, got, want)
+		}
+	}
+}
+
+func TestProgression_GainXP(t *testing.T) {
+	critical := &CriticalStrike{DoubleStrikeChance: 1}
+	progression := &Progression{
+		Growth: StatGrowth{
+			Health:   LinearGrowth(10),
+			Strength: LinearGrowth(2),
+			Luck:     LinearGrowth(0.5),
+		},
+		Unlocks: []SkillUnlock{
+			{Level: 3, Skills: PlayerSkills{OffensiveSkills: []Skill{critical}}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		xp         int
+		wantLevels int
+		wantStats  PlayerStats
+		wantSkills []Skill
+	}{
+		{
+			name:       "doesn't level up without enough experience",
+			xp:         99,
+			wantLevels: 0,
+			wantStats:  PlayerStats{Health: 50, Strength: 10, Luck: 0.1},
+			wantSkills: []Skill{},
+		},
+		{
+			name:       "levels up and grows the stats",
+			xp:         100,
+			wantLevels: 1,
+			wantStats:  PlayerStats{Health: 60, Strength: 12, Luck: 0.6},
+			wantSkills: []Skill{},
+		},
+		{
+			name:       "gains multiple levels at once and unlocks skills",
+			xp:         300,
+			wantLevels: 2,
+			wantStats:  PlayerStats{Health: 70, Strength: 14, Luck: 1},
+			wantSkills: []Skill{critical},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer("Hero", PlayerStats{Health: 50, Strength: 10, Luck: 0.1}, PlayerSkills{OffensiveSkills: []Skill{}})
+			if got := progression.GainXP(p, tt.xp); got != tt.wantLevels {
+				t.Errorf("GainXP() = %d, want %d", got, tt.wantLevels)
+			}
+			if p.Level != 1+tt.wantLevels || p.XP != tt.xp {
+				t.Errorf("GainXP() left the player on level %d with %d xp, want level %d with %d xp", p.Level, p.XP, 1+tt.wantLevels, tt.xp)
+			}
+			if !reflect.DeepEqual(p.PlayerStats, tt.wantStats) {
+				t.Errorf("GainXP() stats = %+v, want %+v", p.PlayerStats, tt.wantStats)
+			}
+			if !reflect.DeepEqual(p.OffensiveSkills, tt.wantSkills) {
+				t.Errorf("GainXP() skills = %v, want %v", p.OffensiveSkills, tt.wantSkills)
+			}
+			if got := len(p.GenerateAttack().Hits); got != 1+len(tt.wantSkills) {
+				t.Errorf("GainXP() should rebuild the attack modifiers; got %d hits", got)
+			}
+		})
+	}
+}
+
+func TestProgression_GainXP_CurveEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		xpForLevel func(level int) int
+		wantLevels int
+	}{
+		{name: "flat curve", xpForLevel: func(int) int { return 0 }, wantLevels: 0},
+		{name: "curve ending on level 3", xpForLevel: func(level int) int {
+			if level > 3 {
+				level = 3
+			}
+			return 100 * (level - 1)
+		}, wantLevels: 2},
+		{name: "decreasing curve", xpForLevel: func(level int) int { return 1000 - level }, wantLevels: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progression := &Progression{XPForLevel: tt.xpForLevel}
+			p := NewPlayer("Hero", PlayerStats{Health: 50}, PlayerSkills{})
+			if got := progression.GainXP(p, 10000); got != tt.wantLevels {
+				t.Errorf("GainXP() = %d, want %d", got, tt.wantLevels)
+			}
+		})
+	}
+}
+
+func TestProgression_Award(t *testing.T) {
+	winner := NewPlayer("Hero", PlayerStats{}, PlayerSkills{})
+	loser := NewPlayer("Peanut", PlayerStats{}, PlayerSkills{})
+	loser.Level = 3
+	progression := &Progression{}
+
+	if got := progression.Award(&DuelResult{First: winner, Second: loser}); got != 0 || winner.XP != 0 {
+		t.Errorf("Award() should not grant experience on ties; got %d levels and %d xp", got, winner.XP)
+	}
+	if got := progression.Award(&DuelResult{Paused: true, First: winner, Second: loser}); got != 0 || winner.XP != 0 {
+		t.Errorf("Award() should not grant experience on paused duels; got %d levels and %d xp", got, winner.XP)
+	}
+
+	result := &DuelResult{Knockout: true, First: winner, Second: loser, Winner: winner, Loser: loser}
+	if got := progression.Award(result); got != 1 || winner.XP != 150 {
+		t.Errorf("Award() = %d levels and %d xp, want 1 level and 150 xp", got, winner.XP)
+	}
+}
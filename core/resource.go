@@ -0,0 +1,186 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Resource represents a pool of points players spend on their skills
+type Resource string
+
+// the supported resources; by convention mana starts full and
+// regenerates slowly, stamina regenerates quickly while rage starts
+// empty, builds up with the damage dealt and taken and decays over time
+const (
+	Mana    Resource = "mana"
+	Stamina Resource = "stamina"
+	Rage    Resource = "rage"
+)
+
+// ResourceTypes lists the resources in the order they are presented
+var ResourceTypes = []Resource{Mana, Stamina, Rage}
+
+// Pool represents the rules of a player's resource pool
+type Pool struct {
+	// Max is the most the pool can hold and Start
+	// the amount it holds when a duel starts
+	Max   float64
+	Start float64
+	// Regen is gained at the start of every turn of the player;
+	// negative values make the resource decay
+	Regen float64
+	// OnDamageDealt and OnDamageTaken are the amounts gained
+	// for every point of damage dealt and taken
+	OnDamageDealt float64
+	OnDamageTaken float64
+}
+
+// Pools holds the player's pool for every resource he uses
+type Pools map[Resource]Pool
+
+// Cost represents the amount of a resource spent to use a skill;
+// the zero value is free
+type Cost struct {
+	Resource Resource
+	Amount   float64
+}
+
+func (c Cost) String() string {
+	return fmt.Sprintf("%.2f %s", c.Amount, c.Resource)
+}
+
+// Resource returns the amount of the resource the player has
+func (p *Player) Resource(r Resource) float64 {
+	return p.resources[r]
+}
+
+// CanAfford checks whether the player has enough of the resource to pay
+func (p *Player) CanAfford(cost Cost) bool {
+	return cost.Amount <= 0 || p.resources[cost.Resource] >= cost.Amount
+}
+
+// spend pays the cost if the player can afford it
+func (p *Player) spend(cost Cost) bool {
+	if !p.CanAfford(cost) {
+		return false
+	}
+	if cost.Amount > 0 {
+		p.resources[cost.Resource] -= cost.Amount
+	}
+	return true
+}
+
+// gain adds the amount to the resource, within [0, Max];
+// resources without a pool are ignored
+func (p *Player) gain(r Resource, amount float64) {
+	pool, ok := p.Pools[r]
+	if !ok || amount == 0 {
+		return
+	}
+	if p.resources == nil {
+		p.resources = map[Resource]float64{}
+	}
+	p.resources[r] = math.Min(pool.Max, math.Max(0, p.resources[r]+amount))
+}
+
+// fillPools sets every resource to the amount it starts the duel with
+func (p *Player) fillPools() {
+	p.resources = map[Resource]float64{}
+	for r, pool := range p.Pools {
+		p.resources[r] = math.Min(pool.Max, math.Max(0, pool.Start))
+	}
+}
+
+// regenerate applies the regeneration of every pool
+func (p *Player) regenerate() {
+	for r, pool := range p.Pools {
+		p.gain(r, pool.Regen)
+	}
+}
+
+// dealtDamage and tookDamage let the pools build up with damage
+func (p *Player) dealtDamage(damage float64) {
+	for r, pool := range p.Pools {
+		p.gain(r, pool.OnDamageDealt*damage)
+	}
+}
+
+func (p *Player) tookDamage(damage float64) {
+	for r, pool := range p.Pools {
+		p.gain(r, pool.OnDamageTaken*damage)
+	}
+}
+
+// Costly is a skill that only triggers when the player can afford its
+// cost; the cost is paid every time the skill triggers
+type Costly struct {
+	Skill Skill
+	Cost
+}
+
+// GetDescription returns the long description of the skill
+func (c *Costly) GetDescription() string {
+	return fmt.Sprintf(`%s for %s`, c.Skill.GetDescription(), c.Cost)
+}
+
+// GetModifier returns the modifier of the skill that is applied only when
+// the player can afford it; the skill triggered when it used a skill
+func (c *Costly) GetModifier(player *Player) AttackModifier {
+	modifier := c.Skill.GetModifier(player)
+	return func(attack *Attack) *Attack {
+		if !player.CanAfford(c.Cost) {
+			return attack
+		}
+
+		used := attack.usedSkills()
+		attack = modifier(attack)
+		if attack.usedSkills() > used {
+			player.spend(c.Cost)
+		}
+		return attack
+	}
+}
+
+// MarshalJSON encodes the configuration of the skill, as read by UnmarshalJSON
+func (c *Costly) MarshalJSON() ([]byte, error) {
+	skill, err := NewSkillConfig(c.Skill)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Skill    SkillConfig `json:"skill"`
+		Resource Resource    `json:"resource"`
+		Amount   float64     `json:"amount"`
+	}{skill, c.Resource, c.Amount})
+}
+
+// UnmarshalJSON decodes the configuration of the skill:
+// {"skill": {"type": ..., "params": ...}, "resource": ..., "amount": ...}
+func (c *Costly) UnmarshalJSON(data []byte) error {
+	var config struct {
+		Skill    SkillConfig `json:"skill"`
+		Resource Resource    `json:"resource"`
+		Amount   float64     `json:"amount"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+
+	skill, err := config.Skill.Build()
+	if err != nil {
+		return err
+	}
+
+	c.Skill, c.Cost = skill, Cost{Resource: config.Resource, Amount: config.Amount}
+	return nil
+}
+
+// usedSkills counts the skills used on the attack and on its hits
+func (a *Attack) usedSkills() int {
+	used := len(a.UsedOffensiveSkills) + len(a.UsedDefensiveSkills)
+	for _, hit := range a.Hits {
+		used += len(hit.UsedOffensiveSkills) + len(hit.UsedDefensiveSkills)
+	}
+	return used
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCostly_GetModifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		mana       float64
+		skill      Skill
+		wantHits   int
+		wantUsed   []string
+		wantRemain float64
+	}{
+		{
+			name:       "triggers and pays when affordable",
+			mana:       25,
+			skill:      &CriticalStrike{DoubleStrikeChance: 1},
+			wantHits:   2,
+			wantUsed:   []string{"CriticalStrike(2x)"},
+			wantRemain: 15,
+		},
+		{
+			name:       "doesn't trigger when not affordable",
+			mana:       5,
+			skill:      &CriticalStrike{DoubleStrikeChance: 1},
+			wantHits:   1,
+			wantUsed:   []string{},
+			wantRemain: 5,
+		},
+		{
+			name:       "doesn't pay when the skill doesn't trigger",
+			mana:       25,
+			skill:      &CriticalStrike{DoubleStrikeChance: 0},
+			wantHits:   1,
+			wantUsed:   []string{},
+			wantRemain: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer("Mage", PlayerStats{Strength: 50}, PlayerSkills{})
+			p.Pools = Pools{Mana: {Max: 100, Start: tt.mana}}
+			p.prepareForDuel(nil)
+
+			costly := &Costly{Skill: tt.skill, Cost: Cost{Resource: Mana, Amount: 10}}
+			attack := costly.GetModifier(p)(NewAttack(50))
+
+			if len(attack.Hits) != tt.wantHits || !reflect.DeepEqual(attack.UsedOffensiveSkills, tt.wantUsed) {
+				t.Errorf("GetModifier() = %+v, want %d hits and %v used", attack, tt.wantHits, tt.wantUsed)
+			}
+			if got := p.Resource(Mana); got != tt.wantRemain {
+				t.Errorf("Resource(Mana) = %.2f, want %.2f", got, tt.wantRemain)
+			}
+		})
+	}
+}
+
+func TestPlayer_Pools(t *testing.T) {
+	p := NewPlayer("Berserker", PlayerStats{Health: 100}, PlayerSkills{})
+	p.Pools = Pools{
+		Stamina: {Max: 50, Start: 60, Regen: 20},
+		Rage:    {Max: 100, Regen: -5, OnDamageTaken: 2},
+	}
+	p.prepareForDuel(nil)
+
+	if p.Resource(Stamina) != 50 || p.Resource(Rage) != 0 {
+		t.Errorf("prepareForDuel() = %.2f stamina and %.2f rage, want 50 and 0", p.Resource(Stamina), p.Resource(Rage))
+	}
+
+	p.DefendAttack(NewAttack(30))
+	if p.Resource(Rage) != 60 {
+		t.Errorf("DefendAttack() rage = %.2f, want 60", p.Resource(Rage))
+	}
+
+	p.spend(Cost{Resource: Stamina, Amount: 40})
+	p.startTurn()
+	if p.Resource(Stamina) != 30 || p.Resource(Rage) != 55 {
+		t.Errorf("startTurn() = %.2f stamina and %.2f rage, want 30 and 55", p.Resource(Stamina), p.Resource(Rage))
+	}
+
+	if p.CanAfford(Cost{Resource: Mana, Amount: 1}) {
+		t.Errorf("CanAfford() should be false for resources without a pool")
+	}
+	if !p.CanAfford(Cost{}) {
+		t.Errorf("CanAfford() should be true for free skills")
+	}
+}
+
+func TestPlayer_AbilityCost(t *testing.T) {
+	fireball := &Ability{Skill: &ElementalInfusion{Type: Fire, Chance: 1, Damage: 20}, Cost: Cost{Resource: Mana, Amount: 30}}
+	p := NewPlayer("Mage", PlayerStats{Strength: 10}, PlayerSkills{})
+	p.Abilities = []*Ability{fireball}
+	p.Pools = Pools{Mana: {Max: 50, Start: 50, Regen: 5}}
+	p.prepareForDuel(nil)
+
+	if !p.IsReady(fireball) {
+		t.Fatalf("IsReady() should be true when the ability is affordable")
+	}
+	p.GenerateAbilityAttack(fireball)
+	p.startTurn()
+	if p.Resource(Mana) != 25 || p.IsReady(fireball) {
+		t.Errorf("GenerateAbilityAttack() should pay the cost; got %.2f mana and ready %v", p.Resource(Mana), p.IsReady(fireball))
+	}
+}
+
+func TestCostly_Config(t *testing.T) {
+	config := SkillConfig{}
+	data := `{"type": "Costly", "params": {"skill": {"type": "Resilience", "params": {"Chance": 0.5, "DamageReduction": 0.4}}, "resource": "rage", "amount": 20}}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Costly{Skill: &Resilience{Chance: 0.5, DamageReduction: 0.4}, Cost: Cost{Resource: Rage, Amount: 20}}
+	got, err := config.Build()
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %+v, %v, want %+v", got, err, want)
+	}
+	if got.GetDescription() != "Resilience (50.00% chance to block 40.00% damage) for 20.00 rage" {
+		t.Errorf("GetDescription() = %q", got.GetDescription())
+	}
+}
+
+func TestGreedy_ChooseAction_KeepsResources(t *testing.T) {
+	self := NewPlayer("Mage", PlayerStats{Health: 100, Strength: 20}, PlayerSkills{OffensiveSkills: []Skill{
+		&Costly{Skill: &CriticalStrike{DoubleStrikeChance: 1}, Cost: Cost{Resource: Mana, Amount: 10}},
+	}})
+	self.Pools = Pools{Mana: {Max: 100, Start: 100}}
+	self.Abilities = []*Ability{{Skill: &Costly{Skill: &SunderArmor{Chance: 1, Amount: 5}, Cost: Cost{Resource: Mana, Amount: 20}}}}
+	opponent := NewPlayer("Opponent", PlayerStats{Health: 100, Strength: 20}, PlayerSkills{})
+	self.prepareForDuel(nil)
+	opponent.prepareForDuel(nil)
+
+	Greedy{HealRatio: 0.5}.ChooseAction(DuelState{Round: 1, Rounds: 20, Self: self, Opponent: opponent})
+	if mana := self.Resource(Mana); mana != 100 {
+		t.Errorf("the mage has %.2f mana after choosing his action, want 100", mana)
+	}
+}
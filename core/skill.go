@@ -6,6 +6,28 @@ type Skill interface {
 	GetModifier(*Player) AttackModifier
 }
 
+// SkillState is what a stateful skill remembers between the attacks
+// it modifies; it is saved together with the player
+type SkillState struct {
+	// Used is set when the skill fired on the last attack
+	Used bool `json:"used,omitempty"`
+	// Recovering is the number of attacks the skill still skips
+	Recovering int `json:"recovering,omitempty"`
+	// Seen is the number of events the skill already fired on
+	Seen int `json:"seen,omitempty"`
+}
+
+// newSkillState returns a new state for a stateful skill; the player
+// keeps the states of the skills chained into his attack modifiers,
+// while the states of the skills used once, like abilities, are discarded
+func (p *Player) newSkillState() *SkillState {
+	state := &SkillState{}
+	if p != nil && p.chaining {
+		p.skillStates = append(p.skillStates, state)
+	}
+	return state
+}
+
 // AttackModifier represents a chainable attack skill
 type AttackModifier func(*Attack) *Attack
 
@@ -19,9 +41,10 @@ func pipe(modifiers []AttackModifier) AttackModifier {
 	}
 }
 
+// pipeSkills chains the skills ordered by priority
 func pipeSkills(p *Player, skills []Skill) AttackModifier {
 	attackModifiers := []AttackModifier{}
-	for _, skill := range skills {
+	for _, skill := range sortSkills(skills) {
 		attackModifiers = append(attackModifiers, skill.GetModifier(p))
 	}
 
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// SkillConfig represents a skill within configuration files;
+// Params are decoded into the skill registered under Type
+type SkillConfig struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+var skillFactories = map[string]func() Skill{
+	"CriticalStrike": func() Skill { return &CriticalStrike{} },
+	"Resilience":     func() Skill { return &Resilience{} },
+	"Luck":           func() Skill { return &Luck{} },
+
+	"ElementalInfusion":   func() Skill { return &ElementalInfusion{} },
+	"ElementalConversion": func() Skill { return &ElementalConversion{} },
+	"PiercingStrike":      func() Skill { return &PiercingStrike{} },
+	"SunderArmor":         func() Skill { return &SunderArmor{} },
+	"Costly":              func() Skill { return &Costly{} },
+	"Triggered":           func() Skill { return &Triggered{} },
+	"Declarative":         func() Skill { return &Declarative{} },
+}
+
+// RegisterSkill makes a skill available to configuration files under the
+// given type; the factory must return a pointer to a zero value skill
+func RegisterSkill(name string, factory func() Skill) {
+	skillFactories[name] = factory
+}
+
+// IsSkillType checks whether a skill is registered under the type
+func IsSkillType(name string) bool {
+	_, ok := skillFactories[name]
+	return ok
+}
+
+// Build creates the configured skill
+func (sc SkillConfig) Build() (Skill, error) {
+	factory, ok := skillFactories[sc.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown skill type %q", sc.Type)
+	}
+
+	skill := factory()
+	if len(sc.Params) > 0 {
+		if err := json.Unmarshal(sc.Params, skill); err != nil {
+			return nil, fmt.Errorf("invalid params for skill %q: %v", sc.Type, err)
+		}
+	}
+	return skill, nil
+}
+
+// BuildSkills creates all the configured skills
+func BuildSkills(configs []SkillConfig) ([]Skill, error) {
+	skills := []Skill{}
+	for _, config := range configs {
+		skill, err := config.Build()
+		if err != nil {
+			return nil, err
+		}
+		skills = append(skills, skill)
+	}
+	return skills, nil
+}
+
+// NewSkillConfig describes the skill like the configuration files do;
+// the type of the skill must be registered
+func NewSkillConfig(skill Skill) (SkillConfig, error) {
+	if skill == nil {
+		return SkillConfig{}, fmt.Errorf("no skill to describe")
+	}
+	t := reflect.TypeOf(skill)
+	for name, factory := range skillFactories {
+		if reflect.TypeOf(factory()) != t {
+			continue
+		}
+		params, err := json.Marshal(skill)
+		if err != nil {
+			return SkillConfig{}, fmt.Errorf("skill %q: %v", name, err)
+		}
+		return SkillConfig{Type: name, Params: params}, nil
+	}
+	return SkillConfig{}, fmt.Errorf("the skill %q has no registered type", skill.GetDescription())
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSkillConfig_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  SkillConfig
+		want    Skill
+		wantErr bool
+	}{
+		{
+			name:   "builds a registered skill with its params",
+			config: SkillConfig{Type: "Resilience", Params: json.RawMessage(`{"Chance": 0.2, "DamageReduction": 0.5}`)},
+			want:   &Resilience{Chance: 0.2, DamageReduction: 0.5},
+		},
+		{
+			name:   "builds a skill without params",
+			config: SkillConfig{Type: "CriticalStrike"},
+			want:   &CriticalStrike{},
+		},
+		{
+			name:   "builds custom registered skills",
+			config: SkillConfig{Type: "DoubleDamage"},
+			want:   &doubleDamageAttack{},
+		},
+		{
+			name:    "fails for unknown skills",
+			config:  SkillConfig{Type: "Fireball"},
+			wantErr: true,
+		},
+		{
+			name:    "fails for invalid params",
+			config:  SkillConfig{Type: "Luck", Params: json.RawMessage(`{"Chance": "high"}`)},
+			wantErr: true,
+		},
+	}
+
+	RegisterSkill("DoubleDamage", func() Skill { return &doubleDamageAttack{} })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 )
 
@@ -61,20 +62,20 @@ func (r *Resilience) GetBattleDescription() string {
 }
 
 // GetModifier converts the skill to a (chainable) attack modifier
-func (r *Resilience) GetModifier(*Player) AttackModifier {
-	usedLastTurn := false
+func (r *Resilience) GetModifier(player *Player) AttackModifier {
+	// the skill can't be used on two attacks in a row
+	state := player.newSkillState()
 	modifier := func(attack *Attack) *Attack {
-		if usedLastTurn {
-			usedLastTurn = false
+		if state.Used {
+			state.Used = false
 			return attack
 		}
 
 		if rand.Float64() <= r.Chance {
-			usedLastTurn = true
+			state.Used = true
 			attack.UsedDefensiveSkills = append(attack.UsedDefensiveSkills, r.GetBattleDescription())
 			for i := 0; i < len(attack.Hits); i++ {
-				hit := &attack.Hits[i]
-				hit.PotentialDamage = hit.PotentialDamage - r.DamageReduction*hit.PotentialDamage
+				attack.Hits[i].Reduce(r.DamageReduction)
 			}
 		}
 
@@ -84,7 +85,7 @@ func (r *Resilience) GetModifier(*Player) AttackModifier {
 }
 
 // Luck is a defensive skill
-// it has a chance to evade a hit
+// it has a chance to evade a hit, regardless of the attacker's accuracy
 type Luck struct {
 	Chance float64
 }
@@ -105,7 +106,7 @@ func (l *Luck) GetModifier(*Player) AttackModifier {
 		for i := 0; i < len(attack.Hits); i++ {
 			hit := &attack.Hits[i]
 			if rand.Float64() < l.Chance {
-				hit.PotentialDamage = 0
+				hit.Scale(0)
 				hit.UsedDefensiveSkills = append(hit.UsedDefensiveSkills, l.GetBattleDescription())
 			}
 		}
@@ -115,3 +116,70 @@ func (l *Luck) GetModifier(*Player) AttackModifier {
 
 	return modifier
 }
+
+// PiercingStrike is an offensive skill
+// it has a given chance to ignore part of the defender's defence
+// (a flat amount and a percentage) for every hit within an attack
+type PiercingStrike struct {
+	Chance      float64
+	Penetration float64
+	Ratio       float64
+}
+
+// GetDescription returns the long description of the skill
+func (ps *PiercingStrike) GetDescription() string {
+	return fmt.Sprintf(`Piercing Strike (%.2f%% chance to ignore %.2f%% and %.2f defence)`, ps.Chance*100, ps.Ratio*100, ps.Penetration)
+}
+
+// GetBattleDescription returns the short (battle) description of the skill
+func (ps *PiercingStrike) GetBattleDescription() string {
+	return fmt.Sprintf(`PiercingStrike(ignored %.2f%% and %.2f defence)`, ps.Ratio*100, ps.Penetration)
+}
+
+// GetModifier returns the skill in a chainable form
+func (ps *PiercingStrike) GetModifier(*Player) AttackModifier {
+	modifier := func(attack *Attack) *Attack {
+		if rand.Float64() < ps.Chance {
+			for i := 0; i < len(attack.Hits); i++ {
+				hit := &attack.Hits[i]
+				hit.Penetration += ps.Penetration
+				hit.PenetrationRatio = math.Min(1, hit.PenetrationRatio+ps.Ratio)
+			}
+			attack.UsedOffensiveSkills = append(attack.UsedOffensiveSkills, ps.GetBattleDescription())
+		}
+		return attack
+	}
+	return modifier
+}
+
+// SunderArmor is an offensive skill
+// it has a given chance to permanently reduce the defender's defence,
+// for the rest of the duel, with every hit that lands within an attack
+type SunderArmor struct {
+	Chance float64
+	Amount float64
+}
+
+// GetDescription returns the long description of the skill
+func (sa *SunderArmor) GetDescription() string {
+	return fmt.Sprintf(`Sunder Armor (%.2f%% chance to destroy %.2f defence per hit)`, sa.Chance*100, sa.Amount)
+}
+
+// GetBattleDescription returns the short (battle) description of the skill
+func (sa *SunderArmor) GetBattleDescription() string {
+	return fmt.Sprintf(`SunderArmor(-%.2f defence per hit)`, sa.Amount)
+}
+
+// GetModifier returns the skill in a chainable form
+func (sa *SunderArmor) GetModifier(*Player) AttackModifier {
+	modifier := func(attack *Attack) *Attack {
+		if rand.Float64() < sa.Chance {
+			for i := 0; i < len(attack.Hits); i++ {
+				attack.Hits[i].Sunder += sa.Amount
+			}
+			attack.UsedOffensiveSkills = append(attack.UsedOffensiveSkills, sa.GetBattleDescription())
+		}
+		return attack
+	}
+	return modifier
+}
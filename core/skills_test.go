@@ -150,6 +150,38 @@ func Test_Skill_GetModifier(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Piercing Strike should add armor penetration to every hit",
+			inargs: inargs{
+				player:        &Player{Name: "Sharp Creep", PlayerStats: PlayerStats{Strength: 50.00}},
+				skill:         &PiercingStrike{Chance: 1, Penetration: 5, Ratio: 0.5},
+				attackFactory: func() *Attack { return NewAttack(50) },
+			},
+			wantedAttacks: []Attack{
+				Attack{
+					Hits:                []Hit{Hit{PotentialDamage: 50, Penetration: 5, PenetrationRatio: 0.5, UsedOffensiveSkills: []string{}, UsedDefensiveSkills: []string{}}},
+					UsedOffensiveSkills: []string{"PiercingStrike(ignored 50.00% and 5.00 defence)"},
+					UsedDefensiveSkills: []string{},
+				},
+			},
+		},
+		{
+			name: "Sunder Armor should sunder the defender's armor with every hit",
+			inargs: inargs{
+				player:        &Player{Name: "Heavy Creep", PlayerStats: PlayerStats{Strength: 50.00}},
+				skill:         &SunderArmor{Chance: 1, Amount: 3},
+				attackFactory: func() *Attack { return &Attack{Hits: []Hit{NewHit(50), NewHit(50)}} },
+			},
+			wantedAttacks: []Attack{
+				Attack{
+					Hits: []Hit{
+						Hit{PotentialDamage: 50, Sunder: 3, UsedOffensiveSkills: []string{}, UsedDefensiveSkills: []string{}},
+						Hit{PotentialDamage: 50, Sunder: 3, UsedOffensiveSkills: []string{}, UsedDefensiveSkills: []string{}},
+					},
+					UsedOffensiveSkills: []string{"SunderArmor(-3.00 defence per hit)"},
+				},
+			},
+		},
 		{
 			name: "No Luck, No miss",
 			inargs: inargs{
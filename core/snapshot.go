@@ -0,0 +1,206 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// playerJSON is the JSON form of a player: his build and, during
+// a duel, the state of the duel, including the state of his skills
+type playerJSON struct {
+	Name        string        `json:"name"`
+	Level       int           `json:"level"`
+	XP          int           `json:"xp"`
+	Stats       PlayerStats   `json:"stats"`
+	Offensive   []SkillConfig `json:"offensiveSkills,omitempty"`
+	Defensive   []SkillConfig `json:"defensiveSkills,omitempty"`
+	Resistances Resistances   `json:"resistances,omitempty"`
+	Abilities   []abilityJSON `json:"abilities,omitempty"`
+	Pools       Pools         `json:"pools,omitempty"`
+	// the stats include the bonuses of the equipment
+	Equipment []equippedJSON `json:"equipment,omitempty"`
+
+	StartingHealth float64              `json:"startingHealth,omitempty"`
+	Sundered       float64              `json:"sundered,omitempty"`
+	Guard          float64              `json:"guard,omitempty"`
+	Round          int                  `json:"round,omitempty"`
+	Cooldowns      []int                `json:"cooldowns,omitempty"`
+	Resources      map[Resource]float64 `json:"resources,omitempty"`
+	Events         map[Event]int        `json:"events,omitempty"`
+	Statuses       []Status             `json:"statuses,omitempty"`
+	SkillStates    []*SkillState        `json:"skillStates,omitempty"`
+}
+
+type abilityJSON struct {
+	Skill    SkillConfig `json:"skill"`
+	Cooldown int         `json:"cooldown,omitempty"`
+	Cost     Cost        `json:"cost,omitempty"`
+}
+
+type equippedJSON struct {
+	Item  ItemConfig  `json:"item"`
+	Bonus PlayerStats `json:"bonus"`
+}
+
+func skillConfigs(skills []Skill) ([]SkillConfig, error) {
+	configs := []SkillConfig{}
+	for _, skill := range skills {
+		config, err := NewSkillConfig(skill)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// itemConfig describes the item like the configuration files do
+func itemConfig(it *Item) (ItemConfig, error) {
+	offensive, err := skillConfigs(it.OffensiveSkills)
+	if err != nil {
+		return ItemConfig{}, fmt.Errorf("item %q: %v", it.Name, err)
+	}
+	defensive, err := skillConfigs(it.DefensiveSkills)
+	if err != nil {
+		return ItemConfig{}, fmt.Errorf("item %q: %v", it.Name, err)
+	}
+	return ItemConfig{
+		Name:            it.Name,
+		Slot:            it.Slot,
+		Flat:            it.Flat,
+		Percent:         it.Percent,
+		OffensiveSkills: offensive,
+		DefensiveSkills: defensive,
+	}, nil
+}
+
+// MarshalJSON encodes the player with his skills, abilities and equipment
+// and, during a duel, the state of the duel, so that a paused duel can be
+// saved and resumed; the skills are encoded like in the configuration
+// files, so their types must be registered. The strategy and the opponent
+// aren't encoded
+func (p *Player) MarshalJSON() ([]byte, error) {
+	s := playerJSON{
+		Name:           p.Name,
+		Level:          p.Level,
+		XP:             p.XP,
+		Stats:          p.PlayerStats,
+		Resistances:    p.Resistances,
+		Pools:          p.Pools,
+		StartingHealth: p.startingHealth,
+		Sundered:       p.sundered,
+		Guard:          p.guard,
+		Round:          p.round,
+		Resources:      p.resources,
+		Events:         p.events,
+		Statuses:       p.statuses,
+		SkillStates:    p.skillStates,
+	}
+
+	var err error
+	if s.Offensive, err = skillConfigs(p.OffensiveSkills); err != nil {
+		return nil, err
+	}
+	if s.Defensive, err = skillConfigs(p.DefensiveSkills); err != nil {
+		return nil, err
+	}
+
+	for _, ability := range p.Abilities {
+		skill, err := NewSkillConfig(ability.Skill)
+		if err != nil {
+			return nil, err
+		}
+		s.Abilities = append(s.Abilities, abilityJSON{Skill: skill, Cooldown: ability.Cooldown, Cost: ability.Cost})
+		if p.cooldowns != nil {
+			s.Cooldowns = append(s.Cooldowns, p.cooldowns[ability])
+		}
+	}
+
+	for _, e := range p.equipment {
+		item, err := itemConfig(e.item)
+		if err != nil {
+			return nil, err
+		}
+		s.Equipment = append(s.Equipment, equippedJSON{Item: item, Bonus: e.bonus})
+	}
+
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes a player encoded by MarshalJSON; a player decoded
+// during a duel continues it with the state he had, e.g. with
+// DuelMaster.ResumeDuel
+func (p *Player) UnmarshalJSON(data []byte) error {
+	var s playerJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	offensive, err := BuildSkills(s.Offensive)
+	if err != nil {
+		return fmt.Errorf("player %q: %v", s.Name, err)
+	}
+	defensive, err := BuildSkills(s.Defensive)
+	if err != nil {
+		return fmt.Errorf("player %q: %v", s.Name, err)
+	}
+
+	restored := Player{
+		Name:           s.Name,
+		Level:          s.Level,
+		XP:             s.XP,
+		PlayerStats:    s.Stats,
+		PlayerSkills:   PlayerSkills{OffensiveSkills: offensive, DefensiveSkills: defensive},
+		Resistances:    s.Resistances,
+		Pools:          s.Pools,
+		startingHealth: s.StartingHealth,
+		sundered:       s.Sundered,
+		guard:          s.Guard,
+		round:          s.Round,
+		resources:      s.Resources,
+		events:         s.Events,
+		statuses:       s.Statuses,
+	}
+
+	for _, a := range s.Abilities {
+		skill, err := a.Skill.Build()
+		if err != nil {
+			return fmt.Errorf("player %q: %v", s.Name, err)
+		}
+		restored.Abilities = append(restored.Abilities, &Ability{Skill: skill, Cooldown: a.Cooldown, Cost: a.Cost})
+	}
+	if s.Cooldowns != nil {
+		if len(s.Cooldowns) != len(restored.Abilities) {
+			return fmt.Errorf("player %q has %d abilities but %d cooldowns", s.Name, len(restored.Abilities), len(s.Cooldowns))
+		}
+		restored.cooldowns = map[*Ability]int{}
+		for i, turns := range s.Cooldowns {
+			restored.cooldowns[restored.Abilities[i]] = turns
+		}
+	}
+
+	for _, e := range s.Equipment {
+		item, err := e.Item.Build()
+		if err != nil {
+			return fmt.Errorf("player %q: %v", s.Name, err)
+		}
+		restored.equipment = append(restored.equipment, equipped{item: item, bonus: e.Bonus})
+	}
+
+	// the modifiers keep the player they are built for, so they
+	// are built once the player is in place
+	*p = restored
+	p.buildAttackModifiers()
+	if s.SkillStates == nil {
+		return nil
+	}
+	if len(s.SkillStates) != len(p.skillStates) {
+		return fmt.Errorf("player %q has %d stateful skills but %d skill states", s.Name, len(p.skillStates), len(s.SkillStates))
+	}
+	for i, state := range s.SkillStates {
+		if state != nil {
+			*p.skillStates[i] = *state
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+// snapshotFighters creates 2 players using stateful skills, abilities,
+// resources and equipment
+func snapshotFighters() (*Player, *Player) {
+	hero := NewPlayer("Hero", PlayerStats{Health: 300, Strength: 70, Defence: 40, Speed: 50, Evasion: 0.2}, PlayerSkills{
+		OffensiveSkills: []Skill{
+			&CriticalStrike{DoubleStrikeChance: 0.3, TripleStrikeChance: 0.1},
+			&Triggered{Skill: &Costly{Skill: &PiercingStrike{Chance: 1, Ratio: 0.5}, Cost: Cost{Resource: Rage, Amount: 20}}, On: OnHit},
+		},
+		DefensiveSkills: []Skill{&Resilience{Chance: 0.5, DamageReduction: 0.5}},
+	})
+	hero.Abilities = []*Ability{{Skill: &SunderArmor{Chance: 1, Amount: 5}, Cooldown: 2, Cost: Cost{Resource: Stamina, Amount: 30}}}
+	hero.Pools = Pools{Stamina: {Max: 100, Start: 100, Regen: 10}, Rage: {Max: 100, OnDamageTaken: 1}}
+	hero.Strategy = Aggressive{}
+	hero.Equip(&Item{
+		Name:         "Rusty Sword",
+		Slot:         Weapon,
+		Flat:         PlayerStats{Strength: 5},
+		PlayerSkills: PlayerSkills{DefensiveSkills: []Skill{&Luck{Chance: 0.1}}},
+	})
+
+	chance := 0.5
+	villain := NewPlayer("Villain", PlayerStats{Health: 320, Strength: 65, Defence: 45, Speed: 45, Evasion: 0.25}, PlayerSkills{
+		DefensiveSkills: []Skill{&Declarative{Spec: SkillSpec{
+			Name:      "Ward",
+			Defensive: true,
+			Chance:    &chance,
+			Recovery:  2,
+			Effects:   []EffectSpec{{Type: ReduceDamage, Amount: 0.3}},
+		}}},
+	})
+	villain.Strategy = Defensive{HealBelow: 0.3}
+	return hero, villain
+}
+
+func TestPlayer_MarshalJSON(t *testing.T) {
+	rand.Seed(1)
+	hero, villain := snapshotFighters()
+	(&DuelMaster{Rounds: 20, PauseAfter: 4, PlayerOne: hero, PlayerTwo: villain}).StartDuel()
+
+	for _, p := range []*Player{hero, villain} {
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("Marshal(%s) returned an unexpected error: %v", p.Name, err)
+		}
+
+		restored := &Player{}
+		if err := json.Unmarshal(data, restored); err != nil {
+			t.Fatalf("Unmarshal(%s) returned an unexpected error: %v", p.Name, err)
+		}
+		again, err := json.Marshal(restored)
+		if err != nil {
+			t.Fatalf("Marshal(restored %s) returned an unexpected error: %v", p.Name, err)
+		}
+		if !bytes.Equal(data, again) {
+			t.Errorf("%s wasn't restored:\n%s\n%s", p.Name, data, again)
+		}
+
+		if restored.Health != p.Health || restored.EffectiveDefence() != p.EffectiveDefence() || len(restored.Equipment()) != len(p.Equipment()) {
+			t.Errorf("%s was restored with %.2f health and %.2f defence, want %.2f and %.2f",
+				p.Name, restored.Health, restored.EffectiveDefence(), p.Health, p.EffectiveDefence())
+		}
+		for i, ability := range p.Abilities {
+			if got, want := restored.Cooldown(restored.Abilities[i]), p.Cooldown(ability); got != want {
+				t.Errorf("%s's ability %d is ready in %d turns, want %d", p.Name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestPlayer_MarshalJSON_SkillState(t *testing.T) {
+	p := NewPlayer("Hero", PlayerStats{Health: 100}, PlayerSkills{
+		DefensiveSkills: []Skill{&Resilience{Chance: 1, DamageReduction: 0.5}},
+	})
+	// Resilience blocks half of the first attack but not of the next one
+	p.DefendAttack(NewAttack(20))
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &Player{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+
+	restored.DefendAttack(NewAttack(20))
+	if restored.Health != 70 {
+		t.Errorf("the restored player has %.2f health, want 70 since Resilience was used on the last attack", restored.Health)
+	}
+}
+
+func TestPlayer_MarshalJSON_Errors(t *testing.T) {
+	p := NewPlayer("Hero", PlayerStats{Health: 100}, PlayerSkills{OffensiveSkills: []Skill{&evasion{}}})
+	if _, err := json.Marshal(p); err == nil {
+		t.Errorf("Marshal() should fail for skills without a registered type")
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown skill", `{"name": "Hero", "offensiveSkills": [{"type": "Fireball"}]}`},
+		{"unknown item skill", `{"name": "Hero", "equipment": [{"item": {"name": "Staff", "slot": "weapon", "offensiveSkills": [{"type": "Fireball"}]}}]}`},
+		{"skill states without skills", `{"name": "Hero", "skillStates": [{"used": true}]}`},
+		{"cooldowns without abilities", `{"name": "Hero", "cooldowns": [1]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.data), &Player{}); err == nil {
+				t.Errorf("Unmarshal() should return an error")
+			}
+		})
+	}
+}
+
+// pauseCommentator counts the duels started and ended
+type pauseCommentator struct {
+	dummyCommentator
+	started, ended int
+}
+
+func (pc *pauseCommentator) Start()                                { pc.started++ }
+func (pc *pauseCommentator) EndDuelKnockout(int, *Player, *Player) { pc.ended++ }
+func (pc *pauseCommentator) EndDuelFlee(int, *Player, *Player)     { pc.ended++ }
+func (pc *pauseCommentator) EndDuelTie(int, *Player, *Player)      { pc.ended++ }
+
+func TestDuelMaster_ResumeDuel(t *testing.T) {
+	rand.Seed(42)
+	hero, villain := snapshotFighters()
+	want := (&DuelMaster{Rounds: 20, PlayerOne: hero, PlayerTwo: villain}).StartDuel()
+
+	rand.Seed(42)
+	hero, villain = snapshotFighters()
+	commentator := &pauseCommentator{}
+	paused := (&DuelMaster{Rounds: 20, PauseAfter: 3, PlayerOne: hero, PlayerTwo: villain}).StartDuel(commentator)
+	if !paused.Paused || paused.IsTie() || paused.Rounds != 3 {
+		t.Fatalf("StartDuel() = %+v, want the duel paused after round 3", paused)
+	}
+
+	data, err := json.Marshal([]*Player{hero, villain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := []*Player{}
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	restored[0].Strategy, restored[1].Strategy = hero.Strategy, villain.Strategy
+
+	dm := &DuelMaster{Rounds: 20, PlayerOne: restored[0], PlayerTwo: restored[1]}
+	for _, round := range []int{0, 2, 20} {
+		if _, err := dm.ResumeDuel(round); err == nil {
+			t.Errorf("ResumeDuel(%d) should return an error for players paused after round 3", round)
+		}
+	}
+
+	got, err := dm.ResumeDuel(paused.Rounds, commentator)
+	if err != nil {
+		t.Fatalf("ResumeDuel() returned an unexpected error: %v", err)
+	}
+	if commentator.started != 1 || commentator.ended != 1 {
+		t.Errorf("the duel was started %d times and ended %d times, want once", commentator.started, commentator.ended)
+	}
+	if got.Paused || got.Rounds != want.Rounds || got.Knockout != want.Knockout || got.Fled != want.Fled {
+		t.Errorf("ResumeDuel() = %+v, want %+v", got, want)
+	}
+	if got.First.Health != want.First.Health || got.Second.Health != want.Second.Health {
+		t.Errorf("the resumed duel ended with %.2f and %.2f health, want %.2f and %.2f",
+			got.First.Health, got.Second.Health, want.First.Health, want.Second.Health)
+	}
+	if want.Rounds <= 3 {
+		t.Errorf("the duel ended on round %d, before it was paused", want.Rounds)
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// Status represents a lasting effect on a player, such as a burn or
+// a weakened armor; it is applied by the hits carrying it once they
+// land and wears off after the given number of the player's turns
+type Status struct {
+	Name  string `json:"name"`
+	Turns int    `json:"turns"`
+	// Damage of the given Type is taken at the start of
+	// every turn; it ignores defence but not resistances
+	Type   DamageType `json:"type,omitempty"`
+	Damage float64    `json:"damage,omitempty"`
+	// Defence is gained (lost when negative) while the status lasts
+	Defence float64 `json:"defence,omitempty"`
+}
+
+func (s Status) String() string {
+	return fmt.Sprintf("%s (%d turns)", s.Name, s.Turns)
+}
+
+// Statuses returns the statuses affecting the player
+func (p *Player) Statuses() []Status {
+	return append([]Status{}, p.statuses...)
+}
+
+// afflict applies the status to the player;
+// a status the player already has is renewed
+func (p *Player) afflict(status Status) {
+	for i, s := range p.statuses {
+		if s.Name == status.Name {
+			p.statuses[i] = status
+			return
+		}
+	}
+	p.statuses = append(p.statuses, status)
+}
+
+// statusDefence returns the defence gained from the statuses
+func (p *Player) statusDefence() float64 {
+	defence := 0.0
+	for _, s := range p.statuses {
+		defence += s.Defence
+	}
+	return defence
+}
+
+// sufferStatuses applies the damage of the statuses
+// and removes the statuses that wore off
+func (p *Player) sufferStatuses() {
+	active := []Status{}
+	for _, s := range p.statuses {
+		if s.Damage > 0 {
+			damageType := s.Type
+			if damageType == "" {
+				damageType = Physical
+			}
+			hit := Hit{PenetrationRatio: 1}
+			hit.AddDamage(damageType, s.Damage)
+			p.Health = math.Max(0, p.Health-p.resist(&hit).Total())
+		}
+
+		s.Turns--
+		if s.Turns > 0 {
+			active = append(active, s)
+		}
+	}
+	p.statuses = active
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"math"
+	"math/rand"
+)
+
+// DuelState represents what a player sees of the duel when choosing
+// his action; strategies must not change the players
+type DuelState struct {
+	Round  int
+	Rounds int
+
+	Self     *Player
+	Opponent *Player
+}
+
+// Strategy chooses the action of a player on every turn
+type Strategy interface {
+	ChooseAction(state DuelState) Action
+}
+
+// Aggressive always attacks, using the first ready ability
+type Aggressive struct{}
+
+// ChooseAction attacks with the first ready ability or a plain attack
+func (Aggressive) ChooseAction(state DuelState) Action {
+	if ready := state.Self.ReadyAbilities(); len(ready) > 0 {
+		return Action{Kind: AbilityAction, Ability: ready[0]}
+	}
+	return Action{Kind: AttackAction}
+}
+
+// Defensive heals when its health falls below HealBelow percent
+// of its starting health, flees below FleeBelow percent and defends
+// when the opponent's next attack could knock it out; otherwise it
+// attacks like the Aggressive strategy
+type Defensive struct {
+	HealBelow float64
+	FleeBelow float64
+}
+
+// ChooseAction chooses the action that keeps the player alive
+func (d Defensive) ChooseAction(state DuelState) Action {
+	self := state.Self
+	health := self.Health / math.Max(self.StartingHealth(), 1)
+
+	switch {
+	case health < d.FleeBelow:
+		return Action{Kind: FleeAction}
+	case health < d.HealBelow && self.Health < self.StartingHealth():
+		return Action{Kind: HealAction}
+	case state.Opponent.Strength-self.EffectiveDefence() >= self.Health:
+		return Action{Kind: DefendAction}
+	}
+	return Aggressive{}.ChooseAction(state)
+}
+
+// Random chooses between attacking, using a ready ability,
+// defending and healing; it never flees
+type Random struct{}
+
+// ChooseAction chooses a random action
+func (Random) ChooseAction(state DuelState) Action {
+	actions := []Action{{Kind: AttackAction}, {Kind: DefendAction}, {Kind: HealAction}}
+	for _, ability := range state.Self.ReadyAbilities() {
+		actions = append(actions, Action{Kind: AbilityAction, Ability: ability})
+	}
+	return actions[rand.Intn(len(actions))]
+}
+
+// Greedy chooses the attack (plain or with an ability) with the
+// highest expected damage, estimated from Samples simulated attacks
+// (100 by default); it heals instead when the opponent's next attack
+// is expected to knock it out and healing would prevent it
+type Greedy struct {
+	Samples   int
+	HealRatio float64
+}
+
+func (g Greedy) samples() int {
+	if g.Samples <= 0 {
+		return 100
+	}
+	return g.Samples
+}
+
+// ChooseAction chooses the action with the highest expected value
+func (g Greedy) ChooseAction(state DuelState) Action {
+	self, opponent := state.Self, state.Opponent
+
+	if g.HealRatio > 0 {
+		incoming := ExpectedDamage(opponent, self, nil, g.samples())
+		healed := math.Min(g.HealRatio*self.StartingHealth(), self.StartingHealth()-self.Health)
+		if incoming >= self.Health && incoming < self.Health+healed {
+			return Action{Kind: HealAction}
+		}
+	}
+
+	best := Action{Kind: AttackAction}
+	bestDamage := ExpectedDamage(self, opponent, nil, g.samples())
+	for _, ability := range self.ReadyAbilities() {
+		if damage := ExpectedDamage(self, opponent, ability, g.samples()); damage > bestDamage {
+			best, bestDamage = Action{Kind: AbilityAction, Ability: ability}, damage
+		}
+	}
+	return best
+}
+
+// ExpectedDamage estimates the damage of the attacker's attack
+// (enhanced by the ability, if any) against the defender by averaging
+// the given number of simulated attacks; the defender's defensive
+// skills are ignored and neither of the players is changed, every
+// attack is simulated on a new copy of the attacker so that the
+// states of his skills and his resources are the same for all of them
+func ExpectedDamage(attacker, defender *Player, ability *Ability, samples int) float64 {
+	if samples <= 0 {
+		return 0
+	}
+
+	hitChance := 1 - MissChance(attacker.Accuracy, defender.Evasion)
+	total := 0.0
+	for i := 0; i < samples; i++ {
+		scratch := attacker.scratch()
+		modifier := scratch.offensiveAttackModifier
+		if ability != nil {
+			modifier = pipe([]AttackModifier{scratch.during(OnAttack, ability.Skill.GetModifier(scratch)), modifier})
+		}
+
+		baseAttack := NewAttack(scratch.Strength)
+		baseAttack.Accuracy = scratch.Accuracy
+		attack := scratch.applyCriticalHits(modifier(baseAttack))
+		for j := range attack.Hits {
+			total += defender.resist(&attack.Hits[j]).Total() * hitChance
+		}
+	}
+	return total / float64(samples)
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// scriptedStrategy plays the given actions in order, then attacks
+type scriptedStrategy struct {
+	actions []Action
+}
+
+func (ss *scriptedStrategy) ChooseAction(DuelState) Action {
+	if len(ss.actions) == 0 {
+		return Action{Kind: AttackAction}
+	}
+	action := ss.actions[0]
+	ss.actions = ss.actions[1:]
+	return action
+}
+
+func TestDuelMaster_StartDuel_Actions(t *testing.T) {
+	tests := []struct {
+		name          string
+		actions       []Action
+		rounds        int
+		player1Health float64
+		player2Health float64
+		fled          bool
+	}{
+		{
+			name:          "defending raises the defence against the next attack",
+			actions:       []Action{{Kind: DefendAction}},
+			rounds:        1,
+			player1Health: 100,
+			player2Health: 100,
+		},
+		{
+			name:          "healing recovers health",
+			actions:       []Action{{Kind: AttackAction}, {Kind: HealAction}},
+			rounds:        2,
+			player1Health: 80, // 60 without healing
+			player2Health: 80,
+		},
+		{
+			name:          "fleeing ends the duel with a loss",
+			actions:       []Action{{Kind: FleeAction}},
+			rounds:        20,
+			player1Health: 100,
+			player2Health: 100,
+			fled:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the first player hits for 20 and the second one for 20, or 0 against a raised guard
+			dm := &DuelMaster{
+				Rounds:    tt.rounds,
+				PlayerOne: NewPlayer("Scripted", PlayerStats{Health: 100, Strength: 40, Defence: 40, Speed: 2}, PlayerSkills{}),
+				PlayerTwo: NewPlayer("Brute", PlayerStats{Health: 100, Strength: 60, Defence: 20, Speed: 1}, PlayerSkills{}),
+			}
+			dm.PlayerOne.Strategy = &scriptedStrategy{actions: tt.actions}
+
+			result := dm.StartDuel()
+			if dm.PlayerOne.Health != tt.player1Health || dm.PlayerTwo.Health != tt.player2Health {
+				t.Errorf("StartDuel() left %.2f and %.2f health, want %.2f and %.2f",
+					dm.PlayerOne.Health, dm.PlayerTwo.Health, tt.player1Health, tt.player2Health)
+			}
+			if result.Fled != tt.fled || (tt.fled && result.Winner != dm.PlayerTwo) {
+				t.Errorf("StartDuel() = %+v, want fled %v", result, tt.fled)
+			}
+		})
+	}
+}
+
+func TestStrategies_ChooseAction(t *testing.T) {
+	slam := &Ability{Skill: &CriticalStrike{DoubleStrikeChance: 1}, Cooldown: 3}
+	weak := &Ability{Skill: &SunderArmor{Chance: 1, Amount: 1}, Cooldown: 3}
+
+	newState := func(health float64, abilities ...*Ability) DuelState {
+		self := NewPlayer("Self", PlayerStats{Health: 100, Strength: 50, Defence: 10}, PlayerSkills{})
+		self.Abilities = abilities
+		self.prepareForDuel(nil)
+		self.Health = health
+		opponent := NewPlayer("Opponent", PlayerStats{Health: 100, Strength: 50, Defence: 10}, PlayerSkills{})
+		opponent.prepareForDuel(nil)
+		return DuelState{Round: 1, Rounds: 20, Self: self, Opponent: opponent}
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		state    DuelState
+		want     Action
+	}{
+		{
+			name:     "aggressive attacks without abilities",
+			strategy: Aggressive{},
+			state:    newState(100),
+			want:     Action{Kind: AttackAction},
+		},
+		{
+			name:     "aggressive uses ready abilities",
+			strategy: Aggressive{},
+			state:    newState(100, slam),
+			want:     Action{Kind: AbilityAction, Ability: slam},
+		},
+		{
+			name:     "defensive heals when low on health",
+			strategy: Defensive{HealBelow: 0.5},
+			state:    newState(45),
+			want:     Action{Kind: HealAction},
+		},
+		{
+			name:     "defensive flees when almost dead",
+			strategy: Defensive{HealBelow: 0.5, FleeBelow: 0.1},
+			state:    newState(5),
+			want:     Action{Kind: FleeAction},
+		},
+		{
+			name:     "defensive defends against a knockout",
+			strategy: Defensive{},
+			state:    newState(40),
+			want:     Action{Kind: DefendAction},
+		},
+		{
+			name:     "greedy picks the ability with the highest expected damage",
+			strategy: Greedy{},
+			state:    newState(100, weak, slam),
+			want:     Action{Kind: AbilityAction, Ability: slam},
+		},
+		{
+			name:     "greedy heals when healing prevents a knockout",
+			strategy: Greedy{HealRatio: 0.2},
+			state:    newState(30),
+			want:     Action{Kind: HealAction},
+		},
+		{
+			name:     "greedy attacks when healing doesn't prevent a knockout",
+			strategy: Greedy{HealRatio: 0.2},
+			state:    newState(10),
+			want:     Action{Kind: AttackAction},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.strategy.ChooseAction(tt.state); got != tt.want {
+				t.Errorf("ChooseAction() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRandom_ChooseAction(t *testing.T) {
+	self := NewPlayer("Self", PlayerStats{}, PlayerSkills{})
+	seen := map[ActionKind]bool{}
+	for i := 0; i < 200; i++ {
+		seen[Random{}.ChooseAction(DuelState{Self: self, Opponent: self}).Kind] = true
+	}
+	if !seen[AttackAction] || !seen[DefendAction] || !seen[HealAction] || seen[FleeAction] || seen[AbilityAction] {
+		t.Errorf("ChooseAction() chose %v", seen)
+	}
+}
+
+func TestExpectedDamage(t *testing.T) {
+	attacker := NewPlayer("Attacker", PlayerStats{Strength: 50, Accuracy: 0.25}, PlayerSkills{})
+	defender := NewPlayer("Defender", PlayerStats{Defence: 10, Evasion: 0.75}, PlayerSkills{})
+
+	if got := ExpectedDamage(attacker, defender, nil, 10); math.Abs(got-20) > 1e-9 {
+		t.Errorf("ExpectedDamage() = %v, want 20", got)
+	}
+
+	double := &Ability{Skill: &CriticalStrike{DoubleStrikeChance: 1}}
+	if got := ExpectedDamage(attacker, defender, double, 10); math.Abs(got-40) > 1e-9 {
+		t.Errorf("ExpectedDamage() with an ability = %v, want 40", got)
+	}
+}
+
+func TestExpectedDamage_SameStateForEverySample(t *testing.T) {
+	attacker := NewPlayer("Attacker", PlayerStats{Health: 100, Strength: 20}, PlayerSkills{OffensiveSkills: []Skill{
+		&Costly{Skill: &CriticalStrike{DoubleStrikeChance: 1}, Cost: Cost{Resource: Mana, Amount: 10}},
+	}})
+	attacker.Pools = Pools{Mana: {Max: 10, Start: 10}}
+	defender := NewPlayer("Defender", PlayerStats{Health: 100}, PlayerSkills{})
+	attacker.prepareForDuel(nil)
+	defender.prepareForDuel(nil)
+
+	// the mana pays for a single double strike, in every sample
+	if got := ExpectedDamage(attacker, defender, nil, 10); math.Abs(got-40) > 1e-9 {
+		t.Errorf("ExpectedDamage() = %v, want 40", got)
+	}
+}
+
+func TestExpectedDamage_KeepsAttacker(t *testing.T) {
+	always := 1.0
+	attacker := NewPlayer("Attacker", PlayerStats{Health: 100, Strength: 20}, PlayerSkills{OffensiveSkills: []Skill{
+		&Triggered{Skill: &CriticalStrike{DoubleStrikeChance: 1}, On: OnHit},
+		&Declarative{Spec: SkillSpec{
+			Name:     "Second Wind",
+			Chance:   &always,
+			Recovery: 3,
+			Effects:  []EffectSpec{{Type: Heal, Amount: 0.5}},
+		}},
+	}})
+	defender := NewPlayer("Defender", PlayerStats{Health: 100}, PlayerSkills{})
+	attacker.prepareForDuel(nil)
+	defender.prepareForDuel(nil)
+	attacker.DefendAttack(NewAttack(30))
+
+	before, err := json.Marshal(attacker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ExpectedDamage(attacker, defender, nil, 10)
+	after, err := json.Marshal(attacker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Errorf("ExpectedDamage() changed the attacker:\n%s\n%s", before, after)
+	}
+
+	// the attacker still heals and double strikes on his next attack
+	if attack := attacker.GenerateAttack(); len(attack.Hits) != 2 || attacker.Health != 100 {
+		t.Errorf("the attacker attacked with %d hits and has %.2f health, want 2 hits and 100 health", len(attack.Hits), attacker.Health)
+	}
+}
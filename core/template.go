@@ -0,0 +1,68 @@
+package core
+
+// StatRange represents the interval in which a stat is generated
+type StatRange struct {
+	Min float64
+	Max float64
+}
+
+// Roll returns a random value within the range
+func (sr StatRange) Roll() float64 {
+	return Range(sr.Min, sr.Max)
+}
+
+// StatRanges holds the ranges for every player stat
+type StatRanges struct {
+	Health   StatRange
+	Strength StatRange
+	Defence  StatRange
+	Speed    StatRange
+	Luck     StatRange
+
+	Accuracy       StatRange
+	Evasion        StatRange
+	CritChance     StatRange
+	CritMultiplier StatRange
+}
+
+// Roll generates random player stats within the ranges
+func (sr StatRanges) Roll() PlayerStats {
+	return PlayerStats{
+		Health:   sr.Health.Roll(),
+		Strength: sr.Strength.Roll(),
+		Defence:  sr.Defence.Roll(),
+		Speed:    sr.Speed.Roll(),
+		Luck:     sr.Luck.Roll(),
+
+		Accuracy:       sr.Accuracy.Roll(),
+		Evasion:        sr.Evasion.Roll(),
+		CritChance:     sr.CritChance.Roll(),
+		CritMultiplier: sr.CritMultiplier.Roll(),
+	}
+}
+
+// PlayerTemplate describes a kind of fighter; every time
+// it is summoned a new player with random stats is created
+type PlayerTemplate struct {
+	Name        string
+	Stats       StatRanges
+	Skills      PlayerSkills
+	Resistances Resistances
+	Equipment   []*Item
+	Abilities   []*Ability
+	Pools       Pools
+	Strategy    Strategy
+}
+
+// Summon creates a new player based on the template
+func (pt PlayerTemplate) Summon() *Player {
+	p := NewPlayer(pt.Name, pt.Stats.Roll(), pt.Skills)
+	p.Resistances = pt.Resistances
+	p.Abilities = pt.Abilities
+	p.Pools = pt.Pools
+	p.Strategy = pt.Strategy
+	for _, item := range pt.Equipment {
+		p.Equip(item)
+	}
+	return p
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlayerTemplate_Summon(t *testing.T) {
+	tests := []struct {
+		name string
+		pt   PlayerTemplate
+	}{
+		{
+			name: "summons a player with stats within the template's ranges",
+			pt: PlayerTemplate{
+				Name: "Peanut",
+				Stats: StatRanges{
+					Health:   StatRange{Min: 60, Max: 90},
+					Strength: StatRange{Min: 60, Max: 90},
+					Defence:  StatRange{Min: 40, Max: 60},
+					Speed:    StatRange{Min: 40, Max: 60},
+					Luck:     StatRange{Min: 0.25, Max: 0.4},
+				},
+				Skills: PlayerSkills{
+					OffensiveSkills: []Skill{&CriticalStrike{DoubleStrikeChance: 0.1}},
+					DefensiveSkills: []Skill{},
+				},
+			},
+		},
+		{
+			name: "summons a player with fixed stats when ranges are empty",
+			pt: PlayerTemplate{
+				Name: "Fixed Creep",
+				Stats: StatRanges{
+					Health:   StatRange{Min: 10, Max: 10},
+					Strength: StatRange{Min: 20, Max: 20},
+					Defence:  StatRange{Min: 5, Max: 5},
+					Speed:    StatRange{Min: 1, Max: 1},
+					Luck:     StatRange{Min: 0, Max: 0},
+				},
+			},
+		},
+	}
+
+	rand.Seed(time.Now().UnixNano())
+
+	inRange := func(v float64, sr StatRange) bool {
+		return v >= sr.Min && v <= sr.Max
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.pt.Summon()
+			if p.Name != tt.pt.Name {
+				t.Errorf("Summon() name = %s, want %s", p.Name, tt.pt.Name)
+			}
+			if !inRange(p.Health, tt.pt.Stats.Health) ||
+				!inRange(p.Strength, tt.pt.Stats.Strength) ||
+				!inRange(p.Defence, tt.pt.Stats.Defence) ||
+				!inRange(p.Speed, tt.pt.Stats.Speed) ||
+				!inRange(p.Luck, tt.pt.Stats.Luck) {
+				t.Errorf("Summon() stats = %+v, want within %+v", p.PlayerStats, tt.pt.Stats)
+			}
+			if !reflect.DeepEqual(p.PlayerSkills, tt.pt.Skills) {
+				t.Errorf("Summon() skills = %v, want %v", p.PlayerSkills, tt.pt.Skills)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Event represents the moment of a duel on which a skill fires
+type Event string
+
+// the supported events; OnAttack and OnDefend fire on every attack going
+// through the offensive and the defensive skills pipe, respectively, and
+// never in the other pipe. OnHit and OnEvade fire on the next attack going
+// through the pipe after the player was hit or evaded a hit during the
+// duel. OnKill carries over to the next duel: it fires on the first
+// attack going through the pipe in the duel after the player killed his
+// opponent. Skills without an event fire on every attack of their pipe
+const (
+	OnAttack Event = "attack"
+	OnDefend Event = "defend"
+	OnHit    Event = "hit"
+	OnEvade  Event = "evade"
+	OnKill   Event = "kill"
+)
+
+// validate checks that the event is supported or empty
+func (e Event) validate() error {
+	switch e {
+	case "", OnAttack, OnDefend, OnHit, OnEvade, OnKill:
+		return nil
+	}
+	return fmt.Errorf("unknown event %q", e)
+}
+
+// Triggered is a skill that fires only on the given event and while
+// all of its conditions are met. Skills with a higher Priority run
+// first within the skills pipe; skills without priority keep the
+// order in which they are listed
+type Triggered struct {
+	Skill Skill
+	// On is the event firing the skill; fires on every attack when empty
+	On Event
+	// HealthBelow makes the skill fire only while the player's health is
+	// below the given percentage of his starting health (ignored when 0)
+	HealthBelow float64
+	// Round makes the skill fire only in the given round (ignored when 0)
+	Round    int
+	Priority int
+}
+
+// GetDescription returns the long description of the skill
+func (t *Triggered) GetDescription() string {
+	conditions := []string{}
+	if t.On != "" {
+		conditions = append(conditions, fmt.Sprintf("on %s", t.On))
+	}
+	if t.HealthBelow > 0 {
+		conditions = append(conditions, fmt.Sprintf("below %.2f%% health", t.HealthBelow*100))
+	}
+	if t.Round > 0 {
+		conditions = append(conditions, fmt.Sprintf("in round %d", t.Round))
+	}
+
+	if len(conditions) == 0 {
+		return t.Skill.GetDescription()
+	}
+	return fmt.Sprintf(`%s (%s)`, t.Skill.GetDescription(), strings.Join(conditions, ", "))
+}
+
+func (t *Triggered) priority() int {
+	return t.Priority
+}
+
+// GetModifier returns the modifier of the skill that is applied
+// only when the event happened and the conditions are met
+func (t *Triggered) GetModifier(player *Player) AttackModifier {
+	return t.modifier(player, player.newSkillState())
+}
+
+// modifier returns the modifier of the skill, counting
+// the events the skill already fired on in the state
+func (t *Triggered) modifier(player *Player, state *SkillState) AttackModifier {
+	modifier := t.Skill.GetModifier(player)
+	state.Seen = player.events[t.On]
+	return func(attack *Attack) *Attack {
+		switch t.On {
+		case "":
+		case OnAttack, OnDefend:
+			if player.acting != t.On {
+				return attack
+			}
+		default:
+			if player.events[t.On] == state.Seen {
+				return attack
+			}
+		}
+		if t.HealthBelow > 0 && player.healthRatio() >= t.HealthBelow {
+			return attack
+		}
+		if t.Round > 0 && player.round != t.Round {
+			return attack
+		}
+
+		state.Seen = player.events[t.On]
+		return modifier(attack)
+	}
+}
+
+// MarshalJSON encodes the configuration of the skill, as read by UnmarshalJSON
+func (t *Triggered) MarshalJSON() ([]byte, error) {
+	skill, err := NewSkillConfig(t.Skill)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Skill       SkillConfig `json:"skill"`
+		On          Event       `json:"on,omitempty"`
+		HealthBelow float64     `json:"healthBelow,omitempty"`
+		Round       int         `json:"round,omitempty"`
+		Priority    int         `json:"priority,omitempty"`
+	}{skill, t.On, t.HealthBelow, t.Round, t.Priority})
+}
+
+// UnmarshalJSON decodes the configuration of the skill:
+// {"skill": {"type": ..., "params": ...}, "on": ..., "healthBelow": ..., "round": ..., "priority": ...}
+func (t *Triggered) UnmarshalJSON(data []byte) error {
+	var config struct {
+		Skill       SkillConfig `json:"skill"`
+		On          Event       `json:"on"`
+		HealthBelow float64     `json:"healthBelow"`
+		Round       int         `json:"round"`
+		Priority    int         `json:"priority"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	if err := config.On.validate(); err != nil {
+		return err
+	}
+
+	skill, err := config.Skill.Build()
+	if err != nil {
+		return err
+	}
+
+	*t = Triggered{
+		Skill:       skill,
+		On:          config.On,
+		HealthBelow: config.HealthBelow,
+		Round:       config.Round,
+		Priority:    config.Priority,
+	}
+	return nil
+}
+
+// prioritized is implemented by the skills with a priority
+type prioritized interface {
+	priority() int
+}
+
+func skillPriority(skill Skill) int {
+	if p, ok := skill.(prioritized); ok {
+		return p.priority()
+	}
+	return 0
+}
+
+// sortSkills orders the skills by descending priority; skills
+// with the same priority keep the order in which they are listed
+func sortSkills(skills []Skill) []Skill {
+	sorted := append([]Skill{}, skills...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return skillPriority(sorted[i]) > skillPriority(sorted[j])
+	})
+	return sorted
+}
+
+// notify records that the event happened to the player
+func (p *Player) notify(event Event) {
+	if p.events == nil {
+		p.events = map[Event]int{}
+	}
+	p.events[event]++
+}
+
+// during returns the modifier of a pipe, which the player goes through
+// acting on the event of the pipe, OnAttack or OnDefend
+func (p *Player) during(event Event, modifier AttackModifier) AttackModifier {
+	return func(attack *Attack) *Attack {
+		acting := p.acting
+		p.acting = event
+		defer func() { p.acting = acting }()
+		return modifier(attack)
+	}
+}
+
+// resetEvents forgets the events of the previous duel, except for the
+// kill carried over to the next one, together with the events the
+// skills fired on
+func (p *Player) resetEvents() {
+	killed := p.events[OnKill] > 0
+	p.events = map[Event]int{}
+	if killed {
+		p.events[OnKill] = 1
+	}
+	for _, state := range p.skillStates {
+		state.Seen = 0
+	}
+}
+
+// Round returns the current round of the player's duel
+func (p *Player) Round() int {
+	return p.round
+}
+
+// Opponent returns the player fought in the current duel, if any
+func (p *Player) Opponent() *Player {
+	return p.opponent
+}
+
+// healthRatio returns the percentage of the starting health
+// the player has left; it is 1 outside of duels
+func (p *Player) healthRatio() float64 {
+	if p.startingHealth <= 0 {
+		return 1
+	}
+	return p.Health / p.startingHealth
+}
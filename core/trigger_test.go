@@ -0,0 +1,197 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// tag is a skill that always fires and records its name on the attack
+type tag string
+
+func (t tag) GetDescription() string { return string(t) }
+
+func (t tag) GetModifier(*Player) AttackModifier {
+	return func(attack *Attack) *Attack {
+		attack.UsedOffensiveSkills = append(attack.UsedOffensiveSkills, string(t))
+		return attack
+	}
+}
+
+func Test_pipeSkills_Priority(t *testing.T) {
+	p := NewPlayer("Hero", PlayerStats{}, PlayerSkills{})
+	skills := []Skill{
+		tag("first listed"),
+		&Triggered{Skill: tag("low priority"), Priority: -1},
+		&Triggered{Skill: tag("high priority"), Priority: 2},
+		tag("second listed"),
+	}
+
+	want := []string{"high priority", "first listed", "second listed", "low priority"}
+	if got := pipeSkills(p, skills)(NewAttack(10)).UsedOffensiveSkills; !reflect.DeepEqual(got, want) {
+		t.Errorf("pipeSkills() used %v, want %v", got, want)
+	}
+}
+
+func TestTriggered_GetModifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		skill     *Triggered
+		prepare   func(p *Player)
+		wantFired []bool
+	}{
+		{
+			name:      "fires on every attack without conditions",
+			skill:     &Triggered{Skill: tag("always")},
+			prepare:   func(p *Player) {},
+			wantFired: []bool{true, true},
+		},
+		{
+			name:  "fires once after being hit",
+			skill: &Triggered{Skill: tag("revenge"), On: OnHit},
+			prepare: func(p *Player) {
+				p.DefendAttack(NewAttack(10))
+			},
+			wantFired: []bool{true, false},
+		},
+		{
+			name:  "doesn't fire after evading instead of being hit",
+			skill: &Triggered{Skill: tag("revenge"), On: OnHit},
+			prepare: func(p *Player) {
+				p.Evasion = 1
+				p.DefendAttack(NewAttack(10))
+			},
+			wantFired: []bool{false, false},
+		},
+		{
+			name:  "fires once after evading",
+			skill: &Triggered{Skill: tag("riposte"), On: OnEvade},
+			prepare: func(p *Player) {
+				p.Evasion = 1
+				p.DefendAttack(NewAttack(10))
+			},
+			wantFired: []bool{true, false},
+		},
+		{
+			name:  "fires while the health is low",
+			skill: &Triggered{Skill: tag("last stand"), HealthBelow: 0.5},
+			prepare: func(p *Player) {
+				p.Health = 40
+			},
+			wantFired: []bool{true, true},
+		},
+		{
+			name:      "doesn't fire while the health is high",
+			skill:     &Triggered{Skill: tag("last stand"), HealthBelow: 0.5},
+			prepare:   func(p *Player) {},
+			wantFired: []bool{false, false},
+		},
+		{
+			name:  "fires in the given round",
+			skill: &Triggered{Skill: tag("opening"), Round: 1},
+			prepare: func(p *Player) {
+				p.round = 1
+			},
+			wantFired: []bool{true, true},
+		},
+		{
+			name:  "doesn't fire in other rounds",
+			skill: &Triggered{Skill: tag("opening"), Round: 1},
+			prepare: func(p *Player) {
+				p.round = 2
+			},
+			wantFired: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer("Hero", PlayerStats{Health: 100}, PlayerSkills{})
+			p.prepareForDuel(nil)
+			modifier := tt.skill.GetModifier(p)
+			tt.prepare(p)
+
+			for i, want := range tt.wantFired {
+				attack := modifier(NewAttack(10))
+				if got := len(attack.UsedOffensiveSkills) > 0; got != want {
+					t.Errorf("attack %d fired = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTriggered_OnAttackAndOnDefend(t *testing.T) {
+	skills := []Skill{
+		&Triggered{Skill: tag("on attack"), On: OnAttack},
+		&Triggered{Skill: tag("on defend"), On: OnDefend},
+	}
+	p := NewPlayer("Hero", PlayerStats{Health: 100, Strength: 10}, PlayerSkills{OffensiveSkills: skills, DefensiveSkills: skills})
+	p.prepareForDuel(nil)
+
+	for i := 0; i < 2; i++ {
+		if got := p.GenerateAttack().UsedOffensiveSkills; !reflect.DeepEqual(got, []string{"on attack"}) {
+			t.Errorf("attack %d used %v, want [on attack]", i+1, got)
+		}
+		if got := p.defensiveAttackModifier(NewAttack(10)).UsedOffensiveSkills; !reflect.DeepEqual(got, []string{"on defend"}) {
+			t.Errorf("defence %d used %v, want [on defend]", i+1, got)
+		}
+	}
+}
+
+func TestTriggered_EventsPerDuel(t *testing.T) {
+	p := NewPlayer("Hero", PlayerStats{Health: 100, Strength: 10}, PlayerSkills{
+		OffensiveSkills: []Skill{&Triggered{Skill: tag("revenge"), On: OnHit}},
+	})
+	p.prepareForDuel(nil)
+	p.DefendAttack(NewAttack(10))
+
+	p.prepareForDuel(nil)
+	if got := p.GenerateAttack().UsedOffensiveSkills; len(got) != 0 {
+		t.Errorf("the first attack of the next duel used %v, want none", got)
+	}
+	p.DefendAttack(NewAttack(10))
+	if got := p.GenerateAttack().UsedOffensiveSkills; !reflect.DeepEqual(got, []string{"revenge"}) {
+		t.Errorf("the attack after being hit used %v, want [revenge]", got)
+	}
+}
+
+// the kill carries over to the next duel, where it fires once
+func TestTriggered_OnKill(t *testing.T) {
+	hero := NewPlayer("Hero", PlayerStats{Health: 100, Strength: 50, Speed: 2}, PlayerSkills{
+		OffensiveSkills: []Skill{&Triggered{Skill: &CriticalStrike{DoubleStrikeChance: 1}, On: OnKill}},
+	})
+
+	for i, wantRounds := range []int{2, 1} {
+		villain := NewPlayer("Peanut", PlayerStats{Health: 100, Speed: 1}, PlayerSkills{})
+		dm := &DuelMaster{Rounds: 5, PlayerOne: hero, PlayerTwo: villain}
+		if got := dm.StartDuel(); got.Winner != hero || got.Rounds != wantRounds {
+			t.Errorf("duel %d = %+v, want the hero to win in %d rounds", i+1, got, wantRounds)
+		}
+	}
+}
+
+func TestTriggered_Config(t *testing.T) {
+	config := SkillConfig{}
+	data := `{"type": "Triggered", "params": {"skill": {"type": "SunderArmor", "params": {"Chance": 1, "Amount": 5}}, "on": "evade", "healthBelow": 0.3, "priority": 2}}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Triggered{Skill: &SunderArmor{Chance: 1, Amount: 5}, On: OnEvade, HealthBelow: 0.3, Priority: 2}
+	got, err := config.Build()
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %+v, %v, want %+v", got, err, want)
+	}
+	if got.GetDescription() != "Sunder Armor (100.00% chance to destroy 5.00 defence per hit) (on evade, below 30.00% health)" {
+		t.Errorf("GetDescription() = %q", got.GetDescription())
+	}
+
+	data = `{"type": "Triggered", "params": {"skill": {"type": "Luck", "params": {"Chance": 1}}, "on": "parry"}}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Build(); err == nil {
+		t.Errorf("Build() should fail for unknown events")
+	}
+}
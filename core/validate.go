@@ -0,0 +1,251 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Domain is the interval of the valid values of a stat or skill parameter
+type Domain struct {
+	Min float64
+	Max float64
+	// Open excludes Min from the domain
+	Open bool
+}
+
+// the domains of the stats and skill parameters
+var (
+	positive    = Domain{Min: 0, Max: math.Inf(1), Open: true}
+	nonNegative = Domain{Min: 0, Max: math.Inf(1)}
+	percentage  = Domain{Min: 0, Max: 1}
+	multiplier  = Domain{Min: 1, Max: math.Inf(1)}
+	// damageMultiplier bounds the damage multipliers of declarative skills
+	damageMultiplier = Domain{Min: 0, Max: 5}
+	// resistances can be negative, for the damage types a player is weak to
+	resistance = Domain{Min: math.Inf(-1), Max: 1}
+)
+
+// Contains checks whether the value is within the domain
+func (d Domain) Contains(v float64) bool {
+	if math.IsNaN(v) || v > d.Max {
+		return false
+	}
+	if d.Open {
+		return v > d.Min
+	}
+	return v >= d.Min
+}
+
+func (d Domain) String() string {
+	open, close := "[", "]"
+	if d.Open || math.IsInf(d.Min, -1) {
+		open = "("
+	}
+	if math.IsInf(d.Max, 1) {
+		close = ")"
+	}
+	return fmt.Sprintf("%s%g, %g%s", open, d.Min, d.Max, close)
+}
+
+// FieldError is a stat or skill parameter outside its domain
+type FieldError struct {
+	// Field is the path of the value within the player or the
+	// template, e.g. Luck or OffensiveSkills[0].DoubleStrikeChance
+	Field  string
+	Value  float64
+	Domain Domain
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s is %g, outside %s", e.Field, e.Value, e.Domain)
+}
+
+// ValidationError lists the values of a build outside their domains
+type ValidationError struct {
+	Name   string
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := []string{}
+	for _, f := range e.Fields {
+		fields = append(fields, f.Error())
+	}
+	return fmt.Sprintf("%s: %s", e.Name, strings.Join(fields, "; "))
+}
+
+// statDomains are the domains of the stats, in the order of PlayerStats;
+// the stats that can be unset keep their default when 0
+var statDomains = []struct {
+	name   string
+	domain Domain
+	unset  bool
+}{
+	{"Health", positive, false},
+	{"Strength", nonNegative, false},
+	{"Defence", nonNegative, false},
+	{"Speed", nonNegative, false},
+	{"Luck", percentage, false},
+	{"Accuracy", percentage, false},
+	{"Evasion", percentage, false},
+	{"CritChance", percentage, false},
+	{"CritMultiplier", multiplier, true},
+}
+
+func (ps PlayerStats) list() []float64 {
+	return []float64{ps.Health, ps.Strength, ps.Defence, ps.Speed, ps.Luck, ps.Accuracy, ps.Evasion, ps.CritChance, ps.CritMultiplier}
+}
+
+func (sr StatRanges) list() []StatRange {
+	return []StatRange{sr.Health, sr.Strength, sr.Defence, sr.Speed, sr.Luck, sr.Accuracy, sr.Evasion, sr.CritChance, sr.CritMultiplier}
+}
+
+// validator collects the values outside their domains
+type validator struct {
+	fields []*FieldError
+}
+
+func (v *validator) check(field string, value float64, d Domain) {
+	if !d.Contains(value) {
+		v.fields = append(v.fields, &FieldError{Field: field, Value: value, Domain: d})
+	}
+}
+
+func (v *validator) err(name string) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Name: name, Fields: v.fields}
+}
+
+// statRange checks both ends of the range, or the value of fixed
+// ranges; the minimum can't be above the maximum
+func (v *validator) statRange(field string, r StatRange, d Domain) {
+	if r.Min == r.Max {
+		v.check(field, r.Min, d)
+		return
+	}
+	v.check(field+".Max", r.Max, d)
+	if r.Max < d.Max && r.Max >= d.Min {
+		d.Max = r.Max
+	}
+	v.check(field+".Min", r.Min, d)
+}
+
+func (v *validator) skills(field string, skills []Skill) {
+	for i, skill := range skills {
+		v.skill(fmt.Sprintf("%s[%d]", field, i), skill)
+	}
+}
+
+// skill checks the parameters of the built-in skills, including the
+// skills they wrap; the parameters of other skills aren't checked
+func (v *validator) skill(field string, skill Skill) {
+	switch s := skill.(type) {
+	case *CriticalStrike:
+		v.check(field+".DoubleStrikeChance", s.DoubleStrikeChance, percentage)
+		v.check(field+".TripleStrikeChance", s.TripleStrikeChance, percentage)
+	case *Resilience:
+		v.check(field+".Chance", s.Chance, percentage)
+		v.check(field+".DamageReduction", s.DamageReduction, percentage)
+	case *Luck:
+		v.check(field+".Chance", s.Chance, percentage)
+	case *PiercingStrike:
+		v.check(field+".Chance", s.Chance, percentage)
+		v.check(field+".Penetration", s.Penetration, nonNegative)
+		v.check(field+".Ratio", s.Ratio, percentage)
+	case *SunderArmor:
+		v.check(field+".Chance", s.Chance, percentage)
+		v.check(field+".Amount", s.Amount, nonNegative)
+	case *ElementalInfusion:
+		v.check(field+".Chance", s.Chance, percentage)
+		v.check(field+".Damage", s.Damage, nonNegative)
+	case *ElementalConversion:
+		v.check(field+".Chance", s.Chance, percentage)
+		v.check(field+".Ratio", s.Ratio, percentage)
+	case *Costly:
+		v.skill(field+".Skill", s.Skill)
+		v.check(field+".Amount", s.Amount, nonNegative)
+	case *Triggered:
+		v.skill(field+".Skill", s.Skill)
+		v.check(field+".HealthBelow", s.HealthBelow, percentage)
+	case *Declarative:
+		v.declarative(field+".Spec", s.Spec)
+	}
+}
+
+func (v *validator) declarative(field string, spec SkillSpec) {
+	if spec.Chance != nil {
+		v.check(field+".Chance", *spec.Chance, percentage)
+	}
+	v.check(field+".When.HealthBelow", spec.When.HealthBelow, percentage)
+	v.check(field+".Recovery", float64(spec.Recovery), nonNegative)
+
+	for i, effect := range spec.Effects {
+		effectField := fmt.Sprintf("%s.Effects[%d]", field, i)
+		if effect.Chance != nil {
+			v.check(effectField+".Chance", *effect.Chance, percentage)
+		}
+		switch effect.Type {
+		case ReduceDamage, Heal:
+			v.check(effectField+".Amount", effect.Amount, percentage)
+		case MultiplyDamage:
+			v.check(effectField+".Amount", effect.Amount, damageMultiplier)
+		default:
+			v.check(effectField+".Amount", effect.Amount, nonNegative)
+		}
+	}
+}
+
+func (v *validator) abilities(abilities []*Ability) {
+	for i, ability := range abilities {
+		field := fmt.Sprintf("Abilities[%d]", i)
+		v.skill(field+".Skill", ability.Skill)
+		v.check(field+".Cooldown", float64(ability.Cooldown), nonNegative)
+		v.check(field+".Cost.Amount", ability.Cost.Amount, nonNegative)
+	}
+}
+
+func (v *validator) resistances(field string, resistances Resistances) {
+	for _, t := range DamageTypes {
+		if r, ok := resistances[t]; ok {
+			v.check(fmt.Sprintf("%s[%s]", field, t), r, resistance)
+		}
+	}
+}
+
+// Validate checks that the stats, resistances and skill parameters of
+// the player are within their domains, e.g. that chances are within
+// [0, 1] and damage reductions don't exceed 100%; the error is a
+// *ValidationError listing all of them
+func (p *Player) Validate() error {
+	v := &validator{}
+	for i, value := range p.PlayerStats.list() {
+		if s := statDomains[i]; !s.unset || value != 0 {
+			v.check(s.name, value, s.domain)
+		}
+	}
+	v.resistances("Resistances", p.Resistances)
+	v.skills("OffensiveSkills", p.OffensiveSkills)
+	v.skills("DefensiveSkills", p.DefensiveSkills)
+	v.abilities(p.Abilities)
+	return v.err(p.Name)
+}
+
+// Validate checks that the stat ranges of the template are within the
+// domains of the stats, and not reversed, and that its resistances and
+// skill parameters are within their domains; the error is a *ValidationError
+func (pt PlayerTemplate) Validate() error {
+	v := &validator{}
+	for i, r := range pt.Stats.list() {
+		if s := statDomains[i]; !s.unset || r != (StatRange{}) {
+			v.statRange("Stats."+s.name, r, s.domain)
+		}
+	}
+	v.resistances("Resistances", pt.Resistances)
+	v.skills("Skills.OffensiveSkills", pt.Skills.OffensiveSkills)
+	v.skills("Skills.DefensiveSkills", pt.Skills.DefensiveSkills)
+	v.abilities(pt.Abilities)
+	return v.err(pt.Name)
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDomain_Contains(t *testing.T) {
+	tests := []struct {
+		name   string
+		domain Domain
+		value  float64
+		want   bool
+	}{
+		{"percentage min", percentage, 0, true},
+		{"percentage max", percentage, 1, true},
+		{"above percentage", percentage, 1.01, false},
+		{"below percentage", percentage, -0.01, false},
+		{"positive zero", positive, 0, false},
+		{"positive", positive, 0.1, true},
+		{"not a number", nonNegative, math.NaN(), false},
+		{"negative resistance", resistance, -0.5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.domain.Contains(tt.value); got != tt.want {
+				t.Errorf("%s.Contains(%v) = %v, want %v", tt.domain, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomain_String(t *testing.T) {
+	tests := []struct {
+		domain Domain
+		want   string
+	}{
+		{percentage, "[0, 1]"},
+		{positive, "(0, +Inf)"},
+		{nonNegative, "[0, +Inf)"},
+		{resistance, "(-Inf, 1]"},
+	}
+	for _, tt := range tests {
+		if got := tt.domain.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// fields returns the fields of the validation error
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("the error %v isn't a *ValidationError", err)
+	}
+	names := []string{}
+	for _, f := range verr.Fields {
+		names = append(names, f.Field)
+	}
+	return names
+}
+
+func TestPlayer_Validate(t *testing.T) {
+	valid := PlayerStats{Health: 100, Strength: 70, Defence: 50, Speed: 40, Luck: 0.2, Evasion: 0.1}
+
+	tests := []struct {
+		name   string
+		player func() *Player
+		want   []string
+	}{
+		{"valid", func() *Player {
+			return NewPlayer("Hero", valid, PlayerSkills{
+				OffensiveSkills: []Skill{&CriticalStrike{DoubleStrikeChance: 0.1, TripleStrikeChance: 0.01}},
+				DefensiveSkills: []Skill{&Resilience{Chance: 0.2, DamageReduction: 0.5}},
+			})
+		}, nil},
+		{"out of domain stats", func() *Player {
+			stats := valid
+			stats.Luck, stats.Defence, stats.Health, stats.CritMultiplier = 5, -10, 0, 0.5
+			return NewPlayer("Hero", stats, PlayerSkills{})
+		}, []string{"Health", "Defence", "Luck", "CritMultiplier"}},
+		{"unset crit multiplier", func() *Player {
+			stats := valid
+			stats.CritChance = 0.2
+			return NewPlayer("Hero", stats, PlayerSkills{})
+		}, nil},
+		{"skill parameters", func() *Player {
+			return NewPlayer("Hero", valid, PlayerSkills{
+				OffensiveSkills: []Skill{
+					&CriticalStrike{DoubleStrikeChance: 1.5},
+					&Triggered{Skill: &PiercingStrike{Chance: 0.5, Ratio: 2}, HealthBelow: 0.3},
+				},
+				DefensiveSkills: []Skill{&Resilience{Chance: 0.2, DamageReduction: 1.2}},
+			})
+		}, []string{"OffensiveSkills[0].DoubleStrikeChance", "OffensiveSkills[1].Skill.Ratio", "DefensiveSkills[0].DamageReduction"}},
+		{"declarative skill", func() *Player {
+			chance := -0.1
+			return NewPlayer("Hero", valid, PlayerSkills{DefensiveSkills: []Skill{&Declarative{Spec: SkillSpec{
+				Name:      "Ward",
+				Defensive: true,
+				Chance:    &chance,
+				Recovery:  -1,
+				Effects:   []EffectSpec{{Type: ReduceDamage, Amount: 1.5}, {Type: MultiplyDamage, Amount: 3}, {Type: MultiplyDamage, Amount: 10}},
+			}}}})
+		}, []string{"DefensiveSkills[0].Spec.Chance", "DefensiveSkills[0].Spec.Recovery", "DefensiveSkills[0].Spec.Effects[0].Amount", "DefensiveSkills[0].Spec.Effects[2].Amount"}},
+		{"resistances and abilities", func() *Player {
+			p := NewPlayer("Hero", valid, PlayerSkills{})
+			p.Resistances = Resistances{Fire: 1.5, Frost: -0.5}
+			p.Abilities = []*Ability{{Skill: &SunderArmor{Chance: 1, Amount: -5}, Cooldown: -1}}
+			return p
+		}, []string{"Resistances[fire]", "Abilities[0].Skill.Amount", "Abilities[0].Cooldown"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(t, tt.player().Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() reported %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlayerTemplate_Validate(t *testing.T) {
+	valid := StatRanges{
+		Health:   StatRange{Min: 70, Max: 100},
+		Strength: StatRange{Min: 70, Max: 80},
+		Luck:     StatRange{Min: 0.1, Max: 0.3},
+	}
+
+	tests := []struct {
+		name  string
+		stats func(StatRanges) StatRanges
+		want  []string
+	}{
+		{"valid", func(sr StatRanges) StatRanges { return sr }, nil},
+		{"out of domain", func(sr StatRanges) StatRanges {
+			sr.Luck = StatRange{Min: 0.5, Max: 5}
+			return sr
+		}, []string{"Stats.Luck.Max"}},
+		{"reversed", func(sr StatRanges) StatRanges {
+			sr.Strength = StatRange{Min: 90, Max: 80}
+			return sr
+		}, []string{"Stats.Strength.Min"}},
+		{"no health", func(sr StatRanges) StatRanges {
+			sr.Health = StatRange{}
+			return sr
+		}, []string{"Stats.Health"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := PlayerTemplate{Name: "Hero", Stats: tt.stats(valid)}
+			if got := fields(t, pt.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() reported %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := NewPlayer("Hero", PlayerStats{Health: 100, Luck: 5}, PlayerSkills{}).Validate()
+	if want := "Hero: Luck is 5, outside [0, 1]"; err == nil || err.Error() != want {
+		t.Errorf("Validate() = %v, want %s", err, want)
+	}
+}
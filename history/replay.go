@@ -0,0 +1,162 @@
+package history
+
+import (
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// EventType represents the kind of event of a replay
+type EventType string
+
+// the events of a duel
+const (
+	RoundEvent    EventType = "round"
+	AttackEvent   EventType = "attack"
+	ActionEvent   EventType = "action"
+	KnockoutEvent EventType = "knockout"
+	FleeEvent     EventType = "flee"
+	TieEvent      EventType = "tie"
+)
+
+// HitEvent is a hit of an attack, once defended
+type HitEvent struct {
+	Damage float64  `json:"damage"`
+	Evaded bool     `json:"evaded,omitempty"`
+	Skills []string `json:"skills,omitempty"`
+}
+
+// Event is a step of a duel; Player is the one acting (the attacker,
+// the winner of a knockout or the one fleeing) and Opponent the other one
+type Event struct {
+	Round    int       `json:"round"`
+	Type     EventType `json:"type"`
+	Player   string    `json:"player,omitempty"`
+	Opponent string    `json:"opponent,omitempty"`
+
+	// Action is the action taken, for action events
+	Action string `json:"action,omitempty"`
+	// Hits and Skills describe an attack; Health is the
+	// opponent's health once the attack was defended
+	Hits   []HitEvent `json:"hits,omitempty"`
+	Skills []string   `json:"skills,omitempty"`
+	Health *float64   `json:"health,omitempty"`
+}
+
+// Fighter is a snapshot of a player taken when the duel started
+type Fighter struct {
+	Name      string           `json:"name"`
+	Level     int              `json:"level"`
+	Stats     core.PlayerStats `json:"stats"`
+	Offensive []string         `json:"offensive,omitempty"`
+	Defensive []string         `json:"defensive,omitempty"`
+	Equipment []string         `json:"equipment,omitempty"`
+}
+
+// Snapshot describes the player
+func Snapshot(p *core.Player) Fighter {
+	f := Fighter{Name: p.Name, Level: p.Level, Stats: p.PlayerStats}
+	for _, skill := range p.OffensiveSkills {
+		f.Offensive = append(f.Offensive, skill.GetDescription())
+	}
+	for _, skill := range p.DefensiveSkills {
+		f.Defensive = append(f.Defensive, skill.GetDescription())
+	}
+	for _, item := range p.Equipment() {
+		f.Equipment = append(f.Equipment, item.GetDescription())
+	}
+	return f
+}
+
+// Recorder is the commentator recording the replay of a duel;
+// it is reset when a new duel starts
+type Recorder struct {
+	Fighters []Fighter
+	Events   []Event
+
+	// OnFighters and OnEvent, when set, are called as soon as
+	// the fighters are presented and every event is recorded
+	OnFighters func([]Fighter)
+	OnEvent    func(Event)
+
+	round int
+}
+
+func (r *Recorder) record(e Event) {
+	r.Events = append(r.Events, e)
+	if r.OnEvent != nil {
+		r.OnEvent(e)
+	}
+}
+
+// Start starts recording a new duel
+func (r *Recorder) Start() {
+	r.Fighters, r.Events, r.round = nil, nil, 0
+}
+
+// PresentPlayers takes the snapshots of the fighters
+func (r *Recorder) PresentPlayers(first, second *core.Player) {
+	r.Fighters = []Fighter{Snapshot(first), Snapshot(second)}
+	if r.OnFighters != nil {
+		r.OnFighters(r.Fighters)
+	}
+}
+
+// PresentRound records the start of the round
+func (r *Recorder) PresentRound(round int) {
+	r.round = round
+	r.record(Event{Round: round, Type: RoundEvent})
+}
+
+// PresentResources does nothing; it's part of the commentator interface
+func (r *Recorder) PresentResources(first, second *core.Player) {}
+
+// PresentAttack records the attack and its hits
+func (r *Recorder) PresentAttack(attack *core.Attack, attacker, defender *core.Player) {
+	e := Event{
+		Round:    r.round,
+		Type:     AttackEvent,
+		Player:   attacker.Name,
+		Opponent: defender.Name,
+		Skills:   append(append([]string{}, attack.UsedOffensiveSkills...), attack.UsedDefensiveSkills...),
+	}
+	for _, hit := range attack.Hits {
+		// the hits left once the defender died aren't defended
+		if hit.Damage == nil {
+			continue
+		}
+		e.Hits = append(e.Hits, HitEvent{
+			Damage: hit.Damage.Total(),
+			Evaded: hit.TotalPotentialDamage() == 0,
+			Skills: append(append([]string{}, hit.UsedOffensiveSkills...), hit.UsedDefensiveSkills...),
+		})
+	}
+	if len(e.Skills) == 0 {
+		e.Skills = nil
+	}
+	health := defender.Health
+	e.Health = &health
+	r.record(e)
+}
+
+// PresentAction records the actions other than plain attacks
+func (r *Recorder) PresentAction(action core.Action, player *core.Player) {
+	e := Event{Round: r.round, Type: ActionEvent, Player: player.Name, Action: action.Kind.String()}
+	if action.Ability != nil && action.Ability.Skill != nil {
+		e.Skills = []string{action.Ability.Skill.GetDescription()}
+	}
+	r.record(e)
+}
+
+// EndDuelKnockout records the knockout
+func (r *Recorder) EndDuelKnockout(round int, winner, loser *core.Player) {
+	r.record(Event{Round: round, Type: KnockoutEvent, Player: winner.Name, Opponent: loser.Name})
+}
+
+// EndDuelFlee records the fleeing
+func (r *Recorder) EndDuelFlee(round int, fled, winner *core.Player) {
+	r.record(Event{Round: round, Type: FleeEvent, Player: fled.Name, Opponent: winner.Name})
+}
+
+// EndDuelTie records the tie
+func (r *Recorder) EndDuelTie(round int, player1, player2 *core.Player) {
+	r.record(Event{Round: round, Type: TieEvent, Player: player1.Name, Opponent: player2.Name})
+}
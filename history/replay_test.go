@@ -0,0 +1,53 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+func TestRecorder(t *testing.T) {
+	// the hero needs 2 hits to knock the villain out and
+	// evades the villain's only hit
+	hero := core.NewPlayer("Hero", core.PlayerStats{Health: 100, Strength: 50, Speed: 2, Evasion: 1}, core.PlayerSkills{})
+	villain := core.NewPlayer("Villain", core.PlayerStats{Health: 80, Strength: 40, Speed: 1}, core.PlayerSkills{})
+
+	recorder := &Recorder{}
+	dm := &core.DuelMaster{Rounds: 20, PlayerOne: hero, PlayerTwo: villain}
+	r := NewRecord(dm.StartDuel(recorder), recorder)
+
+	if r.Winner != "Hero" || r.Loser != "Villain" || !r.Knockout || r.Rounds != 2 {
+		t.Errorf("NewRecord() = %s", r)
+	}
+	if len(r.Fighters) != 2 || r.Fighters[0].Name != "Hero" || r.Fighters[1].Stats.Health != 80 {
+		t.Errorf("NewRecord() fighters = %+v, want the snapshots taken before the duel", r.Fighters)
+	}
+
+	types := []EventType{RoundEvent, AttackEvent, AttackEvent, RoundEvent, AttackEvent, KnockoutEvent}
+	if len(r.Replay) != len(types) {
+		t.Fatalf("NewRecord() replay = %+v, want %d events", r.Replay, len(types))
+	}
+	for i, e := range r.Replay {
+		if e.Type != types[i] {
+			t.Errorf("event %d is %s, want %s", i, e.Type, types[i])
+		}
+	}
+
+	if evaded := r.Replay[2]; evaded.Player != "Villain" || !evaded.Hits[0].Evaded || *evaded.Health != 100 {
+		t.Errorf("the villain's attack = %+v, want it evaded", evaded)
+	}
+	if last := r.Replay[4]; last.Hits[0].Damage != 50 || *last.Health != 0 {
+		t.Errorf("the last attack = %+v, want it to knock the villain out", last)
+	}
+}
+
+func TestNewRecord_Paused(t *testing.T) {
+	hero := core.NewPlayer("Hero", core.PlayerStats{Health: 100, Strength: 50, Speed: 2, Evasion: 1}, core.PlayerSkills{})
+	villain := core.NewPlayer("Villain", core.PlayerStats{Health: 80, Strength: 40, Speed: 1}, core.PlayerSkills{})
+
+	dm := &core.DuelMaster{Rounds: 20, PauseAfter: 1, PlayerOne: hero, PlayerTwo: villain}
+	r := NewRecord(dm.StartDuel(), nil)
+	if !r.Paused || r.IsTie() || r.Winner != "" || r.Rounds != 1 {
+		t.Errorf("NewRecord() = %+v, want a paused duel", r)
+	}
+}
@@ -0,0 +1,443 @@
+// Package history keeps the results, replays and fighter snapshots
+// of duels in an embedded BoltDB database.
+//
+// The database holds the records, as JSON documents keyed by their ID,
+// and an index of the IDs of the duels of every fighter, which answers
+// the queries naming a fighter; the other queries (e.g. all the
+// knockouts) scan the records, newest first. Histories without a
+// database are kept in memory and only keep the last duels
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/simulation"
+)
+
+// ErrNotFound is returned for records that aren't in the history
+var ErrNotFound = errors.New("history: record not found")
+
+// Record is a duel saved in the history
+type Record struct {
+	ID       int64     `json:"id"`
+	Time     time.Time `json:"time"`
+	Rounds   int       `json:"rounds"`
+	Knockout bool      `json:"knockout,omitempty"`
+	Fled     bool      `json:"fled,omitempty"`
+	// Paused is set for duels paused after Rounds rounds
+	Paused bool `json:"paused,omitempty"`
+	// Winner and Loser are empty when the duel ended with a tie or was paused
+	Winner string `json:"winner,omitempty"`
+	Loser  string `json:"loser,omitempty"`
+	// Seed is the seed of math/rand the duel was fought with, when known
+	Seed int64 `json:"seed,omitempty"`
+
+	// Fighters are the snapshots of the fighters, in the order they attacked
+	Fighters []Fighter `json:"fighters"`
+	Replay   []Event   `json:"replay,omitempty"`
+}
+
+// NewRecord builds the record of a duel from its result and
+// from the recorder that commented it, if any
+func NewRecord(result *core.DuelResult, recorder *Recorder) *Record {
+	r := &Record{Rounds: result.Rounds, Knockout: result.Knockout, Fled: result.Fled, Paused: result.Paused}
+	if result.Winner != nil {
+		r.Winner, r.Loser = result.Winner.Name, result.Loser.Name
+	}
+	if recorder != nil {
+		r.Fighters = append(r.Fighters, recorder.Fighters...)
+		r.Replay = append(r.Replay, recorder.Events...)
+	} else {
+		r.Fighters = []Fighter{Snapshot(result.First), Snapshot(result.Second)}
+	}
+	return r
+}
+
+// IsTie checks whether the duel ended without a winner;
+// paused duels haven't ended yet
+func (r *Record) IsTie() bool {
+	return r.Winner == "" && !r.Paused
+}
+
+// Fought checks whether the fighter took part in the duel
+func (r *Record) Fought(name string) bool {
+	for _, f := range r.Fighters {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Record) String() string {
+	names := [2]string{"?", "?"}
+	for i := 0; i < len(r.Fighters) && i < 2; i++ {
+		names[i] = r.Fighters[i].Name
+	}
+	duel := fmt.Sprintf("#%d %s %s vs %s: ", r.ID, r.Time.Format(time.RFC3339), names[0], names[1])
+	switch {
+	case r.Paused:
+		return duel + fmt.Sprintf("paused after %d rounds", r.Rounds)
+	case r.IsTie():
+		return duel + fmt.Sprintf("tie after %d rounds", r.Rounds)
+	case r.Fled:
+		return duel + fmt.Sprintf("%s fled from %s on round %d", r.Loser, r.Winner, r.Rounds)
+	}
+	return duel + fmt.Sprintf("%s knocked %s out on round %d", r.Winner, r.Loser, r.Rounds)
+}
+
+// Filter selects the records of the history; the zero value selects all of them
+type Filter struct {
+	// Fighter selects the duels of the fighter and Opponent,
+	// together with Fighter, the duels between the two
+	Fighter  string
+	Opponent string
+	Winner   string
+	Loser    string
+	// Knockout selects the duels that ended with a knockout
+	Knockout bool
+	// Limit is the maximum number of records; 0 means no limit
+	Limit int
+}
+
+func (f Filter) matches(r *Record) bool {
+	switch {
+	case f.Fighter != "" && !r.Fought(f.Fighter),
+		f.Opponent != "" && !r.Fought(f.Opponent),
+		f.Winner != "" && r.Winner != f.Winner,
+		f.Loser != "" && r.Loser != f.Loser,
+		f.Knockout && !r.Knockout:
+		return false
+	}
+	return true
+}
+
+// Retention is the number of duels kept by the histories kept in memory
+const Retention = 10000
+
+// the buckets of the database: the records by ID and, for every
+// fighter, a bucket of the IDs of his duels
+var (
+	duelsBucket    = []byte("duels")
+	fightersBucket = []byte("fighters")
+)
+
+// Store is the history of duels, kept in a database or, when created by
+// New, in memory. It is safe for concurrent use
+type Store struct {
+	db *bolt.DB
+
+	mu sync.RWMutex
+	// records are the last records of the history kept in memory
+	records []*Record
+	// fought indexes the IDs of the records, in increasing order, by fighter
+	fought map[string][]int64
+	// lastID is the ID of the last record saved in memory
+	lastID int64
+}
+
+// New creates a history that is only kept in memory; it keeps the
+// last Retention duels and forgets the older ones
+func New() *Store {
+	return &Store{fought: map[string][]int64{}}
+}
+
+// Open opens the database file, creating it if needed; it waits a
+// second at most for the other processes using it to close it
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("history: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{duelsBucket, fightersBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("history: %v", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database file
+func (s *Store) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// key encodes the ID in the order of the IDs
+func key(id int64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(id))
+	return k
+}
+
+// fighters returns the names of the fighters of the record, once each
+func (r *Record) fighters() []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, f := range r.Fighters {
+		if f.Name != "" && !seen[f.Name] {
+			names, seen[f.Name] = append(names, f.Name), true
+		}
+	}
+	return names
+}
+
+// Save adds the record to the history; it gets
+// the next ID and, when not set, the current time
+func (s *Store) Save(r *Record) error {
+	saved := *r
+	if saved.Time.IsZero() {
+		saved.Time = time.Now().UTC()
+	}
+
+	if s.db == nil {
+		s.mu.Lock()
+		s.lastID++
+		saved.ID = s.lastID
+		s.add(&saved)
+		s.mu.Unlock()
+	} else {
+		err := s.db.Update(func(tx *bolt.Tx) error {
+			duels := tx.Bucket(duelsBucket)
+			id, err := duels.NextSequence()
+			if err != nil {
+				return err
+			}
+			saved.ID = int64(id)
+			data, err := json.Marshal(&saved)
+			if err != nil {
+				return err
+			}
+			if err := duels.Put(key(saved.ID), data); err != nil {
+				return err
+			}
+
+			for _, name := range saved.fighters() {
+				fought, err := tx.Bucket(fightersBucket).CreateBucketIfNotExists([]byte(name))
+				if err != nil {
+					return err
+				}
+				if err := fought.Put(key(saved.ID), []byte{}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("history: %v", err)
+		}
+	}
+
+	r.ID, r.Time = saved.ID, saved.Time
+	return nil
+}
+
+// add adds the record to the records kept in memory and to their
+// index, forgetting the oldest record beyond the Retention
+func (s *Store) add(r *Record) {
+	s.records = append(s.records, r)
+	for _, name := range r.fighters() {
+		s.fought[name] = append(s.fought[name], r.ID)
+	}
+	if len(s.records) <= Retention {
+		return
+	}
+
+	oldest := s.records[0]
+	s.records[0] = nil
+	s.records = s.records[1:]
+	for _, name := range oldest.fighters() {
+		if s.fought[name] = s.fought[name][1:]; len(s.fought[name]) == 0 {
+			delete(s.fought, name)
+		}
+	}
+}
+
+// Len returns the number of records in the history
+func (s *Store) Len() int {
+	if s.db == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return len(s.records)
+	}
+
+	n := 0
+	s.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(duelsBucket).Stats().KeyN
+		return nil
+	})
+	return n
+}
+
+// Get returns the record with the given ID
+func (s *Store) Get(id int64) (Record, error) {
+	if s.db == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		if r := s.record(id); r != nil {
+			return *r, nil
+		}
+		return Record{}, ErrNotFound
+	}
+
+	var r Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(duelsBucket).Get(key(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &r)
+	})
+	if err != nil && err != ErrNotFound {
+		return Record{}, fmt.Errorf("history: record %d: %v", id, err)
+	}
+	return r, err
+}
+
+// record returns the record kept in memory with the given ID, if any
+func (s *Store) record(id int64) *Record {
+	// the IDs are increasing but the oldest records are forgotten
+	lo, hi := 0, len(s.records)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if s.records[mid].ID < id {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == len(s.records) || s.records[lo].ID != id {
+		return nil
+	}
+	return s.records[lo]
+}
+
+// Find returns the records selected by the filter, newest first
+func (s *Store) Find(f Filter) ([]Record, error) {
+	records := []Record{}
+	visit := func(r *Record) bool {
+		if f.matches(r) {
+			records = append(records, *r)
+		}
+		return f.Limit <= 0 || len(records) < f.Limit
+	}
+
+	if s.db == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		s.findInMemory(f, visit)
+		return records, nil
+	}
+	if err := s.db.View(func(tx *bolt.Tx) error { return find(tx, f, visit) }); err != nil {
+		return nil, fmt.Errorf("history: %v", err)
+	}
+	return records, nil
+}
+
+// findInMemory visits the records kept in memory that can match the
+// filter, newest first, until visit returns false; the records of the
+// fighter of the filter that fought the fewest duels are the only ones
+// visited, as the winner and the loser fought the duels too
+func (s *Store) findInMemory(f Filter, visit func(*Record) bool) {
+	var ids []int64
+	indexed := false
+	for _, name := range f.fighters() {
+		if fought := s.fought[name]; !indexed || len(fought) < len(ids) {
+			ids, indexed = fought, true
+		}
+	}
+
+	if !indexed {
+		for i := len(s.records) - 1; i >= 0; i-- {
+			if !visit(s.records[i]) {
+				return
+			}
+		}
+		return
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		if !visit(s.record(ids[i])) {
+			return
+		}
+	}
+}
+
+// find visits the records of the database that can match the filter,
+// newest first, until visit returns false; when the filter names a
+// fighter, only his records are visited
+func find(tx *bolt.Tx, f Filter, visit func(*Record) bool) error {
+	duels := tx.Bucket(duelsBucket)
+	cursor := duels.Cursor()
+	if names := f.fighters(); len(names) > 0 {
+		fought := tx.Bucket(fightersBucket).Bucket([]byte(names[0]))
+		if fought == nil {
+			return nil
+		}
+		cursor = fought.Cursor()
+	}
+
+	for k, data := cursor.Last(); k != nil; k, data = cursor.Prev() {
+		if len(data) == 0 {
+			data = duels.Get(k)
+		}
+		r := &Record{}
+		if err := json.Unmarshal(data, r); err != nil {
+			return fmt.Errorf("record %d: %v", binary.BigEndian.Uint64(k), err)
+		}
+		if !visit(r) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// fighters returns the fighters named by the filter
+func (f Filter) fighters() []string {
+	names := []string{}
+	for _, name := range []string{f.Fighter, f.Opponent, f.Winner, f.Loser} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Matchup aggregates the duels between the two fighters;
+// the first player of the stats is the first fighter
+func (s *Store) Matchup(one, two string) (simulation.Stats, error) {
+	stats := simulation.Stats{}
+	records, err := s.Find(Filter{Fighter: one, Opponent: two})
+	if err != nil {
+		return stats, err
+	}
+	for _, r := range records {
+		stats.Duels++
+		stats.TotalRounds += r.Rounds
+		switch r.Winner {
+		case one:
+			stats.PlayerOneWins++
+		case two:
+			stats.PlayerTwoWins++
+		default:
+			stats.Ties++
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,128 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/simulation"
+)
+
+func record(one, two, winner string, rounds int, knockout bool) *Record {
+	r := &Record{Rounds: rounds, Knockout: knockout, Fighters: []Fighter{{Name: one}, {Name: two}}}
+	if winner != "" {
+		r.Winner, r.Loser = winner, one
+		if winner == one {
+			r.Loser = two
+		}
+	}
+	return r
+}
+
+func ids(records []Record) []int64 {
+	ids := []int64{}
+	for _, r := range records {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned an unexpected error: %v", err)
+	}
+
+	stores := map[string]*Store{"memory": New(), "database": db}
+	for _, s := range stores {
+		for _, r := range []*Record{
+			record("Hero", "Peanut", "Peanut", 4, true),
+			record("Hero", "Peanut", "Hero", 6, true),
+			record("Peanut", "Ogre", "Peanut", 20, false),
+			record("Peanut", "Hero", "", 20, false),
+			record("Peanut", "Hero", "Peanut", 2, true),
+		} {
+			if err := s.Save(r); err != nil {
+				t.Fatalf("Save() returned an unexpected error: %v", err)
+			}
+		}
+	}
+	db.Close()
+
+	// the records are read back from the file
+	if stores["database"], err = Open(path); err != nil {
+		t.Fatalf("Open() returned an unexpected error: %v", err)
+	}
+	defer stores["database"].Close()
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int64
+	}{
+		{name: "all", want: []int64{5, 4, 3, 2, 1}},
+		{name: "won by Peanut", filter: Filter{Winner: "Peanut"}, want: []int64{5, 3, 1}},
+		{name: "lost by Hero", filter: Filter{Loser: "Hero"}, want: []int64{5, 1}},
+		{name: "matchup", filter: Filter{Fighter: "Hero", Opponent: "Peanut"}, want: []int64{5, 4, 2, 1}},
+		{name: "last 2 knockouts", filter: Filter{Knockout: true, Limit: 2}, want: []int64{5, 2}},
+		{name: "unknown fighter", filter: Filter{Fighter: "Nobody"}, want: []int64{}},
+		{name: "won against Ogre", filter: Filter{Fighter: "Ogre", Winner: "Peanut"}, want: []int64{3}},
+		{name: "last matchup", filter: Filter{Fighter: "Peanut", Opponent: "Hero", Limit: 1}, want: []int64{5}},
+	}
+	for name, s := range stores {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				records, err := s.Find(tt.filter)
+				if got := ids(records); err != nil || !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Find(%+v) = %v, %v, want %v", tt.filter, got, err, tt.want)
+				}
+			})
+		}
+
+		want := simulation.Stats{Duels: 4, PlayerOneWins: 1, PlayerTwoWins: 2, Ties: 1, TotalRounds: 32}
+		if got, err := s.Matchup("Hero", "Peanut"); err != nil || got != want {
+			t.Errorf("%s: Matchup() = %+v, %v, want %+v", name, got, err, want)
+		}
+
+		if r, err := s.Get(3); err != nil || r.Winner != "Peanut" || r.Fighters[1].Name != "Ogre" || r.Time.IsZero() {
+			t.Errorf("%s: Get(3) = %+v, %v", name, r, err)
+		}
+		if _, err := s.Get(6); err != ErrNotFound {
+			t.Errorf("%s: Get(6) returned %v, want %v", name, err, ErrNotFound)
+		}
+		if n := s.Len(); n != 5 {
+			t.Errorf("%s: Len() = %d, want 5", name, n)
+		}
+	}
+}
+
+func TestNew_Retention(t *testing.T) {
+	s := New()
+	s.Save(record("Hero", "Ogre", "Hero", 3, true))
+	for i := 0; i < Retention; i++ {
+		s.Save(record("Hero", "Peanut", "Peanut", 4, true))
+	}
+
+	if n := s.Len(); n != Retention {
+		t.Errorf("Len() = %d, want %d", n, Retention)
+	}
+	if _, err := s.Get(1); err != ErrNotFound {
+		t.Errorf("Get(1) returned %v, want the oldest duel to be forgotten", err)
+	}
+	if records, _ := s.Find(Filter{Fighter: "Ogre"}); len(records) != 0 {
+		t.Errorf("Find() returned %d duels of Ogre, want none", len(records))
+	}
+	if r, err := s.Get(Retention + 1); err != nil || r.Loser != "Hero" {
+		t.Errorf("Get(%d) = %+v, %v", Retention+1, r, err)
+	}
+}
+
+func TestOpen_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	os.WriteFile(path, []byte("{\"id\":1}\nnot json\n{\"id\":2}\n"), 0644)
+	if _, err := Open(path); err == nil {
+		t.Errorf("Open() should return an error for a corrupted database")
+	}
+}
@@ -0,0 +1,18 @@
+// Package testutil holds the fixtures shared by the tests of the other packages
+package testutil
+
+import "github.com/pfzero/battle-simulator/core"
+
+// Template creates a template without skills whose stats always
+// have the given values, for duels whose outcome is known in advance
+func Template(name string, health, strength, defence, speed float64) core.PlayerTemplate {
+	return core.PlayerTemplate{
+		Name: name,
+		Stats: core.StatRanges{
+			Health:   core.StatRange{Min: health, Max: health},
+			Strength: core.StatRange{Min: strength, Max: strength},
+			Defence:  core.StatRange{Min: defence, Max: defence},
+			Speed:    core.StatRange{Min: speed, Max: speed},
+		},
+	}
+}
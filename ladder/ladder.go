@@ -0,0 +1,154 @@
+package ladder
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// Ladder keeps the ratings of named fighter templates
+// and updates them after every duel
+type Ladder struct {
+	System System
+
+	ratings map[string]Rating
+}
+
+// New creates an empty ladder using the given rating system;
+// Elo with K = 32 is used when no system is given
+func New(system System) *Ladder {
+	if system == nil {
+		system = &Elo{K: 32}
+	}
+	return &Ladder{System: system, ratings: map[string]Rating{}}
+}
+
+// Rating returns the rating of the fighter; fighters
+// without duels have the initial rating
+func (l *Ladder) Rating(name string) Rating {
+	if r, ok := l.ratings[name]; ok {
+		return r
+	}
+	return NewRating(name)
+}
+
+// Record updates the ratings of both fighters of the duel;
+// a tie (all rounds exhausted) is scored as half a win. Duels between
+// fighters of the same template and paused duels don't change the ratings
+func (l *Ladder) Record(result *core.DuelResult) {
+	if result.First.Name == result.Second.Name || result.Paused {
+		return
+	}
+
+	a, b := l.Rating(result.First.Name), l.Rating(result.Second.Name)
+
+	score := 0.5
+	switch {
+	case result.IsTie():
+		a.Ties++
+		b.Ties++
+	case result.Winner == result.First:
+		score = 1
+		a.Wins++
+		b.Losses++
+	default:
+		score = 0
+		a.Losses++
+		b.Wins++
+	}
+	a.Duels++
+	b.Duels++
+
+	a, b = l.System.Update(a, b, score)
+	l.ratings[a.Name], l.ratings[b.Name] = a, b
+}
+
+// Duel summons both fighters, lets them fight for the given
+// number of rounds and records the result
+func (l *Ladder) Duel(first, second core.PlayerTemplate, rounds int) *core.DuelResult {
+	dm := &core.DuelMaster{
+		Rounds:    rounds,
+		PlayerOne: first.Summon(),
+		PlayerTwo: second.Summon(),
+	}
+
+	result := dm.StartDuel()
+	l.Record(result)
+	return result
+}
+
+// Standings returns the ratings of all fighters, highest rating first
+func (l *Ladder) Standings() []Rating {
+	standings := make([]Rating, 0, len(l.ratings))
+	for _, r := range l.ratings {
+		standings = append(standings, r)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Rating != standings[j].Rating {
+			return standings[i].Rating > standings[j].Rating
+		}
+		return standings[i].Name < standings[j].Name
+	})
+	return standings
+}
+
+// Opponent returns the candidate with the closest rating to the
+// fighter's; the fighter itself is never picked. The second value
+// is false if there are no candidates
+func (l *Ladder) Opponent(name string, candidates []string) (string, bool) {
+	rating := l.Rating(name).Rating
+
+	best, found := "", false
+	bestDistance := math.Inf(1)
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+		if distance := math.Abs(l.Rating(candidate).Rating - rating); distance < bestDistance {
+			best, bestDistance, found = candidate, distance, true
+		}
+	}
+	return best, found
+}
+
+// Pairings sorts the fighters by rating and pairs neighbours, so
+// that every fighter meets an opponent of similar rating; when there
+// is an odd number of fighters, the lowest rated one is left out
+func (l *Ladder) Pairings(names []string) [][2]string {
+	sorted := append([]string{}, names...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return l.Rating(sorted[i]).Rating > l.Rating(sorted[j]).Rating
+	})
+
+	pairs := [][2]string{}
+	for i := 0; i+1 < len(sorted); i += 2 {
+		pairs = append(pairs, [2]string{sorted[i], sorted[i+1]})
+	}
+	return pairs
+}
+
+// Save writes the ratings as JSON
+func (l *Ladder) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l.Standings())
+}
+
+// Load reads the ratings previously written by Save;
+// the loaded ratings replace the existing ones
+func (l *Ladder) Load(r io.Reader) error {
+	ratings := []Rating{}
+	if err := json.NewDecoder(r).Decode(&ratings); err != nil {
+		return err
+	}
+
+	l.ratings = map[string]Rating{}
+	for _, rating := range ratings {
+		l.ratings[rating.Name] = rating
+	}
+	return nil
+}
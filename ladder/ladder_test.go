@@ -0,0 +1,135 @@
+package ladder
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/internal/testutil"
+)
+
+// fighter creates a template that knocks out slower opponents with one hit
+func fighter(name string, speed float64) core.PlayerTemplate {
+	return testutil.Template(name, 100, 1000, 0, speed)
+}
+
+func TestLadder_Duel(t *testing.T) {
+	l := New(nil)
+	hero, villain := fighter("Hero", 2), fighter("Villain", 1)
+
+	for i := 0; i < 10; i++ {
+		l.Duel(villain, hero, 20)
+	}
+
+	h, v := l.Rating("Hero"), l.Rating("Villain")
+	if h.Wins != 10 || v.Losses != 10 || h.Duels != 10 || v.Duels != 10 {
+		t.Errorf("Duel() recorded %+v and %+v, want 10 wins for Hero", h, v)
+	}
+	if h.Rating <= v.Rating {
+		t.Errorf("Duel() should rate Hero above Villain; got %.2f and %.2f", h.Rating, v.Rating)
+	}
+
+	standings := l.Standings()
+	if len(standings) != 2 || standings[0].Name != "Hero" {
+		t.Errorf("Standings() = %+v, want Hero first", standings)
+	}
+}
+
+func TestLadder_Record(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    func(a, b *core.Player) *core.DuelResult
+		wantTies  int
+		wantScore float64
+	}{
+		{
+			name: "records ties as half a win",
+			result: func(a, b *core.Player) *core.DuelResult {
+				return &core.DuelResult{Rounds: 20, First: a, Second: b}
+			},
+			wantTies:  1,
+			wantScore: InitialRating,
+		},
+		{
+			name: "records knockouts of the second fighter",
+			result: func(a, b *core.Player) *core.DuelResult {
+				return &core.DuelResult{Rounds: 3, Knockout: true, First: a, Second: b, Winner: b, Loser: a}
+			},
+			wantScore: InitialRating - 16,
+		},
+		{
+			name: "ignores paused duels",
+			result: func(a, b *core.Player) *core.DuelResult {
+				return &core.DuelResult{Rounds: 5, Paused: true, First: a, Second: b}
+			},
+			wantScore: InitialRating,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(&Elo{K: 32})
+			a := core.NewPlayer("A", core.PlayerStats{}, core.PlayerSkills{})
+			b := core.NewPlayer("B", core.PlayerStats{}, core.PlayerSkills{})
+
+			l.Record(tt.result(a, b))
+			got := l.Rating("A")
+			if got.Ties != tt.wantTies || got.Rating != tt.wantScore {
+				t.Errorf("Record() = %+v, want %d ties and %.2f rating", got, tt.wantTies, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestLadder_Opponent(t *testing.T) {
+	l := New(nil)
+	l.ratings = map[string]Rating{
+		"Hero":   {Name: "Hero", Rating: 1600},
+		"Peanut": {Name: "Peanut", Rating: 1400},
+		"Goblin": {Name: "Goblin", Rating: 1650},
+	}
+
+	if got, ok := l.Opponent("Hero", []string{"Hero", "Peanut", "Goblin"}); !ok || got != "Goblin" {
+		t.Errorf("Opponent() = %s, %v, want Goblin", got, ok)
+	}
+	if _, ok := l.Opponent("Hero", []string{"Hero"}); ok {
+		t.Errorf("Opponent() should not find an opponent among no candidates")
+	}
+}
+
+func TestLadder_Pairings(t *testing.T) {
+	l := New(nil)
+	l.ratings = map[string]Rating{
+		"A": {Name: "A", Rating: 1000},
+		"B": {Name: "B", Rating: 2000},
+		"C": {Name: "C", Rating: 1100},
+		"D": {Name: "D", Rating: 1900},
+		"E": {Name: "E", Rating: 500},
+	}
+
+	want := [][2]string{{"B", "D"}, {"C", "A"}}
+	if got := l.Pairings([]string{"A", "B", "C", "D", "E"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pairings() = %v, want %v", got, want)
+	}
+}
+
+func TestLadder_SaveLoad(t *testing.T) {
+	l := New(&Glicko{C: 30})
+	for i := 0; i < 3; i++ {
+		l.Duel(fighter("Hero", 2), fighter("Villain", 1), 20)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := l.Save(buf); err != nil {
+		t.Fatalf("Save() returned an unexpected error: %v", err)
+	}
+
+	loaded := New(&Glicko{C: 30})
+	if err := loaded.Load(buf); err != nil {
+		t.Fatalf("Load() returned an unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Standings(), l.Standings()) {
+		t.Errorf("Load() = %+v, want %+v", loaded.Standings(), l.Standings())
+	}
+}
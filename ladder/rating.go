@@ -0,0 +1,84 @@
+package ladder
+
+import "math"
+
+// default values for new ratings
+const (
+	InitialRating    = 1500
+	InitialDeviation = 350
+)
+
+// Rating represents the strength of a fighter template on the ladder
+type Rating struct {
+	Name      string  `json:"name"`
+	Rating    float64 `json:"rating"`
+	Deviation float64 `json:"deviation"`
+
+	Duels  int `json:"duels"`
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Ties   int `json:"ties"`
+}
+
+// NewRating creates the rating of a fighter that never dueled
+func NewRating(name string) Rating {
+	return Rating{Name: name, Rating: InitialRating, Deviation: InitialDeviation}
+}
+
+// System represents a rating system; Update returns the new ratings
+// of 2 fighters after a duel, where score is the result of the first
+// fighter: 1 for a win, 0.5 for a tie and 0 for a loss
+type System interface {
+	Update(a, b Rating, score float64) (Rating, Rating)
+}
+
+// Elo is the classic Elo rating system; Deviation is left untouched
+type Elo struct {
+	// K is the maximum change of a rating after a duel
+	K float64
+}
+
+// Update updates the ratings using the Elo formula
+func (e *Elo) Update(a, b Rating, score float64) (Rating, Rating) {
+	expected := 1 / (1 + math.Pow(10, (b.Rating-a.Rating)/400))
+	change := e.K * (score - expected)
+
+	a.Rating += change
+	b.Rating -= change
+	return a, b
+}
+
+// Glicko is the Glicko rating system, in which every duel is a rating
+// period; the rating deviation shrinks as fighters duel and grows by
+// C before every duel, up to InitialDeviation
+type Glicko struct {
+	C float64
+}
+
+const glickoQ = math.Ln10 / 400
+
+func glickoG(deviation float64) float64 {
+	return 1 / math.Sqrt(1+3*glickoQ*glickoQ*deviation*deviation/(math.Pi*math.Pi))
+}
+
+func (g *Glicko) update(r, opponent Rating, score float64) Rating {
+	gj := glickoG(opponent.Deviation)
+	expected := 1 / (1 + math.Pow(10, -gj*(r.Rating-opponent.Rating)/400))
+	dSquared := 1 / (glickoQ * glickoQ * gj * gj * expected * (1 - expected))
+
+	denominator := 1/(r.Deviation*r.Deviation) + 1/dSquared
+	r.Rating += glickoQ / denominator * gj * (score - expected)
+	r.Deviation = math.Sqrt(1 / denominator)
+	return r
+}
+
+func (g *Glicko) inflate(r Rating) Rating {
+	r.Deviation = math.Min(math.Sqrt(r.Deviation*r.Deviation+g.C*g.C), InitialDeviation)
+	return r
+}
+
+// Update updates the ratings and deviations using the Glicko formulas
+func (g *Glicko) Update(a, b Rating, score float64) (Rating, Rating) {
+	a, b = g.inflate(a), g.inflate(b)
+	return g.update(a, b, score), g.update(b, a, 1-score)
+}
@@ -0,0 +1,103 @@
+package ladder
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+func TestElo_Update(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  Rating
+		score float64
+		wantA float64
+		wantB float64
+	}{
+		{
+			name:  "the winner takes half of K from an equally rated fighter",
+			a:     NewRating("Hero"),
+			b:     NewRating("Villain"),
+			score: 1,
+			wantA: 1516,
+			wantB: 1484,
+		},
+		{
+			name:  "a tie between equally rated fighters doesn't change the ratings",
+			a:     NewRating("Hero"),
+			b:     NewRating("Villain"),
+			score: 0.5,
+			wantA: 1500,
+			wantB: 1500,
+		},
+		{
+			name:  "a tie moves the ratings towards each other",
+			a:     Rating{Name: "Hero", Rating: 1900},
+			b:     Rating{Name: "Villain", Rating: 1500},
+			score: 0.5,
+			wantA: 1886.91,
+			wantB: 1513.09,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotA, gotB := (&Elo{K: 32}).Update(tt.a, tt.b, tt.score)
+			if !almostEqual(gotA.Rating, tt.wantA) || !almostEqual(gotB.Rating, tt.wantB) {
+				t.Errorf("Elo.Update() = %.2f, %.2f, want %.2f, %.2f", gotA.Rating, gotB.Rating, tt.wantA, tt.wantB)
+			}
+		})
+	}
+}
+
+func TestGlicko_Update(t *testing.T) {
+	tests := []struct {
+		name          string
+		a, b          Rating
+		score         float64
+		wantA         float64
+		wantDeviation float64
+	}{
+		{
+			name:          "a win against a settled fighter raises the rating and lowers the deviation",
+			a:             Rating{Name: "Hero", Rating: 1500, Deviation: 200},
+			b:             Rating{Name: "Villain", Rating: 1400, Deviation: 30},
+			score:         1,
+			wantA:         1563.43,
+			wantDeviation: 175.22,
+		},
+		{
+			name:          "an uncertain fighter tying an equal one keeps the rating",
+			a:             NewRating("Hero"),
+			b:             NewRating("Villain"),
+			score:         0.5,
+			wantA:         1500,
+			wantDeviation: 290.23,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotA, gotB := (&Glicko{}).Update(tt.a, tt.b, tt.score)
+			if !almostEqual(gotA.Rating, tt.wantA) || !almostEqual(gotA.Deviation, tt.wantDeviation) {
+				t.Errorf("Glicko.Update() = %.2f (%.2f), want %.2f (%.2f)", gotA.Rating, gotA.Deviation, tt.wantA, tt.wantDeviation)
+			}
+			if tt.score == 1 && gotB.Rating >= tt.b.Rating {
+				t.Errorf("Glicko.Update() should lower the loser's rating; got %.2f from %.2f", gotB.Rating, tt.b.Rating)
+			}
+		})
+	}
+}
+
+func TestGlicko_inflate(t *testing.T) {
+	g := &Glicko{C: 50}
+	if got := g.inflate(Rating{Deviation: 120}).Deviation; !almostEqual(got, 130) {
+		t.Errorf("inflate() = %.2f, want 130", got)
+	}
+	if got := g.inflate(Rating{Deviation: 349}).Deviation; got != InitialDeviation {
+		t.Errorf("inflate() = %.2f, want %d", got, InitialDeviation)
+	}
+}
@@ -1,46 +1,582 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
 	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/pfzero/battle-simulator/balance"
+	"github.com/pfzero/battle-simulator/campaign"
 	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/history"
+	"github.com/pfzero/battle-simulator/pvp"
+	"github.com/pfzero/battle-simulator/script"
+	"github.com/pfzero/battle-simulator/server"
+	"github.com/pfzero/battle-simulator/simulation"
+	"github.com/pfzero/battle-simulator/terminal"
 )
 
-func main() {
-	t := time.Now()
-	rand.Seed(t.UnixNano())
-
-	p1 := core.NewPlayer("Na`arun The Wicked", core.PlayerStats{
-		Health:   core.Range(70, 100),
-		Strength: core.Range(70, 80),
-		Defence:  core.Range(45, 55),
-		Speed:    core.Range(40, 50),
-		Luck:     core.Range(0.1, 0.3),
-	}, core.PlayerSkills{
+var hero = core.PlayerTemplate{
+	Name: "Na`arun The Wicked",
+	Stats: core.StatRanges{
+		Health:   core.StatRange{Min: 70, Max: 100},
+		Strength: core.StatRange{Min: 70, Max: 80},
+		Defence:  core.StatRange{Min: 45, Max: 55},
+		Speed:    core.StatRange{Min: 40, Max: 50},
+		Luck:     core.StatRange{Min: 0.1, Max: 0.3},
+		Evasion:  core.StatRange{Min: 0.1, Max: 0.3},
+	},
+	Skills: core.PlayerSkills{
 		OffensiveSkills: []core.Skill{&core.CriticalStrike{DoubleStrikeChance: 0.1, TripleStrikeChance: 0.01}},
 		DefensiveSkills: []core.Skill{&core.Resilience{Chance: 0.2, DamageReduction: 0.5}},
-	})
-
-	p2 := core.NewPlayer("Peanut", core.PlayerStats{
-		Health:   core.Range(60, 90),
-		Strength: core.Range(60, 90),
-		Defence:  core.Range(40, 60),
-		Speed:    core.Range(40, 60),
-		Luck:     core.Range(0.25, 0.4),
-	}, core.PlayerSkills{
+	},
+	// abilities are only used when the hero's actions are chosen by a strategy
+	Abilities: []*core.Ability{
+		{
+			Skill:    &core.CriticalStrike{DoubleStrikeChance: 1, TripleStrikeChance: 0.1},
+			Cooldown: 3,
+			Cost:     core.Cost{Resource: core.Rage, Amount: 50},
+		},
+		{
+			Skill:    &core.SunderArmor{Chance: 1, Amount: 5},
+			Cooldown: 2,
+			Cost:     core.Cost{Resource: core.Stamina, Amount: 30},
+		},
+	},
+	Pools: core.Pools{
+		core.Stamina: {Max: 100, Start: 100, Regen: 10},
+		core.Rage:    {Max: 100, Regen: -5, OnDamageTaken: 1},
+	},
+}
+
+var villain = core.PlayerTemplate{
+	Name: "Peanut",
+	Stats: core.StatRanges{
+		Health:   core.StatRange{Min: 60, Max: 90},
+		Strength: core.StatRange{Min: 60, Max: 90},
+		Defence:  core.StatRange{Min: 40, Max: 60},
+		Speed:    core.StatRange{Min: 40, Max: 60},
+		Luck:     core.StatRange{Min: 0.25, Max: 0.4},
+		Evasion:  core.StatRange{Min: 0.25, Max: 0.4},
+	},
+	Skills: core.PlayerSkills{
 		OffensiveSkills: []core.Skill{},
 		DefensiveSkills: []core.Skill{},
-	})
+	},
+}
+
+func main() {
+	walk := flag.Bool("campaign", false, "walk the hero through The Terminal Valley, facing villains one after another")
+	encounters := flag.Int("encounters", 0, "maximum number of villains met in a campaign (0 means until the hero dies, or at most 1000 villains)")
+	rest := flag.Float64("rest", 0.2, "percentage of max health the hero recovers between campaign encounters")
+	itemsConfig := flag.String("items", "items.json", "path of the items configuration")
+	heroEquipment := flag.String("hero-equipment", "", "comma separated names of the items worn by the hero")
+	skillsConfig := flag.String("skills", "skills.json", "path of the declarative skills configuration")
+	heroSkills := flag.String("hero-skills", "", "comma separated names of the declarative skills learned by the hero")
+	heroScripts := flag.String("hero-scripts", "", "comma separated paths of the offensive skill scripts learned by the hero")
+	formulaName := flag.String("formula", "subtractive", "damage formula: subtractive or mitigation")
+	mitigationK := flag.Float64("mitigation-k", 100, "K constant of the mitigation formula: defence / (defence + K)")
+	minDamage := flag.Float64("min-damage", 0, "percentage of the potential damage every hit does at least")
+	variance := flag.Float64("variance", 0, "percentage by which the damage of every hit randomly varies")
+	simulate := flag.Int("simulate", 0, "number of duels to simulate instead of commenting a single duel")
+	tune := flag.Float64("tune", 0, "win rate of the hero to tune the parameters for, e.g. 0.55")
+	tuneParams := flag.String("tune-params", "two.strength.max=60:120", "comma separated parameters to tune, as <one|two>.<knob>=<min>:<max>; one is the hero")
+	tuneMethod := flag.String("tune-method", "coordinate-descent", "tuning algorithm: coordinate-descent or evolution")
+	tuneDuels := flag.Int("tune-duels", 2000, "number of duels simulated for every tuning candidate")
+	sensitivity := flag.Float64("sensitivity", 0, "percentage every stat and skill parameter is perturbed by to rank their impact on the hero's win rate, e.g. 0.1")
+	sensitivityDuels := flag.Int("sensitivity-duels", 2000, "number of duels simulated for every perturbation")
+	heroStrategy := flag.String("hero-strategy", "", "AI choosing the hero's actions: aggressive, defensive, random or greedy")
+	villainStrategy := flag.String("villain-strategy", "", "AI choosing the villains' actions: aggressive, defensive, random or greedy")
+	serve := flag.String("serve", "", "address to serve duels, simulations and Prometheus metrics on, e.g. :8080")
+	grpcServe := flag.String("grpc", "", "address to serve the Battle gRPC service on, alone or alongside -serve, e.g. :9090")
+	pvpServe := flag.String("pvp", "", "address to serve duels between remote players on, e.g. :9000")
+	pvpBudget := flag.String("pvp-budget", "", "path of the point-buy budget the fighters joining the -pvp server must fit in, e.g. budget.json")
+	pvpJoin := flag.String("pvp-join", "", "address of the server to join with the hero, choosing his actions from the terminal")
+	historyPath := flag.String("history", "", "path of the database file saving the commented duels")
+	historyList := flag.Int("history-list", 0, "list the last duels of the history instead of fighting, with the hero vs villain record")
+	historyWinner := flag.String("history-winner", "", "only list the duels won by this fighter")
+	play := flag.Bool("play", false, "choose the hero's actions from the terminal; villains default to the greedy strategy")
+	flag.Parse()
+
+	formula, err := damageFormula(*formulaName, *mitigationK, *minDamage, *variance)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if hero.Strategy, err = strategy(*heroStrategy); err != nil {
+		log.Fatal(err)
+	}
+	if villain.Strategy, err = strategy(*villainStrategy); err != nil {
+		log.Fatal(err)
+	}
+
+	if *play {
+		hero.Strategy = terminal.NewHuman(os.Stdin, os.Stdout)
+		if villain.Strategy == nil {
+			villain.Strategy = core.Greedy{HealRatio: 0.2}
+		}
+	}
+
+	if *heroEquipment != "" {
+		equipment, err := loadEquipment(*itemsConfig, strings.Split(*heroEquipment, ","))
+		if err != nil {
+			log.Fatal(err)
+		}
+		hero.Equipment = equipment
+	}
+
+	if *heroSkills != "" {
+		skills, err := loadSkills(*skillsConfig, strings.Split(*heroSkills, ","))
+		if err != nil {
+			log.Fatal(err)
+		}
+		hero.Skills = learn(hero.Skills, skills)
+	}
+
+	if *heroScripts != "" {
+		for _, path := range strings.Split(*heroScripts, ",") {
+			skill, err := script.Open(strings.TrimSpace(path))
+			if err != nil {
+				log.Fatal(err)
+			}
+			hero.Skills = learn(hero.Skills, []core.Skill{skill})
+		}
+	}
+
+	for _, t := range []core.PlayerTemplate{hero, villain} {
+		if err := t.Validate(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var store *history.Store
+	if *historyPath != "" {
+		if store, err = history.Open(*historyPath); err != nil {
+			log.Fatal(err)
+		}
+		defer store.Close()
+	}
+
+	if *historyList > 0 {
+		if store == nil {
+			log.Fatal("-history-list needs the -history database")
+		}
+		records, err := store.Find(history.Filter{Winner: *historyWinner, Limit: *historyList})
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, r := range records {
+			log.Println(r.String())
+		}
+		matchup, err := store.Matchup(hero.Name, villain.Name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("%s vs %s: %s\n", hero.Name, villain.Name, matchup)
+		return
+	}
+
+	t := time.Now()
+	rand.Seed(t.UnixNano())
+
+	if *serve != "" || *grpcServe != "" {
+		srv := server.New(hero, villain, formula)
+		if store != nil {
+			srv.History = store
+		}
+		if *grpcServe != "" {
+			l, err := net.Listen("tcp", *grpcServe)
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("Serving the Battle gRPC service (%s vs %s) on %s", hero.Name, villain.Name, *grpcServe)
+			if *serve == "" {
+				log.Fatal(server.NewGRPC(srv).Serve(l))
+			}
+			go func() {
+				log.Fatal(server.NewGRPC(srv).Serve(l))
+			}()
+		}
+		log.Printf("Serving %s vs %s on %s", hero.Name, villain.Name, *serve)
+		log.Fatal(http.ListenAndServe(*serve, srv))
+	}
+
+	if *pvpServe != "" {
+		log.Printf("Serving duels between remote players on %s", *pvpServe)
+		srv := pvp.NewServer(formula)
+		srv.History = store
+		if *pvpBudget != "" {
+			if srv.Budget, err = loadBudget(*pvpBudget); err != nil {
+				log.Fatal(err)
+			}
+		}
+		log.Fatal(srv.ListenAndServe(*pvpServe))
+	}
+
+	if *pvpJoin != "" {
+		if err := joinDuel(*pvpJoin, os.Stdin, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *tune > 0 {
+		result, err := tuneBalance(*tune, *tuneParams, *tuneMethod, *tuneDuels, formula)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("%s vs %s:\n%s", hero.Name, villain.Name, result)
+		return
+	}
+
+	if *sensitivity > 0 {
+		s := &balance.Sensitivity{
+			PlayerOne:     hero,
+			PlayerTwo:     villain,
+			Perturbation:  *sensitivity,
+			Duels:         *sensitivityDuels,
+			Rounds:        20,
+			Seed:          t.UnixNano(),
+			DamageFormula: formula,
+		}
+		report, err := s.Run()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("%s vs %s:\n%s", hero.Name, villain.Name, report)
+		return
+	}
+
+	if *simulate > 0 {
+		s := &simulation.Simulation{
+			PlayerOne:     hero,
+			PlayerTwo:     villain,
+			Duels:         *simulate,
+			Rounds:        20,
+			DamageFormula: formula,
+		}
+		log.Printf("%s vs %s: %s\n", hero.Name, villain.Name, s.Run())
+		return
+	}
+
+	if *walk {
+		c := &campaign.Campaign{
+			Hero:        hero.Summon(),
+			Villains:    []core.PlayerTemplate{villain},
+			Encounters:  *encounters,
+			Rest:        *rest,
+			Rounds:      20,
+			RoundsDelay: time.Second,
+			AttackDelay: 500 * time.Millisecond,
+			Commentator: &core.LogsCommentator{},
+
+			DamageFormula: formula,
+		}
+
+		summary, err := c.Run()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("\n%s", summary)
+		return
+	}
 
 	dm := &core.DuelMaster{
 		Rounds:      20,
 		RoundsDelay: time.Second,
 		AttackDelay: 500 * time.Millisecond,
 
-		PlayerOne: p1,
-		PlayerTwo: p2,
+		PlayerOne: hero.Summon(),
+		PlayerTwo: villain.Summon(),
+
+		DamageFormula: formula,
+	}
+
+	if store == nil {
+		dm.StartDuel(&core.LogsCommentator{})
+		return
 	}
 
-	dm.StartDuel(&core.LogsCommentator{})
+	recorder := &history.Recorder{}
+	result := dm.StartDuel(core.Commentators{&core.LogsCommentator{}, recorder})
+	if err := store.Save(history.NewRecord(result, recorder)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadEquipment reads the items configuration and returns the named items
+func loadEquipment(path string, names []string) ([]*core.Item, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	items, err := core.LoadItems(f)
+	if err != nil {
+		return nil, err
+	}
+
+	equipment := []*core.Item{}
+	for _, name := range names {
+		item, ok := items[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("there is no item named %q in %s", name, path)
+		}
+		equipment = append(equipment, item)
+	}
+	return equipment, nil
+}
+
+// loadSkills reads the declarative skills configuration and returns the named skills
+func loadSkills(path string, names []string) ([]core.Skill, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	skills, err := core.LoadSkills(f)
+	if err != nil {
+		return nil, err
+	}
+
+	learned := []core.Skill{}
+	for _, name := range names {
+		skill, ok := skills[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("there is no skill named %q in %s", name, path)
+		}
+		learned = append(learned, skill)
+	}
+	return learned, nil
+}
+
+// loadBudget reads the point-buy budget
+func loadBudget(path string) (*core.Budget, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	budget := &core.Budget{}
+	if err := json.Unmarshal(data, budget); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return budget, nil
+}
+
+// learn adds the declarative skills to the offensive or
+// defensive skills, without changing the given skills
+func learn(skills core.PlayerSkills, learned []core.Skill) core.PlayerSkills {
+	offensive := append([]core.Skill{}, skills.OffensiveSkills...)
+	defensive := append([]core.Skill{}, skills.DefensiveSkills...)
+	for _, skill := range learned {
+		if d, ok := skill.(*core.Declarative); ok && d.Spec.Defensive {
+			defensive = append(defensive, skill)
+		} else {
+			offensive = append(offensive, skill)
+		}
+	}
+	return core.PlayerSkills{OffensiveSkills: offensive, DefensiveSkills: defensive}
+}
+
+// tuneBalance searches the parameters for which the hero wins the target percentage of duels
+func tuneBalance(target float64, params, method string, duels int, formula core.DamageFormula) (*balance.Result, error) {
+	tuner := &balance.Tuner{
+		PlayerOne:     hero,
+		PlayerTwo:     villain,
+		Target:        target,
+		Duels:         duels,
+		Rounds:        20,
+		Tolerance:     0.01,
+		DamageFormula: formula,
+	}
+
+	switch method {
+	case "coordinate-descent":
+		tuner.Method = balance.CoordinateDescent
+	case "evolution":
+		tuner.Method = balance.Evolution
+	default:
+		return nil, fmt.Errorf("unknown tuning method %q", method)
+	}
+
+	for _, spec := range strings.Split(params, ",") {
+		p, err := balance.ParseParameter(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		tuner.Parameters = append(tuner.Parameters, p)
+	}
+	return tuner.Run()
+}
+
+// damageFormula builds the damage formula configured from the command line
+func damageFormula(name string, k, minDamage, variance float64) (core.DamageFormula, error) {
+	var formula core.DamageFormula
+	switch name {
+	case "subtractive":
+		formula = core.Subtractive{}
+	case "mitigation":
+		formula = core.Mitigation{K: k}
+	default:
+		return nil, fmt.Errorf("unknown damage formula %q", name)
+	}
+
+	if minDamage > 0 {
+		formula = core.MinimumDamage{Formula: formula, Ratio: minDamage}
+	}
+	if variance > 0 {
+		formula = core.Variance{Formula: formula, Spread: variance}
+	}
+	return formula, nil
+}
+
+// strategy returns the built-in AI with the given name;
+// players without a strategy always attack
+func strategy(name string) (core.Strategy, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "aggressive":
+		return core.Aggressive{}, nil
+	case "defensive":
+		return core.Defensive{HealBelow: 0.4}, nil
+	case "random":
+		return core.Random{}, nil
+	case "greedy":
+		return core.Greedy{HealRatio: 0.2}, nil
+	}
+	return nil, fmt.Errorf("unknown strategy %q", name)
+}
+
+// fighterSpec describes the template for the servers; the skills are
+// configured under the names of their registered types
+func fighterSpec(t core.PlayerTemplate) (server.FighterSpec, error) {
+	spec := server.FighterSpec{
+		Name: t.Name,
+		Stats: server.StatsSpec{
+			Health:         server.RangeSpec(t.Stats.Health),
+			Strength:       server.RangeSpec(t.Stats.Strength),
+			Defence:        server.RangeSpec(t.Stats.Defence),
+			Speed:          server.RangeSpec(t.Stats.Speed),
+			Luck:           server.RangeSpec(t.Stats.Luck),
+			Accuracy:       server.RangeSpec(t.Stats.Accuracy),
+			Evasion:        server.RangeSpec(t.Stats.Evasion),
+			CritChance:     server.RangeSpec(t.Stats.CritChance),
+			CritMultiplier: server.RangeSpec(t.Stats.CritMultiplier),
+		},
+		Resistances: t.Resistances,
+	}
+
+	configs := func(skills []core.Skill) ([]core.SkillConfig, error) {
+		configs := []core.SkillConfig{}
+		for _, skill := range skills {
+			config, err := core.NewSkillConfig(skill)
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, config)
+		}
+		return configs, nil
+	}
+
+	var err error
+	if spec.Offensive, err = configs(t.Skills.OffensiveSkills); err != nil {
+		return spec, err
+	}
+	spec.Defensive, err = configs(t.Skills.DefensiveSkills)
+	return spec, err
+}
+
+// joinDuel joins the server of duels between remote players
+// with the hero, choosing his actions from the terminal
+func joinDuel(addr string, in io.Reader, out io.Writer) error {
+	spec, err := fighterSpec(hero)
+	if err != nil {
+		return err
+	}
+	c, err := pvp.Dial(addr, spec)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	input := bufio.NewScanner(in)
+	for {
+		m, err := c.Receive()
+		if err == io.EOF {
+			return errors.New("the server closed the connection")
+		}
+		if err != nil {
+			return err
+		}
+
+		switch m.Type {
+		case pvp.WaitingMessage:
+			fmt.Fprintln(out, "Waiting for an opponent...")
+		case pvp.MatchedMessage:
+			fmt.Fprintf(out, "%s vs %s\n", m.Fighters[0].Name, m.Fighters[1].Name)
+		case pvp.EventMessage:
+			presentEvent(out, *m.Event)
+		case pvp.TurnMessage:
+			fmt.Fprintf(out, "Round %d of %d: %.2f health, your opponent has %.2f; you have %.0f seconds to attack, defend, heal or flee\n> ",
+				m.Turn.Round, m.Turn.Rounds, m.Turn.Health, m.Turn.OpponentHealth, m.Turn.Timeout)
+			kind := core.FleeAction
+			for input.Scan() {
+				if kind, err = pvp.ParseAction(strings.TrimSpace(input.Text())); err == nil {
+					break
+				}
+				fmt.Fprintf(out, "%v\n> ", err)
+				kind = core.FleeAction
+			}
+			if err := c.Act(kind); err != nil {
+				return err
+			}
+		case pvp.TimeoutMessage:
+			fmt.Fprintln(out, "You took too long and attacked")
+		case pvp.ErrorMessage:
+			fmt.Fprintln(out, m.Error)
+		case pvp.ResultMessage:
+			switch r := m.Result; {
+			case m.Forfeit != "":
+				fmt.Fprintf(out, "%s disconnected and forfeited the duel\n", m.Forfeit)
+			case r.IsTie():
+				fmt.Fprintf(out, "The duel ended with a tie after %d rounds\n", r.Rounds)
+			default:
+				fmt.Fprintf(out, "%s won the duel on round %d\n", r.Winner, r.Rounds)
+			}
+			return nil
+		}
+	}
+}
+
+// presentEvent writes the event of a remote duel
+func presentEvent(out io.Writer, e history.Event) {
+	switch e.Type {
+	case history.RoundEvent:
+		fmt.Fprintf(out, "Round %d\n", e.Round)
+	case history.AttackEvent:
+		damage := 0.0
+		for _, hit := range e.Hits {
+			damage += hit.Damage
+		}
+		fmt.Fprintf(out, "%s hits %s for %.2f damage, leaving %.2f health\n", e.Player, e.Opponent, damage, *e.Health)
+	case history.ActionEvent:
+		fmt.Fprintf(out, "%s chose to %s\n", e.Player, e.Action)
+	case history.KnockoutEvent:
+		fmt.Fprintf(out, "%s knocked %s out\n", e.Player, e.Opponent)
+	case history.FleeEvent:
+		fmt.Fprintf(out, "%s fled from %s\n", e.Player, e.Opponent)
+	}
 }
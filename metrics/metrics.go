@@ -0,0 +1,261 @@
+// Package metrics counts the events of duels and exposes them
+// in the Prometheus text format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// counter is a counter with an optional label
+type counter struct {
+	name  string
+	help  string
+	label string
+	// values holds the count for every label value; the count
+	// of counters without a label is kept under ""
+	values map[string]float64
+}
+
+func newCounter(name, help, label string) *counter {
+	return &counter{name: name, help: help, label: label, values: map[string]float64{}}
+}
+
+func (c *counter) add(value string, v float64) {
+	c.values[value] += v
+}
+
+func (c *counter) write(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	if c.label == "" {
+		fmt.Fprintf(w, "%s %s\n", c.name, formatFloat(c.values[""]))
+		return
+	}
+
+	values := make([]string, 0, len(c.values))
+	for value := range c.values {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %s\n", c.name, c.label, escape(value), formatFloat(c.values[value]))
+	}
+}
+
+// histogram counts observations within cumulative buckets
+type histogram struct {
+	name    string
+	help    string
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(name, help string, buckets ...float64) *histogram {
+	return &histogram{name: name, help: help, buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func (h *histogram) write(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for i, bound := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.name, formatFloat(bound), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", h.name, formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", h.name, h.count)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+// Metrics holds the counters and histograms of all the duels
+// instrumented by its collectors; it is safe for concurrent use
+type Metrics struct {
+	mu sync.Mutex
+
+	duels      *counter
+	rounds     *counter
+	hits       *counter
+	evasions   *counter
+	triggers   *counter
+	damage     *histogram
+	duelRounds *histogram
+	durations  *histogram
+}
+
+// New creates empty metrics
+func New() *Metrics {
+	return &Metrics{
+		duels:      newCounter("battle_duels_total", "Number of duels fought, by outcome.", "outcome"),
+		rounds:     newCounter("battle_rounds_total", "Number of rounds fought.", ""),
+		hits:       newCounter("battle_hits_total", "Number of hits defended.", ""),
+		evasions:   newCounter("battle_evasions_total", "Number of hits evaded.", ""),
+		triggers:   newCounter("battle_skill_triggers_total", "Number of times the skills were triggered, by skill.", "skill"),
+		damage:     newHistogram("battle_hit_damage", "Damage done by the hits that weren't evaded.", 1, 5, 10, 25, 50, 100, 250, 500),
+		duelRounds: newHistogram("battle_duel_rounds", "Number of rounds of the duels.", 1, 2, 3, 5, 10, 20, 50),
+		durations:  newHistogram("battle_duel_duration_seconds", "Duration of the duels.", 0.0001, 0.001, 0.01, 0.1, 1, 10, 60),
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, c := range []*counter{m.duels, m.rounds, m.hits, m.evasions, m.triggers} {
+		c.write(bw)
+	}
+	for _, h := range []*histogram{m.damage, m.duelRounds, m.durations} {
+		h.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// ServeHTTP serves the metrics to Prometheus
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// Collector returns a commentator recording the events of the duels
+// into the metrics; every collector follows one duel at a time
+func (m *Metrics) Collector() *Collector {
+	return &Collector{metrics: m}
+}
+
+// Collector is the commentator instrumenting a duel
+type Collector struct {
+	metrics *Metrics
+	started time.Time
+}
+
+// skillName strips the details of the battle description of a skill,
+// e.g. CriticalStrike(2x) becomes CriticalStrike
+func skillName(description string) string {
+	if i := strings.Index(description, "("); i >= 0 {
+		description = description[:i]
+	}
+	return strings.TrimSpace(description)
+}
+
+// Start starts measuring the duration of a new duel
+func (c *Collector) Start() {
+	c.started = time.Now()
+}
+
+// PresentPlayers does nothing; it's part of the commentator interface
+func (c *Collector) PresentPlayers(first, second *core.Player) {}
+
+// PresentRound counts the round
+func (c *Collector) PresentRound(round int) {
+	c.metrics.mu.Lock()
+	defer c.metrics.mu.Unlock()
+	c.metrics.rounds.add("", 1)
+}
+
+// PresentResources does nothing; it's part of the commentator interface
+func (c *Collector) PresentResources(first, second *core.Player) {}
+
+// PresentAttack counts the hits, the evasions and the skills used
+// and records the damage of every hit
+func (c *Collector) PresentAttack(attack *core.Attack, attacker, defender *core.Player) {
+	m := c.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	skills := append(append([]string{}, attack.UsedOffensiveSkills...), attack.UsedDefensiveSkills...)
+	for _, hit := range attack.Hits {
+		skills = append(append(skills, hit.UsedOffensiveSkills...), hit.UsedDefensiveSkills...)
+
+		// the hits left once the defender died aren't defended
+		if hit.Damage == nil {
+			continue
+		}
+		m.hits.add("", 1)
+		if hit.TotalPotentialDamage() == 0 {
+			m.evasions.add("", 1)
+			continue
+		}
+		m.damage.observe(hit.Damage.Total())
+	}
+	for _, skill := range skills {
+		m.triggers.add(skillName(skill), 1)
+	}
+}
+
+// PresentAction does nothing; it's part of the commentator interface
+func (c *Collector) PresentAction(action core.Action, player *core.Player) {}
+
+func (c *Collector) end(outcome string, round int) {
+	m := c.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.duels.add(outcome, 1)
+	m.duelRounds.observe(float64(round))
+	m.durations.observe(time.Since(c.started).Seconds())
+}
+
+// EndDuelKnockout counts a duel that ended with a knockout
+func (c *Collector) EndDuelKnockout(round int, winner, loser *core.Player) {
+	c.end("knockout", round)
+}
+
+// EndDuelFlee counts a duel that ended with a player fleeing
+func (c *Collector) EndDuelFlee(round int, fled, winner *core.Player) {
+	c.end("flee", round)
+}
+
+// EndDuelTie counts a duel that ended with a tie
+func (c *Collector) EndDuelTie(round int, player1, player2 *core.Player) {
+	c.end("tie", round)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+func TestCollector(t *testing.T) {
+	m := New()
+	c := m.Collector()
+
+	// the hero needs 3 rounds and 3 hits to knock the villain out;
+	// every hit of the villain is evaded
+	hero := core.NewPlayer("Hero", core.PlayerStats{Health: 100, Strength: 40, Speed: 2, Evasion: 1}, core.PlayerSkills{})
+	villain := core.NewPlayer("Villain", core.PlayerStats{Health: 90, Strength: 40, Defence: 10, Speed: 1}, core.PlayerSkills{
+		DefensiveSkills: []core.Skill{&core.Resilience{Chance: 0}},
+	})
+	dm := &core.DuelMaster{Rounds: 20, PlayerOne: hero, PlayerTwo: villain}
+	dm.StartDuel(c)
+
+	var sb strings.Builder
+	if _, err := m.WriteTo(&sb); err != nil {
+		t.Fatalf("WriteTo() returned an unexpected error: %v", err)
+	}
+	out := sb.String()
+
+	for _, line := range []string{
+		"# TYPE battle_duels_total counter",
+		`battle_duels_total{outcome="knockout"} 1`,
+		"battle_rounds_total 3",
+		"battle_hits_total 5",
+		"battle_evasions_total 2",
+		`battle_skill_triggers_total{skill="Evaded"} 2`,
+		"# TYPE battle_hit_damage histogram",
+		`battle_hit_damage_bucket{le="25"} 0`,
+		`battle_hit_damage_bucket{le="50"} 3`,
+		`battle_hit_damage_bucket{le="+Inf"} 3`,
+		"battle_hit_damage_sum 90",
+		"battle_hit_damage_count 3",
+		`battle_duel_rounds_bucket{le="2"} 0`,
+		`battle_duel_rounds_bucket{le="3"} 1`,
+		"battle_duel_duration_seconds_count 1",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("WriteTo() is missing %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestSkillName(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{description: "CriticalStrike(2x)", want: "CriticalStrike"},
+		{description: "Resilience(blocked 50.00% damage)", want: "Resilience"},
+		{description: "Got Lucky (you missed)", want: "Got Lucky"},
+		{description: "Berserk", want: "Berserk"},
+	}
+
+	for _, tt := range tests {
+		if got := skillName(tt.description); got != tt.want {
+			t.Errorf("skillName(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := escape("a \"b\" \\c\nd"); got != `a \"b\" \\c\nd` {
+		t.Errorf("escape() = %s", got)
+	}
+}
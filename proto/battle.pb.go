@@ -0,0 +1,2022 @@
+// The Battle service runs duels and simulations and streams their
+// events as they happen. The server package serves it with gRPC (see
+// server.NewGRPC; battle.pb.go and battle_grpc.pb.go are generated
+// from this file with protoc-gen-go and protoc-gen-go-grpc) and, for
+// the clients without gRPC, over HTTP (POST /duels/stream and POST
+// /simulations/stream): the request is the canonical JSON form
+// (protojson) of the request message and every line of the response is
+// the protojson form of a message of the stream. The HTTP requests may
+// also use the server's shorthands, e.g. fixed stats written as numbers
+// and skill params as JSON objects.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: battle.proto
+
+package battlepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Event_Type int32
+
+const (
+	Event_TYPE_UNSPECIFIED Event_Type = 0
+	Event_ROUND            Event_Type = 1
+	Event_ATTACK           Event_Type = 2
+	Event_ACTION           Event_Type = 3
+	Event_KNOCKOUT         Event_Type = 4
+	Event_FLEE             Event_Type = 5
+	Event_TIE              Event_Type = 6
+)
+
+// Enum value maps for Event_Type.
+var (
+	Event_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "ROUND",
+		2: "ATTACK",
+		3: "ACTION",
+		4: "KNOCKOUT",
+		5: "FLEE",
+		6: "TIE",
+	}
+	Event_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"ROUND":            1,
+		"ATTACK":           2,
+		"ACTION":           3,
+		"KNOCKOUT":         4,
+		"FLEE":             5,
+		"TIE":              6,
+	}
+)
+
+func (x Event_Type) Enum() *Event_Type {
+	p := new(Event_Type)
+	*p = x
+	return p
+}
+
+func (x Event_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Event_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_battle_proto_enumTypes[0].Descriptor()
+}
+
+func (Event_Type) Type() protoreflect.EnumType {
+	return &file_battle_proto_enumTypes[0]
+}
+
+func (x Event_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Event_Type.Descriptor instead.
+func (Event_Type) EnumDescriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{10, 0}
+}
+
+// StatRange is the interval a stat is rolled within; min == max for fixed stats
+type StatRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min float64 `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max float64 `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *StatRange) Reset() {
+	*x = StatRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatRange) ProtoMessage() {}
+
+func (x *StatRange) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatRange.ProtoReflect.Descriptor instead.
+func (*StatRange) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StatRange) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *StatRange) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type StatsSpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Health         *StatRange `protobuf:"bytes,1,opt,name=health,proto3" json:"health,omitempty"`
+	Strength       *StatRange `protobuf:"bytes,2,opt,name=strength,proto3" json:"strength,omitempty"`
+	Defence        *StatRange `protobuf:"bytes,3,opt,name=defence,proto3" json:"defence,omitempty"`
+	Speed          *StatRange `protobuf:"bytes,4,opt,name=speed,proto3" json:"speed,omitempty"`
+	Luck           *StatRange `protobuf:"bytes,5,opt,name=luck,proto3" json:"luck,omitempty"`
+	Accuracy       *StatRange `protobuf:"bytes,6,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	Evasion        *StatRange `protobuf:"bytes,7,opt,name=evasion,proto3" json:"evasion,omitempty"`
+	CritChance     *StatRange `protobuf:"bytes,8,opt,name=crit_chance,json=critChance,proto3" json:"crit_chance,omitempty"`
+	CritMultiplier *StatRange `protobuf:"bytes,9,opt,name=crit_multiplier,json=critMultiplier,proto3" json:"crit_multiplier,omitempty"`
+}
+
+func (x *StatsSpec) Reset() {
+	*x = StatsSpec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsSpec) ProtoMessage() {}
+
+func (x *StatsSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsSpec.ProtoReflect.Descriptor instead.
+func (*StatsSpec) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StatsSpec) GetHealth() *StatRange {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *StatsSpec) GetStrength() *StatRange {
+	if x != nil {
+		return x.Strength
+	}
+	return nil
+}
+
+func (x *StatsSpec) GetDefence() *StatRange {
+	if x != nil {
+		return x.Defence
+	}
+	return nil
+}
+
+func (x *StatsSpec) GetSpeed() *StatRange {
+	if x != nil {
+		return x.Speed
+	}
+	return nil
+}
+
+func (x *StatsSpec) GetLuck() *StatRange {
+	if x != nil {
+		return x.Luck
+	}
+	return nil
+}
+
+func (x *StatsSpec) GetAccuracy() *StatRange {
+	if x != nil {
+		return x.Accuracy
+	}
+	return nil
+}
+
+func (x *StatsSpec) GetEvasion() *StatRange {
+	if x != nil {
+		return x.Evasion
+	}
+	return nil
+}
+
+func (x *StatsSpec) GetCritChance() *StatRange {
+	if x != nil {
+		return x.CritChance
+	}
+	return nil
+}
+
+func (x *StatsSpec) GetCritMultiplier() *StatRange {
+	if x != nil {
+		return x.CritMultiplier
+	}
+	return nil
+}
+
+// SkillConfig is a skill like in the configuration files;
+// params holds the JSON parameters of the skill type
+type SkillConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type   string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Params string `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+}
+
+func (x *SkillConfig) Reset() {
+	*x = SkillConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SkillConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkillConfig) ProtoMessage() {}
+
+func (x *SkillConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkillConfig.ProtoReflect.Descriptor instead.
+func (*SkillConfig) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SkillConfig) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SkillConfig) GetParams() string {
+	if x != nil {
+		return x.Params
+	}
+	return ""
+}
+
+type FighterSpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Stats     *StatsSpec     `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	Offensive []*SkillConfig `protobuf:"bytes,3,rep,name=offensive,proto3" json:"offensive,omitempty"`
+	Defensive []*SkillConfig `protobuf:"bytes,4,rep,name=defensive,proto3" json:"defensive,omitempty"`
+	// resistances holds the percentage of damage blocked by damage type
+	Resistances map[string]float64 `protobuf:"bytes,5,rep,name=resistances,proto3" json:"resistances,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *FighterSpec) Reset() {
+	*x = FighterSpec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FighterSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FighterSpec) ProtoMessage() {}
+
+func (x *FighterSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FighterSpec.ProtoReflect.Descriptor instead.
+func (*FighterSpec) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FighterSpec) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FighterSpec) GetStats() *StatsSpec {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *FighterSpec) GetOffensive() []*SkillConfig {
+	if x != nil {
+		return x.Offensive
+	}
+	return nil
+}
+
+func (x *FighterSpec) GetDefensive() []*SkillConfig {
+	if x != nil {
+		return x.Defensive
+	}
+	return nil
+}
+
+func (x *FighterSpec) GetResistances() map[string]float64 {
+	if x != nil {
+		return x.Resistances
+	}
+	return nil
+}
+
+// DuelRequest describes a duel; the server's fighters fight when no
+// players are given and a random seed is used when the seed isn't set
+type DuelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Players []*FighterSpec `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Rounds  int32          `protobuf:"varint,2,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	Seed    int64          `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"`
+}
+
+func (x *DuelRequest) Reset() {
+	*x = DuelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DuelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuelRequest) ProtoMessage() {}
+
+func (x *DuelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuelRequest.ProtoReflect.Descriptor instead.
+func (*DuelRequest) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DuelRequest) GetPlayers() []*FighterSpec {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *DuelRequest) GetRounds() int32 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+func (x *DuelRequest) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+type SimulationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Players []*FighterSpec `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Rounds  int32          `protobuf:"varint,2,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	Seed    int64          `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"`
+	Duels   int32          `protobuf:"varint,4,opt,name=duels,proto3" json:"duels,omitempty"`
+}
+
+func (x *SimulationRequest) Reset() {
+	*x = SimulationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimulationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulationRequest) ProtoMessage() {}
+
+func (x *SimulationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulationRequest.ProtoReflect.Descriptor instead.
+func (*SimulationRequest) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SimulationRequest) GetPlayers() []*FighterSpec {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *SimulationRequest) GetRounds() int32 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+func (x *SimulationRequest) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *SimulationRequest) GetDuels() int32 {
+	if x != nil {
+		return x.Duels
+	}
+	return 0
+}
+
+type PlayerStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Health         float64 `protobuf:"fixed64,1,opt,name=health,proto3" json:"health,omitempty"`
+	Strength       float64 `protobuf:"fixed64,2,opt,name=strength,proto3" json:"strength,omitempty"`
+	Defence        float64 `protobuf:"fixed64,3,opt,name=defence,proto3" json:"defence,omitempty"`
+	Speed          float64 `protobuf:"fixed64,4,opt,name=speed,proto3" json:"speed,omitempty"`
+	Luck           float64 `protobuf:"fixed64,5,opt,name=luck,proto3" json:"luck,omitempty"`
+	Accuracy       float64 `protobuf:"fixed64,6,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	Evasion        float64 `protobuf:"fixed64,7,opt,name=evasion,proto3" json:"evasion,omitempty"`
+	CritChance     float64 `protobuf:"fixed64,8,opt,name=crit_chance,json=critChance,proto3" json:"crit_chance,omitempty"`
+	CritMultiplier float64 `protobuf:"fixed64,9,opt,name=crit_multiplier,json=critMultiplier,proto3" json:"crit_multiplier,omitempty"`
+}
+
+func (x *PlayerStats) Reset() {
+	*x = PlayerStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerStats) ProtoMessage() {}
+
+func (x *PlayerStats) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerStats.ProtoReflect.Descriptor instead.
+func (*PlayerStats) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PlayerStats) GetHealth() float64 {
+	if x != nil {
+		return x.Health
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetStrength() float64 {
+	if x != nil {
+		return x.Strength
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetDefence() float64 {
+	if x != nil {
+		return x.Defence
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetSpeed() float64 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetLuck() float64 {
+	if x != nil {
+		return x.Luck
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetEvasion() float64 {
+	if x != nil {
+		return x.Evasion
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetCritChance() float64 {
+	if x != nil {
+		return x.CritChance
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetCritMultiplier() float64 {
+	if x != nil {
+		return x.CritMultiplier
+	}
+	return 0
+}
+
+// Fighter is a snapshot of a fighter taken when the duel started
+type Fighter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Level     int32        `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Stats     *PlayerStats `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`
+	Offensive []string     `protobuf:"bytes,4,rep,name=offensive,proto3" json:"offensive,omitempty"`
+	Defensive []string     `protobuf:"bytes,5,rep,name=defensive,proto3" json:"defensive,omitempty"`
+	Equipment []string     `protobuf:"bytes,6,rep,name=equipment,proto3" json:"equipment,omitempty"`
+}
+
+func (x *Fighter) Reset() {
+	*x = Fighter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Fighter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fighter) ProtoMessage() {}
+
+func (x *Fighter) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fighter.ProtoReflect.Descriptor instead.
+func (*Fighter) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Fighter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Fighter) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *Fighter) GetStats() *PlayerStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *Fighter) GetOffensive() []string {
+	if x != nil {
+		return x.Offensive
+	}
+	return nil
+}
+
+func (x *Fighter) GetDefensive() []string {
+	if x != nil {
+		return x.Defensive
+	}
+	return nil
+}
+
+func (x *Fighter) GetEquipment() []string {
+	if x != nil {
+		return x.Equipment
+	}
+	return nil
+}
+
+type Fighters struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fighters []*Fighter `protobuf:"bytes,1,rep,name=fighters,proto3" json:"fighters,omitempty"`
+}
+
+func (x *Fighters) Reset() {
+	*x = Fighters{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Fighters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fighters) ProtoMessage() {}
+
+func (x *Fighters) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fighters.ProtoReflect.Descriptor instead.
+func (*Fighters) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Fighters) GetFighters() []*Fighter {
+	if x != nil {
+		return x.Fighters
+	}
+	return nil
+}
+
+type Hit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Damage float64  `protobuf:"fixed64,1,opt,name=damage,proto3" json:"damage,omitempty"`
+	Evaded bool     `protobuf:"varint,2,opt,name=evaded,proto3" json:"evaded,omitempty"`
+	Skills []string `protobuf:"bytes,3,rep,name=skills,proto3" json:"skills,omitempty"`
+}
+
+func (x *Hit) Reset() {
+	*x = Hit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hit) ProtoMessage() {}
+
+func (x *Hit) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hit.ProtoReflect.Descriptor instead.
+func (*Hit) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Hit) GetDamage() float64 {
+	if x != nil {
+		return x.Damage
+	}
+	return 0
+}
+
+func (x *Hit) GetEvaded() bool {
+	if x != nil {
+		return x.Evaded
+	}
+	return false
+}
+
+func (x *Hit) GetSkills() []string {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+// Event is a step of a duel; player is the one acting (the attacker,
+// the winner of a knockout or the one fleeing) and opponent the other one
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Round    int32      `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Type     Event_Type `protobuf:"varint,2,opt,name=type,proto3,enum=battle.v1.Event_Type" json:"type,omitempty"`
+	Player   string     `protobuf:"bytes,3,opt,name=player,proto3" json:"player,omitempty"`
+	Opponent string     `protobuf:"bytes,4,opt,name=opponent,proto3" json:"opponent,omitempty"`
+	// action is the action taken, for action events
+	Action string `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	// hits and skills describe an attack; health is the
+	// opponent's health once the attack was defended
+	Hits   []*Hit   `protobuf:"bytes,6,rep,name=hits,proto3" json:"hits,omitempty"`
+	Skills []string `protobuf:"bytes,7,rep,name=skills,proto3" json:"skills,omitempty"`
+	Health *float64 `protobuf:"fixed64,8,opt,name=health,proto3,oneof" json:"health,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Event) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *Event) GetType() Event_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Event_TYPE_UNSPECIFIED
+}
+
+func (x *Event) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
+func (x *Event) GetOpponent() string {
+	if x != nil {
+		return x.Opponent
+	}
+	return ""
+}
+
+func (x *Event) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Event) GetHits() []*Hit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *Event) GetSkills() []string {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+func (x *Event) GetHealth() float64 {
+	if x != nil && x.Health != nil {
+		return *x.Health
+	}
+	return 0
+}
+
+// DuelResult is the outcome of a duel, saved in the history under id
+type DuelResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Time     string `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Rounds   int32  `protobuf:"varint,3,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	Knockout bool   `protobuf:"varint,4,opt,name=knockout,proto3" json:"knockout,omitempty"`
+	Fled     bool   `protobuf:"varint,5,opt,name=fled,proto3" json:"fled,omitempty"`
+	// winner and loser are empty when the duel ended with a tie
+	Winner   string     `protobuf:"bytes,6,opt,name=winner,proto3" json:"winner,omitempty"`
+	Loser    string     `protobuf:"bytes,7,opt,name=loser,proto3" json:"loser,omitempty"`
+	Seed     int64      `protobuf:"varint,8,opt,name=seed,proto3" json:"seed,omitempty"`
+	Fighters []*Fighter `protobuf:"bytes,9,rep,name=fighters,proto3" json:"fighters,omitempty"`
+}
+
+func (x *DuelResult) Reset() {
+	*x = DuelResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DuelResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuelResult) ProtoMessage() {}
+
+func (x *DuelResult) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuelResult.ProtoReflect.Descriptor instead.
+func (*DuelResult) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DuelResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DuelResult) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *DuelResult) GetRounds() int32 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+func (x *DuelResult) GetKnockout() bool {
+	if x != nil {
+		return x.Knockout
+	}
+	return false
+}
+
+func (x *DuelResult) GetFled() bool {
+	if x != nil {
+		return x.Fled
+	}
+	return false
+}
+
+func (x *DuelResult) GetWinner() string {
+	if x != nil {
+		return x.Winner
+	}
+	return ""
+}
+
+func (x *DuelResult) GetLoser() string {
+	if x != nil {
+		return x.Loser
+	}
+	return ""
+}
+
+func (x *DuelResult) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *DuelResult) GetFighters() []*Fighter {
+	if x != nil {
+		return x.Fighters
+	}
+	return nil
+}
+
+type DuelMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Message:
+	//	*DuelMessage_Fighters
+	//	*DuelMessage_Event
+	//	*DuelMessage_Result
+	//	*DuelMessage_Error
+	Message isDuelMessage_Message `protobuf_oneof:"message"`
+}
+
+func (x *DuelMessage) Reset() {
+	*x = DuelMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DuelMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuelMessage) ProtoMessage() {}
+
+func (x *DuelMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuelMessage.ProtoReflect.Descriptor instead.
+func (*DuelMessage) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{12}
+}
+
+func (m *DuelMessage) GetMessage() isDuelMessage_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (x *DuelMessage) GetFighters() *Fighters {
+	if x, ok := x.GetMessage().(*DuelMessage_Fighters); ok {
+		return x.Fighters
+	}
+	return nil
+}
+
+func (x *DuelMessage) GetEvent() *Event {
+	if x, ok := x.GetMessage().(*DuelMessage_Event); ok {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *DuelMessage) GetResult() *DuelResult {
+	if x, ok := x.GetMessage().(*DuelMessage_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *DuelMessage) GetError() string {
+	if x, ok := x.GetMessage().(*DuelMessage_Error); ok {
+		return x.Error
+	}
+	return ""
+}
+
+type isDuelMessage_Message interface {
+	isDuelMessage_Message()
+}
+
+type DuelMessage_Fighters struct {
+	Fighters *Fighters `protobuf:"bytes,1,opt,name=fighters,proto3,oneof"`
+}
+
+type DuelMessage_Event struct {
+	Event *Event `protobuf:"bytes,2,opt,name=event,proto3,oneof"`
+}
+
+type DuelMessage_Result struct {
+	Result *DuelResult `protobuf:"bytes,3,opt,name=result,proto3,oneof"`
+}
+
+type DuelMessage_Error struct {
+	Error string `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+func (*DuelMessage_Fighters) isDuelMessage_Message() {}
+
+func (*DuelMessage_Event) isDuelMessage_Message() {}
+
+func (*DuelMessage_Result) isDuelMessage_Message() {}
+
+func (*DuelMessage_Error) isDuelMessage_Message() {}
+
+type SimulationStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Duels         int32 `protobuf:"varint,1,opt,name=duels,proto3" json:"duels,omitempty"`
+	PlayerOneWins int32 `protobuf:"varint,2,opt,name=player_one_wins,json=playerOneWins,proto3" json:"player_one_wins,omitempty"`
+	PlayerTwoWins int32 `protobuf:"varint,3,opt,name=player_two_wins,json=playerTwoWins,proto3" json:"player_two_wins,omitempty"`
+	Ties          int32 `protobuf:"varint,4,opt,name=ties,proto3" json:"ties,omitempty"`
+	TotalRounds   int32 `protobuf:"varint,5,opt,name=total_rounds,json=totalRounds,proto3" json:"total_rounds,omitempty"`
+}
+
+func (x *SimulationStats) Reset() {
+	*x = SimulationStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimulationStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulationStats) ProtoMessage() {}
+
+func (x *SimulationStats) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulationStats.ProtoReflect.Descriptor instead.
+func (*SimulationStats) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SimulationStats) GetDuels() int32 {
+	if x != nil {
+		return x.Duels
+	}
+	return 0
+}
+
+func (x *SimulationStats) GetPlayerOneWins() int32 {
+	if x != nil {
+		return x.PlayerOneWins
+	}
+	return 0
+}
+
+func (x *SimulationStats) GetPlayerTwoWins() int32 {
+	if x != nil {
+		return x.PlayerTwoWins
+	}
+	return 0
+}
+
+func (x *SimulationStats) GetTies() int32 {
+	if x != nil {
+		return x.Ties
+	}
+	return 0
+}
+
+func (x *SimulationStats) GetTotalRounds() int32 {
+	if x != nil {
+		return x.TotalRounds
+	}
+	return 0
+}
+
+type Progress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Done  int32            `protobuf:"varint,1,opt,name=done,proto3" json:"done,omitempty"`
+	Total int32            `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Stats *SimulationStats `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Progress) GetDone() int32 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *Progress) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Progress) GetStats() *SimulationStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type SimulationResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int64            `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Time      string           `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	PlayerOne string           `protobuf:"bytes,3,opt,name=player_one,json=playerOne,proto3" json:"player_one,omitempty"`
+	PlayerTwo string           `protobuf:"bytes,4,opt,name=player_two,json=playerTwo,proto3" json:"player_two,omitempty"`
+	Rounds    int32            `protobuf:"varint,5,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	Seed      int64            `protobuf:"varint,6,opt,name=seed,proto3" json:"seed,omitempty"`
+	Stats     *SimulationStats `protobuf:"bytes,7,opt,name=stats,proto3" json:"stats,omitempty"`
+}
+
+func (x *SimulationResult) Reset() {
+	*x = SimulationResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimulationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulationResult) ProtoMessage() {}
+
+func (x *SimulationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulationResult.ProtoReflect.Descriptor instead.
+func (*SimulationResult) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SimulationResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SimulationResult) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *SimulationResult) GetPlayerOne() string {
+	if x != nil {
+		return x.PlayerOne
+	}
+	return ""
+}
+
+func (x *SimulationResult) GetPlayerTwo() string {
+	if x != nil {
+		return x.PlayerTwo
+	}
+	return ""
+}
+
+func (x *SimulationResult) GetRounds() int32 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+func (x *SimulationResult) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *SimulationResult) GetStats() *SimulationStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type SimulationMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Message:
+	//	*SimulationMessage_Progress
+	//	*SimulationMessage_Result
+	Message isSimulationMessage_Message `protobuf_oneof:"message"`
+}
+
+func (x *SimulationMessage) Reset() {
+	*x = SimulationMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battle_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimulationMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulationMessage) ProtoMessage() {}
+
+func (x *SimulationMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_battle_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulationMessage.ProtoReflect.Descriptor instead.
+func (*SimulationMessage) Descriptor() ([]byte, []int) {
+	return file_battle_proto_rawDescGZIP(), []int{16}
+}
+
+func (m *SimulationMessage) GetMessage() isSimulationMessage_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (x *SimulationMessage) GetProgress() *Progress {
+	if x, ok := x.GetMessage().(*SimulationMessage_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *SimulationMessage) GetResult() *SimulationResult {
+	if x, ok := x.GetMessage().(*SimulationMessage_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+type isSimulationMessage_Message interface {
+	isSimulationMessage_Message()
+}
+
+type SimulationMessage_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type SimulationMessage_Result struct {
+	Result *SimulationResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*SimulationMessage_Progress) isSimulationMessage_Message() {}
+
+func (*SimulationMessage_Result) isSimulationMessage_Message() {}
+
+var File_battle_proto protoreflect.FileDescriptor
+
+var file_battle_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
+	0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x22, 0x2f, 0x0a, 0x09, 0x53, 0x74, 0x61,
+	0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0xc9, 0x03, 0x0a, 0x09, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x53, 0x70, 0x65, 0x63, 0x12, 0x2c, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c,
+	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x30, 0x0a, 0x08, 0x73, 0x74, 0x72, 0x65, 0x6e, 0x67,
+	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x08,
+	0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x2e, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74,
+	0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x07, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x05, 0x73,
+	0x70, 0x65, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x04, 0x6c, 0x75, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x04, 0x6c, 0x75, 0x63, 0x6b, 0x12, 0x30,
+	0x0a, 0x08, 0x61, 0x63, 0x63, 0x75, 0x72, 0x61, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x08, 0x61, 0x63, 0x63, 0x75, 0x72, 0x61, 0x63, 0x79,
+	0x12, 0x2e, 0x0a, 0x07, 0x65, 0x76, 0x61, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x07, 0x65, 0x76, 0x61, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x35, 0x0a, 0x0b, 0x63, 0x72, 0x69, 0x74, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0a, 0x63, 0x72, 0x69,
+	0x74, 0x43, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x0f, 0x63, 0x72, 0x69, 0x74, 0x5f,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0e, 0x63, 0x72, 0x69, 0x74, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x22, 0x39, 0x0a, 0x0b, 0x53, 0x6b, 0x69, 0x6c, 0x6c, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x22, 0xc4, 0x02, 0x0a, 0x0b, 0x46, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72, 0x53, 0x70, 0x65,
+	0x63, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x53, 0x70, 0x65, 0x63, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x73, 0x12, 0x34, 0x0a, 0x09, 0x6f, 0x66, 0x66, 0x65, 0x6e, 0x73, 0x69, 0x76, 0x65, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x6b, 0x69, 0x6c, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x6f, 0x66,
+	0x66, 0x65, 0x6e, 0x73, 0x69, 0x76, 0x65, 0x12, 0x34, 0x0a, 0x09, 0x64, 0x65, 0x66, 0x65, 0x6e,
+	0x73, 0x69, 0x76, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61, 0x74,
+	0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6b, 0x69, 0x6c, 0x6c, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x09, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x73, 0x69, 0x76, 0x65, 0x12, 0x49, 0x0a,
+	0x0b, 0x72, 0x65, 0x73, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x69, 0x67, 0x68, 0x74, 0x65, 0x72, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x72, 0x65, 0x73,
+	0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x69,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x6b, 0x0a, 0x0b, 0x44, 0x75, 0x65, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72, 0x53, 0x70, 0x65, 0x63,
+	0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x75,
+	0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x73, 0x65, 0x65, 0x64, 0x22, 0x87, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62,
+	0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72,
+	0x53, 0x70, 0x65, 0x63, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72,
+	0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x75, 0x65,
+	0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x75, 0x65, 0x6c, 0x73, 0x22,
+	0x85, 0x02, 0x0a, 0x0b, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74, 0x72, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x73, 0x74, 0x72, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x64, 0x65, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x70,
+	0x65, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x75, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x04, 0x6c, 0x75, 0x63, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x75, 0x72,
+	0x61, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x61, 0x63, 0x63, 0x75, 0x72,
+	0x61, 0x63, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x76, 0x61, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x65, 0x76, 0x61, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a,
+	0x0b, 0x63, 0x72, 0x69, 0x74, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0a, 0x63, 0x72, 0x69, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x27,
+	0x0a, 0x0f, 0x63, 0x72, 0x69, 0x74, 0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65,
+	0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x63, 0x72, 0x69, 0x74, 0x4d, 0x75, 0x6c,
+	0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x22, 0xbb, 0x01, 0x0a, 0x07, 0x46, 0x69, 0x67, 0x68,
+	0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x2c, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62,
+	0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6f,
+	0x66, 0x66, 0x65, 0x6e, 0x73, 0x69, 0x76, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09,
+	0x6f, 0x66, 0x66, 0x65, 0x6e, 0x73, 0x69, 0x76, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x66,
+	0x65, 0x6e, 0x73, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65,
+	0x66, 0x65, 0x6e, 0x73, 0x69, 0x76, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x71, 0x75, 0x69, 0x70,
+	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x65, 0x71, 0x75, 0x69,
+	0x70, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x3a, 0x0a, 0x08, 0x46, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72,
+	0x73, 0x12, 0x2e, 0x0a, 0x08, 0x66, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x46, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72, 0x52, 0x08, 0x66, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72,
+	0x73, 0x22, 0x4d, 0x0a, 0x03, 0x48, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x61, 0x6d, 0x61,
+	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x65, 0x76, 0x61, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x65, 0x76, 0x61, 0x64, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6b, 0x69, 0x6c,
+	0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x73,
+	0x22, 0xda, 0x02, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x12, 0x29, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15,
+	0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x04, 0x68, 0x69, 0x74, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x48, 0x69, 0x74, 0x52, 0x04, 0x68, 0x69, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6b, 0x69,
+	0x6c, 0x6c, 0x73, 0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x88, 0x01, 0x01,
+	0x22, 0x60, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09,
+	0x0a, 0x05, 0x52, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x54, 0x54,
+	0x41, 0x43, 0x4b, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x10,
+	0x03, 0x12, 0x0c, 0x0a, 0x08, 0x4b, 0x4e, 0x4f, 0x43, 0x4b, 0x4f, 0x55, 0x54, 0x10, 0x04, 0x12,
+	0x08, 0x0a, 0x04, 0x46, 0x4c, 0x45, 0x45, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x49, 0x45,
+	0x10, 0x06, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x22, 0xea, 0x01,
+	0x0a, 0x0a, 0x44, 0x75, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x6e, 0x6f, 0x63,
+	0x6b, 0x6f, 0x75, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6b, 0x6e, 0x6f, 0x63,
+	0x6b, 0x6f, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x04, 0x66, 0x6c, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e,
+	0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x73, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6c, 0x6f, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x2e, 0x0a, 0x08, 0x66, 0x69,
+	0x67, 0x68, 0x74, 0x65, 0x72, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62,
+	0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72,
+	0x52, 0x08, 0x66, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72, 0x73, 0x22, 0xbe, 0x01, 0x0a, 0x0b, 0x44,
+	0x75, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x66, 0x69,
+	0x67, 0x68, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x62,
+	0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72,
+	0x73, 0x48, 0x00, 0x52, 0x08, 0x66, 0x69, 0x67, 0x68, 0x74, 0x65, 0x72, 0x73, 0x12, 0x28, 0x0a,
+	0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x62,
+	0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x75, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00,
+	0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xae, 0x01, 0x0a, 0x0f,
+	0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x64, 0x75, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x64, 0x75, 0x65, 0x6c, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f,
+	0x6f, 0x6e, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4f, 0x6e, 0x65, 0x57, 0x69, 0x6e, 0x73, 0x12, 0x26, 0x0a,
+	0x0f, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x74, 0x77, 0x6f, 0x5f, 0x77, 0x69, 0x6e, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x54, 0x77,
+	0x6f, 0x57, 0x69, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x69, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x22, 0x66, 0x0a, 0x08,
+	0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69,
+	0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x73, 0x22, 0xd2, 0x01, 0x0a, 0x10, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4f, 0x6e, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x74, 0x77, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x54, 0x77, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x72,
+	0x6f, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x6f, 0x75,
+	0x6e, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x22, 0x88, 0x01, 0x0a, 0x11, 0x53, 0x69,
+	0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x31, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x35, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48,
+	0x00, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x32, 0x8f, 0x01, 0x0a, 0x06, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x12,
+	0x3b, 0x0a, 0x07, 0x52, 0x75, 0x6e, 0x44, 0x75, 0x65, 0x6c, 0x12, 0x16, 0x2e, 0x62, 0x61, 0x74,
+	0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x75, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x75, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x12, 0x48, 0x0a, 0x08,
+	0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x66, 0x7a, 0x65, 0x72, 0x6f, 0x2f, 0x62, 0x61, 0x74, 0x74,
+	0x6c, 0x65, 0x2d, 0x73, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x3b, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_battle_proto_rawDescOnce sync.Once
+	file_battle_proto_rawDescData = file_battle_proto_rawDesc
+)
+
+func file_battle_proto_rawDescGZIP() []byte {
+	file_battle_proto_rawDescOnce.Do(func() {
+		file_battle_proto_rawDescData = protoimpl.X.CompressGZIP(file_battle_proto_rawDescData)
+	})
+	return file_battle_proto_rawDescData
+}
+
+var file_battle_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_battle_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_battle_proto_goTypes = []any{
+	(Event_Type)(0),           // 0: battle.v1.Event.Type
+	(*StatRange)(nil),         // 1: battle.v1.StatRange
+	(*StatsSpec)(nil),         // 2: battle.v1.StatsSpec
+	(*SkillConfig)(nil),       // 3: battle.v1.SkillConfig
+	(*FighterSpec)(nil),       // 4: battle.v1.FighterSpec
+	(*DuelRequest)(nil),       // 5: battle.v1.DuelRequest
+	(*SimulationRequest)(nil), // 6: battle.v1.SimulationRequest
+	(*PlayerStats)(nil),       // 7: battle.v1.PlayerStats
+	(*Fighter)(nil),           // 8: battle.v1.Fighter
+	(*Fighters)(nil),          // 9: battle.v1.Fighters
+	(*Hit)(nil),               // 10: battle.v1.Hit
+	(*Event)(nil),             // 11: battle.v1.Event
+	(*DuelResult)(nil),        // 12: battle.v1.DuelResult
+	(*DuelMessage)(nil),       // 13: battle.v1.DuelMessage
+	(*SimulationStats)(nil),   // 14: battle.v1.SimulationStats
+	(*Progress)(nil),          // 15: battle.v1.Progress
+	(*SimulationResult)(nil),  // 16: battle.v1.SimulationResult
+	(*SimulationMessage)(nil), // 17: battle.v1.SimulationMessage
+	nil,                       // 18: battle.v1.FighterSpec.ResistancesEntry
+}
+var file_battle_proto_depIdxs = []int32{
+	1,  // 0: battle.v1.StatsSpec.health:type_name -> battle.v1.StatRange
+	1,  // 1: battle.v1.StatsSpec.strength:type_name -> battle.v1.StatRange
+	1,  // 2: battle.v1.StatsSpec.defence:type_name -> battle.v1.StatRange
+	1,  // 3: battle.v1.StatsSpec.speed:type_name -> battle.v1.StatRange
+	1,  // 4: battle.v1.StatsSpec.luck:type_name -> battle.v1.StatRange
+	1,  // 5: battle.v1.StatsSpec.accuracy:type_name -> battle.v1.StatRange
+	1,  // 6: battle.v1.StatsSpec.evasion:type_name -> battle.v1.StatRange
+	1,  // 7: battle.v1.StatsSpec.crit_chance:type_name -> battle.v1.StatRange
+	1,  // 8: battle.v1.StatsSpec.crit_multiplier:type_name -> battle.v1.StatRange
+	2,  // 9: battle.v1.FighterSpec.stats:type_name -> battle.v1.StatsSpec
+	3,  // 10: battle.v1.FighterSpec.offensive:type_name -> battle.v1.SkillConfig
+	3,  // 11: battle.v1.FighterSpec.defensive:type_name -> battle.v1.SkillConfig
+	18, // 12: battle.v1.FighterSpec.resistances:type_name -> battle.v1.FighterSpec.ResistancesEntry
+	4,  // 13: battle.v1.DuelRequest.players:type_name -> battle.v1.FighterSpec
+	4,  // 14: battle.v1.SimulationRequest.players:type_name -> battle.v1.FighterSpec
+	7,  // 15: battle.v1.Fighter.stats:type_name -> battle.v1.PlayerStats
+	8,  // 16: battle.v1.Fighters.fighters:type_name -> battle.v1.Fighter
+	0,  // 17: battle.v1.Event.type:type_name -> battle.v1.Event.Type
+	10, // 18: battle.v1.Event.hits:type_name -> battle.v1.Hit
+	8,  // 19: battle.v1.DuelResult.fighters:type_name -> battle.v1.Fighter
+	9,  // 20: battle.v1.DuelMessage.fighters:type_name -> battle.v1.Fighters
+	11, // 21: battle.v1.DuelMessage.event:type_name -> battle.v1.Event
+	12, // 22: battle.v1.DuelMessage.result:type_name -> battle.v1.DuelResult
+	14, // 23: battle.v1.Progress.stats:type_name -> battle.v1.SimulationStats
+	14, // 24: battle.v1.SimulationResult.stats:type_name -> battle.v1.SimulationStats
+	15, // 25: battle.v1.SimulationMessage.progress:type_name -> battle.v1.Progress
+	16, // 26: battle.v1.SimulationMessage.result:type_name -> battle.v1.SimulationResult
+	5,  // 27: battle.v1.Battle.RunDuel:input_type -> battle.v1.DuelRequest
+	6,  // 28: battle.v1.Battle.Simulate:input_type -> battle.v1.SimulationRequest
+	13, // 29: battle.v1.Battle.RunDuel:output_type -> battle.v1.DuelMessage
+	17, // 30: battle.v1.Battle.Simulate:output_type -> battle.v1.SimulationMessage
+	29, // [29:31] is the sub-list for method output_type
+	27, // [27:29] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
+}
+
+func init() { file_battle_proto_init() }
+func file_battle_proto_init() {
+	if File_battle_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_battle_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*StatRange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*StatsSpec); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*SkillConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*FighterSpec); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*DuelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*SimulationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*PlayerStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*Fighter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*Fighters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*Hit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*DuelResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*DuelMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*SimulationStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*Progress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*SimulationResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battle_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*SimulationMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_battle_proto_msgTypes[10].OneofWrappers = []any{}
+	file_battle_proto_msgTypes[12].OneofWrappers = []any{
+		(*DuelMessage_Fighters)(nil),
+		(*DuelMessage_Event)(nil),
+		(*DuelMessage_Result)(nil),
+		(*DuelMessage_Error)(nil),
+	}
+	file_battle_proto_msgTypes[16].OneofWrappers = []any{
+		(*SimulationMessage_Progress)(nil),
+		(*SimulationMessage_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_battle_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_battle_proto_goTypes,
+		DependencyIndexes: file_battle_proto_depIdxs,
+		EnumInfos:         file_battle_proto_enumTypes,
+		MessageInfos:      file_battle_proto_msgTypes,
+	}.Build()
+	File_battle_proto = out.File
+	file_battle_proto_rawDesc = nil
+	file_battle_proto_goTypes = nil
+	file_battle_proto_depIdxs = nil
+}
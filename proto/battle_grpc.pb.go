@@ -0,0 +1,219 @@
+// The Battle service runs duels and simulations and streams their
+// events as they happen. The server package serves it with gRPC (see
+// server.NewGRPC; battle.pb.go and battle_grpc.pb.go are generated
+// from this file with protoc-gen-go and protoc-gen-go-grpc) and, for
+// the clients without gRPC, over HTTP (POST /duels/stream and POST
+// /simulations/stream): the request is the canonical JSON form
+// (protojson) of the request message and every line of the response is
+// the protojson form of a message of the stream. The HTTP requests may
+// also use the server's shorthands, e.g. fixed stats written as numbers
+// and skill params as JSON objects.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: battle.proto
+
+package battlepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Battle_RunDuel_FullMethodName  = "/battle.v1.Battle/RunDuel"
+	Battle_Simulate_FullMethodName = "/battle.v1.Battle/Simulate"
+)
+
+// BattleClient is the client API for Battle service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BattleClient interface {
+	// RunDuel fights a duel and streams the fighters, every event
+	// (round start, attack with its hits, action, outcome) and the result
+	RunDuel(ctx context.Context, in *DuelRequest, opts ...grpc.CallOption) (Battle_RunDuelClient, error)
+	// Simulate runs many duels and streams the progress and the result
+	Simulate(ctx context.Context, in *SimulationRequest, opts ...grpc.CallOption) (Battle_SimulateClient, error)
+}
+
+type battleClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBattleClient(cc grpc.ClientConnInterface) BattleClient {
+	return &battleClient{cc}
+}
+
+func (c *battleClient) RunDuel(ctx context.Context, in *DuelRequest, opts ...grpc.CallOption) (Battle_RunDuelClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Battle_ServiceDesc.Streams[0], Battle_RunDuel_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &battleRunDuelClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Battle_RunDuelClient interface {
+	Recv() (*DuelMessage, error)
+	grpc.ClientStream
+}
+
+type battleRunDuelClient struct {
+	grpc.ClientStream
+}
+
+func (x *battleRunDuelClient) Recv() (*DuelMessage, error) {
+	m := new(DuelMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *battleClient) Simulate(ctx context.Context, in *SimulationRequest, opts ...grpc.CallOption) (Battle_SimulateClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Battle_ServiceDesc.Streams[1], Battle_Simulate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &battleSimulateClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Battle_SimulateClient interface {
+	Recv() (*SimulationMessage, error)
+	grpc.ClientStream
+}
+
+type battleSimulateClient struct {
+	grpc.ClientStream
+}
+
+func (x *battleSimulateClient) Recv() (*SimulationMessage, error) {
+	m := new(SimulationMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BattleServer is the server API for Battle service.
+// All implementations must embed UnimplementedBattleServer
+// for forward compatibility
+type BattleServer interface {
+	// RunDuel fights a duel and streams the fighters, every event
+	// (round start, attack with its hits, action, outcome) and the result
+	RunDuel(*DuelRequest, Battle_RunDuelServer) error
+	// Simulate runs many duels and streams the progress and the result
+	Simulate(*SimulationRequest, Battle_SimulateServer) error
+	mustEmbedUnimplementedBattleServer()
+}
+
+// UnimplementedBattleServer must be embedded to have forward compatible implementations.
+type UnimplementedBattleServer struct {
+}
+
+func (UnimplementedBattleServer) RunDuel(*DuelRequest, Battle_RunDuelServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunDuel not implemented")
+}
+func (UnimplementedBattleServer) Simulate(*SimulationRequest, Battle_SimulateServer) error {
+	return status.Errorf(codes.Unimplemented, "method Simulate not implemented")
+}
+func (UnimplementedBattleServer) mustEmbedUnimplementedBattleServer() {}
+
+// UnsafeBattleServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BattleServer will
+// result in compilation errors.
+type UnsafeBattleServer interface {
+	mustEmbedUnimplementedBattleServer()
+}
+
+func RegisterBattleServer(s grpc.ServiceRegistrar, srv BattleServer) {
+	s.RegisterService(&Battle_ServiceDesc, srv)
+}
+
+func _Battle_RunDuel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DuelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BattleServer).RunDuel(m, &battleRunDuelServer{ServerStream: stream})
+}
+
+type Battle_RunDuelServer interface {
+	Send(*DuelMessage) error
+	grpc.ServerStream
+}
+
+type battleRunDuelServer struct {
+	grpc.ServerStream
+}
+
+func (x *battleRunDuelServer) Send(m *DuelMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Battle_Simulate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SimulationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BattleServer).Simulate(m, &battleSimulateServer{ServerStream: stream})
+}
+
+type Battle_SimulateServer interface {
+	Send(*SimulationMessage) error
+	grpc.ServerStream
+}
+
+type battleSimulateServer struct {
+	grpc.ServerStream
+}
+
+func (x *battleSimulateServer) Send(m *SimulationMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Battle_ServiceDesc is the grpc.ServiceDesc for Battle service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Battle_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "battle.v1.Battle",
+	HandlerType: (*BattleServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunDuel",
+			Handler:       _Battle_RunDuel_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Simulate",
+			Handler:       _Battle_Simulate_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "battle.proto",
+}
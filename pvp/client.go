@@ -0,0 +1,48 @@
+package pvp
+
+import (
+	"encoding/json"
+	"net"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/server"
+)
+
+// Client is the connection of a player to the server
+type Client struct {
+	conn    net.Conn
+	decoder *json.Decoder
+	encoder *json.Encoder
+}
+
+// Dial connects to the server at the TCP address and joins with the fighter
+func Dial(addr string, fighter server.FighterSpec) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: conn, decoder: json.NewDecoder(conn), encoder: json.NewEncoder(conn)}
+	if err := c.encoder.Encode(Message{Type: JoinMessage, Fighter: &fighter}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Receive waits for the next message of the server
+func (c *Client) Receive() (Message, error) {
+	var m Message
+	err := c.decoder.Decode(&m)
+	return m, err
+}
+
+// Act sends the action of the player's turn
+func (c *Client) Act(kind core.ActionKind) error {
+	return c.encoder.Encode(Message{Type: ActMessage, Action: kind.String()})
+}
+
+// Close disconnects the player; during a duel, he forfeits it
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,86 @@
+package pvp
+
+import (
+	"fmt"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/history"
+	"github.com/pfzero/battle-simulator/server"
+)
+
+// MessageType represents the kind of a message of the protocol
+type MessageType string
+
+// the messages sent by the clients
+const (
+	// JoinMessage is the first message of a client, with his fighter
+	JoinMessage MessageType = "join"
+	// ActMessage is the action chosen by the client for his turn
+	ActMessage MessageType = "act"
+)
+
+// the messages sent by the server
+const (
+	// WaitingMessage tells the client he waits for an opponent
+	WaitingMessage MessageType = "waiting"
+	// MatchedMessage presents the fighters, in the order they attack
+	MatchedMessage MessageType = "matched"
+	// EventMessage is an event of the duel
+	EventMessage MessageType = "event"
+	// TurnMessage asks the client for the action of his turn
+	TurnMessage MessageType = "turn"
+	// TimeoutMessage tells the client he didn't act in time and attacked
+	TimeoutMessage MessageType = "timeout"
+	// ResultMessage ends the duel; the connection is closed after it
+	ResultMessage MessageType = "result"
+	// ErrorMessage reports an invalid message; the connection is closed
+	// after it when the client couldn't join
+	ErrorMessage MessageType = "error"
+)
+
+// Message is a message of the protocol; the messages are JSON documents,
+// one per line, and only the fields of their type are set
+type Message struct {
+	Type MessageType `json:"type"`
+
+	// Fighter is the build of the joining client
+	Fighter *server.FighterSpec `json:"fighter,omitempty"`
+	// Action is the action chosen by the client: attack, defend, heal or flee
+	Action string `json:"action,omitempty"`
+
+	// You is the name of the client's fighter, which can differ
+	// from the one he joined with when both fighters have the same name
+	You      string            `json:"you,omitempty"`
+	Fighters []history.Fighter `json:"fighters,omitempty"`
+	Event    *history.Event    `json:"event,omitempty"`
+	Turn     *Turn             `json:"turn,omitempty"`
+	// Result is the duel, without its replay which was already sent
+	Result *history.Record `json:"result,omitempty"`
+	// Forfeit is the name of the fighter who lost by disconnecting
+	Forfeit string `json:"forfeit,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Turn describes the duel to the client who has to act
+type Turn struct {
+	Round  int `json:"round"`
+	Rounds int `json:"rounds"`
+
+	Health         float64 `json:"health"`
+	OpponentHealth float64 `json:"opponentHealth"`
+	// Timeout is the number of seconds the client has to act
+	Timeout float64 `json:"timeout"`
+}
+
+// actions are the actions the clients can choose
+var actions = []core.ActionKind{core.AttackAction, core.DefendAction, core.HealAction, core.FleeAction}
+
+// ParseAction reads an action sent by a client
+func ParseAction(name string) (core.ActionKind, error) {
+	for _, kind := range actions {
+		if kind.String() == name {
+			return kind, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown action %q, expected attack, defend, heal or flee", name)
+}
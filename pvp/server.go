@@ -0,0 +1,340 @@
+// Package pvp fights duels between players connecting over the network:
+// the server pairs the clients as they join, each with his fighter, fights
+// their duel and sends its events to both of them, asking every client for
+// the action of his turn.
+//
+// The protocol is made of JSON messages, one per line, over TCP: the client
+// joins with his fighter and then answers every turn message with an act
+// message. Clients who don't act in time attack and clients who disconnect
+// forfeit the duel, on their next turn. Messages larger than 64 KiB are
+// refused
+package pvp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/history"
+	"github.com/pfzero/battle-simulator/metrics"
+)
+
+// maxMessageSize bounds the size of the messages read from the clients
+const maxMessageSize = 64 << 10
+
+// Server pairs the connecting clients and fights their duels
+type Server struct {
+	Rounds        int
+	DamageFormula core.DamageFormula
+	// TurnTimeout bounds the time a client has to act
+	// and the time it has to receive a message
+	TurnTimeout time.Duration
+	// JoinTimeout bounds the time a client has to join once connected
+	JoinTimeout time.Duration
+
+	// Budget, when set, is the point-buy budget the fighters must fit in
+	Budget *core.Budget
+
+	// Metrics and History, when set, instrument and keep the duels
+	Metrics *metrics.Metrics
+	History *history.Store
+
+	mu      sync.Mutex
+	waiting *player
+}
+
+// NewServer creates a server fighting duels of 20 rounds,
+// with 30 seconds for every turn
+func NewServer(formula core.DamageFormula) *Server {
+	return &Server{
+		Rounds:        20,
+		DamageFormula: formula,
+		TurnTimeout:   30 * time.Second,
+		JoinTimeout:   10 * time.Second,
+	}
+}
+
+// ListenAndServe listens on the TCP address and serves the clients
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve serves the clients connecting to the listener
+// until accepting a connection fails
+func (s *Server) Serve(l net.Listener) error {
+	defer l.Close()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle reads the fighter of the client and pairs him
+func (s *Server) handle(conn net.Conn) {
+	p := newPlayer(conn, s.TurnTimeout)
+
+	conn.SetReadDeadline(time.Now().Add(s.JoinTimeout))
+	var join Message
+	if err := p.receive(&join); err != nil {
+		p.refuse(fmt.Errorf("invalid join message: %v", err))
+		return
+	}
+	if join.Type != JoinMessage || join.Fighter == nil {
+		p.refuse(fmt.Errorf("the first message must join with a fighter"))
+		return
+	}
+	template, err := join.Fighter.Template()
+	if err != nil {
+		p.refuse(err)
+		return
+	}
+	if s.Budget != nil {
+		if err := s.Budget.Check(template); err != nil {
+			p.refuse(err)
+			return
+		}
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	p.template = template
+	go p.read()
+	s.pair(p)
+}
+
+// pair matches the player with the one waiting, if any is still
+// connected; otherwise the player waits for the next one
+func (s *Server) pair(p *player) {
+	s.mu.Lock()
+	opponent := s.waiting
+	if opponent != nil && !opponent.disconnected() {
+		s.waiting = nil
+		s.mu.Unlock()
+		s.match(opponent, p)
+		return
+	}
+	s.waiting = p
+	s.mu.Unlock()
+	p.send(Message{Type: WaitingMessage})
+}
+
+// match fights the duel of the 2 players and sends them its result
+func (s *Server) match(a, b *player) {
+	defer a.close()
+	defer b.close()
+
+	if a.template.Name == b.template.Name {
+		b.template.Name += " (2)"
+	}
+	one, two := a.template.Summon(), b.template.Summon()
+	one.Strategy, two.Strategy = a, b
+	a.name, b.name = one.Name, two.Name
+
+	players := []*player{a, b}
+	broadcast := func(m Message) {
+		for _, p := range players {
+			p.send(m)
+		}
+	}
+
+	recorder := &history.Recorder{
+		OnFighters: func(fighters []history.Fighter) {
+			for _, p := range players {
+				p.send(Message{Type: MatchedMessage, You: p.name, Fighters: fighters})
+			}
+		},
+		OnEvent: func(e history.Event) {
+			broadcast(Message{Type: EventMessage, Event: &e})
+		},
+	}
+	var commentator core.Commentator = recorder
+	if s.Metrics != nil {
+		commentator = core.Commentators{s.Metrics.Collector(), recorder}
+	}
+
+	dm := &core.DuelMaster{
+		Rounds:        s.Rounds,
+		PlayerOne:     one,
+		PlayerTwo:     two,
+		DamageFormula: s.DamageFormula,
+	}
+	result := dm.StartDuel(commentator)
+
+	record := history.NewRecord(result, recorder)
+	if s.History != nil {
+		if err := s.History.Save(record); err != nil {
+			broadcast(Message{Type: ErrorMessage, Error: fmt.Sprintf("the duel wasn't saved: %v", err)})
+		}
+	}
+	// the events were already sent
+	record.Replay = nil
+
+	m := Message{Type: ResultMessage, Result: record}
+	for _, p := range players {
+		if p.forfeited {
+			m.Forfeit = p.name
+		}
+	}
+	broadcast(m)
+}
+
+// player is a connected client; it's the strategy of his fighter
+type player struct {
+	conn net.Conn
+	// limit bounds the bytes the decoder reads for every message
+	limit   *io.LimitedReader
+	decoder *json.Decoder
+	timeout time.Duration
+
+	sending sync.Mutex
+	encoder *json.Encoder
+
+	template core.PlayerTemplate
+	name     string
+
+	// turn counts the turn messages sent to the client
+	turn int64
+
+	// actions are the actions read from the client; gone is closed
+	// when the connection fails and done when the duel is over
+	actions chan action
+	gone    chan struct{}
+	done    chan struct{}
+
+	forfeited bool
+}
+
+func newPlayer(conn net.Conn, timeout time.Duration) *player {
+	limit := &io.LimitedReader{R: conn}
+	return &player{
+		conn:    conn,
+		limit:   limit,
+		decoder: json.NewDecoder(limit),
+		encoder: json.NewEncoder(conn),
+		timeout: timeout,
+		actions: make(chan action, 1),
+		gone:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// send writes the message; the errors are noticed by read
+func (p *player) send(m Message) {
+	p.sending.Lock()
+	defer p.sending.Unlock()
+	p.conn.SetWriteDeadline(time.Now().Add(p.timeout))
+	p.encoder.Encode(m)
+}
+
+// receive reads the next message of the client; the messages
+// larger than maxMessageSize are refused
+func (p *player) receive(m *Message) error {
+	p.limit.N = maxMessageSize
+	err := p.decoder.Decode(m)
+	if err != nil && p.limit.N == 0 {
+		return fmt.Errorf("the messages are limited to %d bytes", maxMessageSize)
+	}
+	return err
+}
+
+// refuse tells the client why he can't join and disconnects him
+func (p *player) refuse(err error) {
+	p.send(Message{Type: ErrorMessage, Error: err.Error()})
+	p.conn.Close()
+}
+
+// read reads the actions of the client until he disconnects
+func (p *player) read() {
+	defer p.conn.Close()
+	defer close(p.gone)
+	for {
+		var m Message
+		if err := p.receive(&m); err != nil {
+			return
+		}
+		if m.Type != ActMessage {
+			p.send(Message{Type: ErrorMessage, Error: fmt.Sprintf("unexpected %q message", m.Type)})
+			continue
+		}
+		kind, err := ParseAction(m.Action)
+		if err != nil {
+			p.send(Message{Type: ErrorMessage, Error: err.Error()})
+			continue
+		}
+		select {
+		case p.actions <- action{kind: kind, turn: atomic.LoadInt64(&p.turn)}:
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *player) disconnected() bool {
+	select {
+	case <-p.gone:
+		return true
+	default:
+		return false
+	}
+}
+
+// close ends the duel of the player and disconnects him
+func (p *player) close() {
+	close(p.done)
+	p.conn.Close()
+}
+
+// ChooseAction asks the client for his action; he attacks when he doesn't
+// act in time and flees, forfeiting the duel, when he disconnected
+func (p *player) ChooseAction(state core.DuelState) core.Action {
+	if p.forfeited {
+		return core.Action{Kind: core.FleeAction}
+	}
+
+	// the actions read before the turn message was sent answer
+	// an earlier turn, or none, so they are ignored
+	turn := atomic.AddInt64(&p.turn, 1)
+	p.send(Message{Type: TurnMessage, Turn: &Turn{
+		Round:          state.Round,
+		Rounds:         state.Rounds,
+		Health:         state.Self.Health,
+		OpponentHealth: state.Opponent.Health,
+		Timeout:        p.timeout.Seconds(),
+	}})
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case a := <-p.actions:
+			if a.turn != turn {
+				continue
+			}
+			return core.Action{Kind: a.kind}
+		case <-p.gone:
+			p.forfeited = true
+			return core.Action{Kind: core.FleeAction}
+		case <-timer.C:
+			p.send(Message{Type: TimeoutMessage})
+			return core.Action{Kind: core.AttackAction}
+		}
+	}
+}
+
+// action is an action of the client with the
+// turn that was asked for when it was read
+type action struct {
+	kind core.ActionKind
+	turn int64
+}
@@ -0,0 +1,379 @@
+package pvp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/history"
+	_ "github.com/pfzero/battle-simulator/script"
+	"github.com/pfzero/battle-simulator/server"
+)
+
+func fighter(name string, health, strength, defence, speed float64) server.FighterSpec {
+	return server.FighterSpec{
+		Name: name,
+		Stats: server.StatsSpec{
+			Health:   server.RangeSpec{Min: health, Max: health},
+			Strength: server.RangeSpec{Min: strength, Max: strength},
+			Defence:  server.RangeSpec{Min: defence, Max: defence},
+			Speed:    server.RangeSpec{Min: speed, Max: speed},
+		},
+	}
+}
+
+// the hero knocks the villain out on round 10 when both attack
+var (
+	hero    = fighter("Hero", 100, 60, 0, 2)
+	villain = fighter("Villain", 100, 10, 50, 1)
+)
+
+func newTestServer(t *testing.T, timeout time.Duration) (*Server, net.Listener) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(nil)
+	s.TurnTimeout = timeout
+	s.History = history.New()
+	go s.Serve(l)
+	return s, l
+}
+
+// join connects the fighter and, when he is the first one, waits to be paired
+func join(t *testing.T, l net.Listener, f server.FighterSpec, first bool) *Client {
+	t.Helper()
+	c, err := Dial(l.Addr().String(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first {
+		if m, err := c.Receive(); err != nil || m.Type != WaitingMessage {
+			t.Fatalf("%s joined with %+v, %v; want a waiting message", f.Name, m, err)
+		}
+	}
+	return c
+}
+
+func always(kind core.ActionKind) func(Turn) (core.ActionKind, bool) {
+	return func(Turn) (core.ActionKind, bool) { return kind, true }
+}
+
+func never(Turn) (core.ActionKind, bool) { return 0, false }
+
+// play receives the messages of the duel until its result, answering the
+// turns with the action chosen by act, if any; it runs in its own goroutine
+func play(c *Client, act func(Turn) (core.ActionKind, bool), messages chan<- []Message) {
+	received := []Message{}
+	defer func() { messages <- received }()
+	for {
+		m, err := c.Receive()
+		if err != nil {
+			return
+		}
+		received = append(received, m)
+		switch m.Type {
+		case TurnMessage:
+			if kind, ok := act(*m.Turn); ok {
+				c.Act(kind)
+			}
+		case ResultMessage:
+			return
+		}
+	}
+}
+
+func last(messages []Message) Message {
+	if len(messages) == 0 {
+		return Message{}
+	}
+	return messages[len(messages)-1]
+}
+
+func count(messages []Message, t MessageType) int {
+	n := 0
+	for _, m := range messages {
+		if m.Type == t {
+			n++
+		}
+	}
+	return n
+}
+
+func TestServer_Duel(t *testing.T) {
+	s, l := newTestServer(t, time.Second)
+	defer l.Close()
+
+	one := join(t, l, hero, true)
+	two := join(t, l, villain, false)
+	defer one.Close()
+	defer two.Close()
+
+	heroMessages, villainMessages := make(chan []Message), make(chan []Message)
+	go play(one, always(core.AttackAction), heroMessages)
+	go play(two, always(core.AttackAction), villainMessages)
+
+	for _, tt := range []struct {
+		name     string
+		messages []Message
+	}{
+		{"Hero", <-heroMessages},
+		{"Villain", <-villainMessages},
+	} {
+		matched := tt.messages[0]
+		if matched.Type != MatchedMessage || matched.You != tt.name || len(matched.Fighters) != 2 || matched.Fighters[0].Name != "Hero" {
+			t.Errorf("%s was matched with %+v", tt.name, matched)
+		}
+		if turns := count(tt.messages, TurnMessage); turns != 10 && turns != 9 {
+			t.Errorf("%s was asked for %d actions", tt.name, turns)
+		}
+
+		result := last(tt.messages)
+		if result.Type != ResultMessage || result.Result == nil {
+			t.Fatalf("%s received %+v instead of the result", tt.name, result)
+		}
+		r := result.Result
+		if r.ID != 1 || r.Winner != "Hero" || !r.Knockout || r.Rounds != 10 || r.Replay != nil || result.Forfeit != "" {
+			t.Errorf("%s received the result %+v", tt.name, r)
+		}
+		if events := count(tt.messages, EventMessage); events != 30 {
+			t.Errorf("%s received %d events, want 30", tt.name, events)
+		}
+	}
+
+	if n := s.History.Len(); n != 1 {
+		t.Errorf("the history has %d duels, want 1", n)
+	}
+}
+
+func TestServer_Forfeit(t *testing.T) {
+	_, l := newTestServer(t, time.Second)
+	defer l.Close()
+
+	one := join(t, l, hero, true)
+	two := join(t, l, villain, false)
+	defer one.Close()
+
+	heroMessages, villainMessages := make(chan []Message), make(chan []Message)
+	go play(one, always(core.AttackAction), heroMessages)
+	// the villain disconnects when he has to act on round 3
+	go play(two, func(turn Turn) (core.ActionKind, bool) {
+		if turn.Round == 3 {
+			two.Close()
+			return 0, false
+		}
+		return core.AttackAction, true
+	}, villainMessages)
+
+	if m := last(<-villainMessages); m.Type != TurnMessage {
+		t.Errorf("the villain received %+v after disconnecting", m)
+	}
+	result := last(<-heroMessages)
+	if result.Type != ResultMessage || result.Forfeit != "Villain" {
+		t.Fatalf("the hero received %+v, want the villain to forfeit", result)
+	}
+	if r := result.Result; r.Winner != "Hero" || !r.Fled || r.Rounds != 3 {
+		t.Errorf("the hero received the result %+v", r)
+	}
+}
+
+func TestServer_TurnTimeout(t *testing.T) {
+	_, l := newTestServer(t, 20*time.Millisecond)
+	defer l.Close()
+
+	one := join(t, l, hero, true)
+	two := join(t, l, villain, false)
+	defer one.Close()
+	defer two.Close()
+
+	heroMessages, villainMessages := make(chan []Message), make(chan []Message)
+	go play(one, always(core.AttackAction), heroMessages)
+	go play(two, never, villainMessages)
+
+	messages := <-villainMessages
+	<-heroMessages
+	if timeouts := count(messages, TimeoutMessage); timeouts != 9 {
+		t.Errorf("the villain timed out %d times, want 9", timeouts)
+	}
+	// the villain attacked instead
+	if r := last(messages).Result; r == nil || r.Winner != "Hero" || r.Rounds != 10 {
+		t.Errorf("the villain received %+v", last(messages))
+	}
+}
+
+func TestServer_SameNames(t *testing.T) {
+	_, l := newTestServer(t, time.Second)
+	defer l.Close()
+
+	one := join(t, l, villain, true)
+	two := join(t, l, villain, false)
+	defer one.Close()
+	defer two.Close()
+
+	oneMessages, twoMessages := make(chan []Message), make(chan []Message)
+	go play(one, always(core.FleeAction), oneMessages)
+	go play(two, always(core.FleeAction), twoMessages)
+
+	<-oneMessages
+	messages := <-twoMessages
+	if you := messages[0].You; you != "Villain (2)" {
+		t.Errorf("the second villain is %q, want %q", you, "Villain (2)")
+	}
+	if r := last(messages).Result; r == nil || r.Loser != "Villain" || r.Winner != "Villain (2)" {
+		t.Errorf("the second villain received %+v", last(messages))
+	}
+}
+
+func TestServer_InvalidJoin(t *testing.T) {
+	_, l := newTestServer(t, time.Second)
+	defer l.Close()
+
+	reversed := hero
+	reversed.Stats.Strength = server.RangeSpec{Min: 80, Max: 60}
+
+	for _, tt := range []struct {
+		name    string
+		fighter server.FighterSpec
+	}{
+		{"no name", fighter("", 100, 10, 10, 10)},
+		{"no health", fighter("Ghost", 0, 10, 10, 10)},
+		{"reversed range", reversed},
+		{"scripted skill", server.FighterSpec{
+			Name:      "Scripter",
+			Stats:     hero.Stats,
+			Offensive: []core.SkillConfig{{Type: "Script"}},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Dial(l.Addr().String(), tt.fighter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			if m, err := c.Receive(); err != nil || m.Type != ErrorMessage || m.Error == "" {
+				t.Errorf("joining = %+v, %v; want an error message", m, err)
+			}
+			if _, err := c.Receive(); !errors.Is(err, io.EOF) {
+				t.Errorf("the connection wasn't closed: %v", err)
+			}
+		})
+	}
+}
+
+func TestServer_JoinTooLarge(t *testing.T) {
+	_, l := newTestServer(t, time.Second)
+	defer l.Close()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the message never ends: it would be read until the join timeout
+	start := `{"type": "join", "fighter": {"name": "`
+	if _, err := conn.Write([]byte(start + strings.Repeat("A", maxMessageSize-len(start)))); err != nil {
+		t.Fatal(err)
+	}
+	var m Message
+	if err := json.NewDecoder(conn).Decode(&m); err != nil || m.Type != ErrorMessage || !strings.Contains(m.Error, "limited") {
+		t.Errorf("joining = %+v, %v; want an error message", m, err)
+	}
+}
+
+func TestServer_Budget(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	s := NewServer(nil)
+	s.Budget = &core.Budget{Points: 200, Stats: map[string]float64{"Health": 1, "Strength": 1}}
+	go s.Serve(l)
+
+	// the hero costs 160 points and the villain 110
+	expensive := hero
+	expensive.Stats.Health = server.RangeSpec{Min: 100, Max: 150}
+	for _, tt := range []struct {
+		name    string
+		fighter server.FighterSpec
+		want    MessageType
+	}{
+		{"within the budget", hero, WaitingMessage},
+		{"above the budget", expensive, ErrorMessage},
+		{"unpriced skills", server.FighterSpec{
+			Name:      "Critter",
+			Stats:     villain.Stats,
+			Offensive: []core.SkillConfig{{Type: "CriticalStrike"}},
+		}, ErrorMessage},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Dial(l.Addr().String(), tt.fighter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			if m, err := c.Receive(); err != nil || m.Type != tt.want {
+				t.Errorf("joining = %+v, %v; want a %s message", m, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		want    core.ActionKind
+		wantErr bool
+	}{
+		{"attack", core.AttackAction, false},
+		{"defend", core.DefendAction, false},
+		{"heal", core.HealAction, false},
+		{"flee", core.FleeAction, false},
+		{"ability", 0, true},
+		{"dance", 0, true},
+	} {
+		got, err := ParseAction(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAction(%q) = %v, %v", tt.name, got, err)
+		}
+	}
+}
+
+func TestPlayer_ChooseAction_OutOfTurn(t *testing.T) {
+	conn, client := net.Pipe()
+	defer client.Close()
+	p := newPlayer(conn, time.Second)
+	go p.read()
+
+	// one action is buffered and the other one waits to be
+	encoder, decoder := json.NewEncoder(client), json.NewDecoder(client)
+	for i := 0; i < 2; i++ {
+		if err := encoder.Encode(Message{Type: ActMessage, Action: core.HealAction.String()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	hero := core.NewPlayer("Hero", core.PlayerStats{Health: 100}, core.PlayerSkills{})
+	chosen := make(chan core.Action)
+	go func() {
+		chosen <- p.ChooseAction(core.DuelState{Round: 1, Rounds: 20, Self: hero, Opponent: hero})
+	}()
+
+	var m Message
+	if err := decoder.Decode(&m); err != nil || m.Type != TurnMessage {
+		t.Fatalf("received %+v, %v; want a turn message", m, err)
+	}
+	encoder.Encode(Message{Type: ActMessage, Action: core.DefendAction.String()})
+	if a := <-chosen; a.Kind != core.DefendAction {
+		t.Errorf("ChooseAction() = %v, want the action sent after the turn message", a.Kind)
+	}
+}
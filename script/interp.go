@@ -0,0 +1,459 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// value is a value of the scripting language:
+// nil, bool, float64, string, builtin, object or list
+type value interface{}
+
+// builtin is a function provided by the host
+type builtin func(args []value) (value, error)
+
+// object is a host value with named fields
+type object interface {
+	get(field string) (value, error)
+	set(field string, v value) error
+}
+
+// list is a host value indexed from 1
+type list interface {
+	index(i int) (value, error)
+	length() int
+}
+
+// maxSteps limits the number of statements and loop iterations of a
+// run, so that a script can't block the duel with an endless loop
+const maxSteps = 100000
+
+var (
+	errBreak       = errors.New("break outside of a loop")
+	errStepsExceed = fmt.Errorf("the script exceeded %d steps", maxSteps)
+)
+
+// returned carries the value of a return statement
+type returned struct{ value value }
+
+func (r *returned) Error() string { return "return" }
+
+// scope holds the local variables of a block
+type scope struct {
+	vars   map[string]value
+	parent *scope
+}
+
+func (s *scope) lookup(name string) (*scope, bool) {
+	for ; s != nil; s = s.parent {
+		if _, ok := s.vars[name]; ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// machine runs a script; host holds the read-only values given to the
+// script while globals holds the variables the script assigns, which
+// are kept between runs
+type machine struct {
+	host    map[string]value
+	globals map[string]value
+	steps   int
+}
+
+// run executes the statements and returns the returned value, if any
+func (m *machine) run(block []stat) (value, error) {
+	m.steps = 0
+	err := m.block(block, &scope{vars: map[string]value{}})
+	if r, ok := err.(*returned); ok {
+		return r.value, nil
+	}
+	return nil, err
+}
+
+func (m *machine) step() error {
+	m.steps++
+	if m.steps > maxSteps {
+		return errStepsExceed
+	}
+	return nil
+}
+
+func (m *machine) block(block []stat, parent *scope) error {
+	s := &scope{vars: map[string]value{}, parent: parent}
+	for _, st := range block {
+		if err := m.step(); err != nil {
+			return err
+		}
+		if err := m.stat(st, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *machine) stat(st stat, s *scope) error {
+	switch st := st.(type) {
+	case *localStat:
+		v, err := m.eval(st.value, s)
+		if err != nil {
+			return err
+		}
+		s.vars[st.name] = v
+	case *assignStat:
+		v, err := m.eval(st.value, s)
+		if err != nil {
+			return err
+		}
+		return m.assign(st, v, s)
+	case *callStat:
+		_, err := m.eval(st.call, s)
+		return err
+	case *ifStat:
+		for i, cond := range st.conds {
+			v, err := m.eval(cond, s)
+			if err != nil {
+				return err
+			}
+			if truthy(v) {
+				return m.block(st.blocks[i], s)
+			}
+		}
+		if st.otherwise != nil {
+			return m.block(st.otherwise, s)
+		}
+	case *forStat:
+		return m.forLoop(st, s)
+	case *whileStat:
+		for {
+			v, err := m.eval(st.cond, s)
+			if err != nil {
+				return err
+			}
+			if !truthy(v) {
+				return nil
+			}
+			if err := m.loopBody(st.body, s); err != nil {
+				if err == errBreak {
+					return nil
+				}
+				return err
+			}
+		}
+	case *breakStat:
+		return errBreak
+	case *returnStat:
+		var v value
+		if st.value != nil {
+			var err error
+			if v, err = m.eval(st.value, s); err != nil {
+				return err
+			}
+		}
+		return &returned{v}
+	}
+	return nil
+}
+
+func (m *machine) loopBody(body []stat, s *scope) error {
+	if err := m.step(); err != nil {
+		return err
+	}
+	return m.block(body, s)
+}
+
+func (m *machine) forLoop(st *forStat, s *scope) error {
+	bounds := [3]float64{}
+	for i, e := range []expr{st.from, st.to, st.step} {
+		v, err := m.eval(e, s)
+		if err != nil {
+			return err
+		}
+		n, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("line %d: the bounds of a for loop must be numbers", st.line)
+		}
+		bounds[i] = n
+	}
+	from, to, step := bounds[0], bounds[1], bounds[2]
+	if step == 0 {
+		return fmt.Errorf("line %d: the step of a for loop must not be 0", st.line)
+	}
+
+	for i := from; (step > 0 && i <= to) || (step < 0 && i >= to); i += step {
+		loop := &scope{vars: map[string]value{st.name: i}, parent: s}
+		if err := m.loopBody(st.body, loop); err != nil {
+			if err == errBreak {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *machine) assign(st *assignStat, v value, s *scope) error {
+	switch target := st.target.(type) {
+	case *nameExpr:
+		if owner, ok := s.lookup(target.name); ok {
+			owner.vars[target.name] = v
+			return nil
+		}
+		if _, ok := m.host[target.name]; ok {
+			return fmt.Errorf("line %d: %s is read-only", st.line, target.name)
+		}
+		m.globals[target.name] = v
+	case *fieldExpr:
+		o, err := m.eval(target.object, s)
+		if err != nil {
+			return err
+		}
+		obj, ok := o.(object)
+		if !ok {
+			return fmt.Errorf("line %d: cannot set field %s of %s", st.line, target.name, typeName(o))
+		}
+		if err := obj.set(target.name, v); err != nil {
+			return fmt.Errorf("line %d: %v", st.line, err)
+		}
+	default:
+		return fmt.Errorf("line %d: cannot assign to an index", st.line)
+	}
+	return nil
+}
+
+func (m *machine) eval(e expr, s *scope) (value, error) {
+	switch e := e.(type) {
+	case *literal:
+		return e.value, nil
+	case *nameExpr:
+		if owner, ok := s.lookup(e.name); ok {
+			return owner.vars[e.name], nil
+		}
+		if v, ok := m.host[e.name]; ok {
+			return v, nil
+		}
+		return m.globals[e.name], nil
+	case *fieldExpr:
+		o, err := m.eval(e.object, s)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := o.(object)
+		if !ok {
+			return nil, fmt.Errorf("line %d: cannot read field %s of %s", e.line, e.name, typeName(o))
+		}
+		v, err := obj.get(e.name)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", e.line, err)
+		}
+		return v, nil
+	case *indexExpr:
+		return m.index(e, s)
+	case *callExpr:
+		fn, err := m.eval(e.fn, s)
+		if err != nil {
+			return nil, err
+		}
+		f, ok := fn.(builtin)
+		if !ok {
+			return nil, fmt.Errorf("line %d: cannot call %s", e.line, typeName(fn))
+		}
+		args := []value{}
+		for _, arg := range e.args {
+			v, err := m.eval(arg, s)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+		}
+		v, err := f(args)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", e.line, err)
+		}
+		return v, nil
+	case *unaryExpr:
+		return m.unary(e, s)
+	case *binaryExpr:
+		return m.binary(e, s)
+	}
+	return nil, fmt.Errorf("unknown expression %T", e)
+}
+
+func (m *machine) index(e *indexExpr, s *scope) (value, error) {
+	o, err := m.eval(e.object, s)
+	if err != nil {
+		return nil, err
+	}
+	i, err := m.eval(e.index, s)
+	if err != nil {
+		return nil, err
+	}
+
+	l, ok := o.(list)
+	if !ok {
+		return nil, fmt.Errorf("line %d: cannot index %s", e.line, typeName(o))
+	}
+	n, ok := i.(float64)
+	if !ok || n != math.Trunc(n) {
+		return nil, fmt.Errorf("line %d: lists are indexed by whole numbers", e.line)
+	}
+	if n < 1 || int(n) > l.length() {
+		return nil, nil
+	}
+	return l.index(int(n))
+}
+
+func (m *machine) unary(e *unaryExpr, s *scope) (value, error) {
+	v, err := m.eval(e.operand, s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "not":
+		return !truthy(v), nil
+	case "-":
+		n, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("line %d: cannot negate %s", e.line, typeName(v))
+		}
+		return -n, nil
+	default:
+		switch v := v.(type) {
+		case string:
+			return float64(len(v)), nil
+		case list:
+			return float64(v.length()), nil
+		}
+		return nil, fmt.Errorf("line %d: cannot get the length of %s", e.line, typeName(v))
+	}
+}
+
+func (m *machine) binary(e *binaryExpr, s *scope) (value, error) {
+	left, err := m.eval(e.left, s)
+	if err != nil {
+		return nil, err
+	}
+
+	// and / or short-circuit and return one of their operands
+	switch e.op {
+	case "and":
+		if !truthy(left) {
+			return left, nil
+		}
+		return m.eval(e.right, s)
+	case "or":
+		if truthy(left) {
+			return left, nil
+		}
+		return m.eval(e.right, s)
+	}
+
+	right, err := m.eval(e.right, s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "==", "~=":
+		_, lfn := left.(builtin)
+		_, rfn := right.(builtin)
+		if lfn || rfn {
+			return nil, fmt.Errorf("line %d: cannot compare functions", e.line)
+		}
+		return (left == right) == (e.op == "=="), nil
+	case "..":
+		l, lok := concatenable(left)
+		r, rok := concatenable(right)
+		if !lok || !rok {
+			return nil, fmt.Errorf("line %d: cannot concatenate %s and %s", e.line, typeName(left), typeName(right))
+		}
+		return l + r, nil
+	}
+
+	if ls, ok := left.(string); ok {
+		if rs, ok := right.(string); ok {
+			switch e.op {
+			case "<":
+				return ls < rs, nil
+			case "<=":
+				return ls <= rs, nil
+			case ">":
+				return ls > rs, nil
+			case ">=":
+				return ls >= rs, nil
+			}
+		}
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("line %d: cannot apply %s to %s and %s", e.line, e.op, typeName(left), typeName(right))
+	}
+
+	switch e.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		return l / r, nil
+	case "%":
+		return l - math.Floor(l/r)*r, nil
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	default:
+		return l >= r, nil
+	}
+}
+
+// truthy follows Lua: only nil and false are false
+func truthy(v value) bool {
+	if v == nil {
+		return false
+	}
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return true
+}
+
+func concatenable(v value) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+func typeName(v value) string {
+	switch v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case builtin:
+		return "a function"
+	case list:
+		return "a list"
+	case object:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,84 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+func run(t *testing.T, src string, globals map[string]value) (value, error) {
+	t.Helper()
+	program, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	m := &machine{host: map[string]value{}, globals: globals}
+	return m.run(program)
+}
+
+func TestMachine_Run(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want value
+	}{
+		{name: "arithmetic precedence", src: "return 1 + 2 * 3 - -4 / 2", want: 9.0},
+		{name: "parentheses and modulo", src: "return (7 + 3) % 4", want: 2.0},
+		{name: "comparisons", src: "return 1 < 2 and 2 <= 2 and 3 > 2 and 3 >= 3 and 1 ~= 2 and 'a' == 'a'", want: true},
+		{name: "and / or return their operands", src: "return nil or false or 'fallback'", want: "fallback"},
+		{name: "not", src: "return not nil", want: true},
+		{name: "concatenation", src: "return 'Critical Strike(' .. 2 .. 'x)'", want: "Critical Strike(2x)"},
+		{name: "length", src: "return #'fire'", want: 4.0},
+		{name: "locals and if", src: "local x = 5\nif x > 10 then return 'big' elseif x > 3 then return 'medium' else return 'small' end", want: "medium"},
+		{name: "numeric for", src: "local sum = 0\nfor i = 1, 10, 2 do sum = sum + i end\nreturn sum", want: 25.0},
+		{name: "while and break", src: "local i = 0\nwhile true do i = i + 1; if i == 3 then break end end\nreturn i", want: 3.0},
+		{name: "locals are scoped to their block", src: "local x = 1\nif true then local x = 2 end\nreturn x", want: 1.0},
+		{name: "comments", src: "-- nothing to see\nreturn 1 -- here either", want: 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := run(t, tt.src, map[string]value{})
+			if err != nil {
+				t.Fatalf("run() returned an unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("run() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachine_Globals(t *testing.T) {
+	globals := map[string]value{}
+	for i, want := range []float64{1, 2, 3} {
+		got, err := run(t, "count = (count or 0) + 1\nreturn count", globals)
+		if err != nil || got != want {
+			t.Errorf("run %d = %v, %v, want %v", i+1, got, err, want)
+		}
+	}
+}
+
+func TestMachine_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "syntax error", src: "if true return 1 end", want: `line 1: expected "then"`},
+		{name: "unfinished string", src: "return 'oops", want: "unfinished string"},
+		{name: "unknown character", src: "return 1 @ 2", want: "unexpected character"},
+		{name: "arithmetic on strings", src: "return 'a' + 1", want: "line 1: cannot apply + to a string and a number"},
+		{name: "calling nil", src: "\nexplode()", want: "line 2: cannot call nil"},
+		{name: "reading fields of nil", src: "return nothing.here", want: "cannot read field here of nil"},
+		{name: "endless loop", src: "while true do end", want: "exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, tt.src, map[string]value{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("run() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
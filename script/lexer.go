@@ -0,0 +1,150 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tokenKind represents the kind of a token of the source code
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenNumber
+	tokenString
+	tokenKeyword
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	line int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of script"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+var keywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true,
+	"end": true, "false": true, "for": true, "if": true, "local": true,
+	"nil": true, "not": true, "or": true, "return": true, "then": true,
+	"true": true, "while": true,
+}
+
+// symbols are ordered so that the longest symbols are matched first
+var symbols = []string{
+	"..", "==", "~=", "<=", ">=",
+	"+", "-", "*", "/", "%", "#", "<", ">", "=", "(", ")", "[", "]", ",", ".", ";",
+}
+
+// lex splits the source code into tokens
+func lex(src string) ([]token, error) {
+	tokens := []token{}
+	line := 1
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case isLetter(c):
+			j := i
+			for j < len(src) && (isLetter(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			kind := tokenName
+			if keywords[src[i:j]] {
+				kind = tokenKeyword
+			}
+			tokens = append(tokens, token{kind: kind, text: src[i:j], line: line})
+			i = j
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			num, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", line, src[i:j])
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:j], num: num, line: line})
+			i = j
+		case c == '"' || c == '\'':
+			text, n, err := lexString(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, line: line})
+			i += n
+		default:
+			matched := false
+			for _, s := range symbols {
+				if strings.HasPrefix(src[i:], s) {
+					tokens = append(tokens, token{kind: tokenSymbol, text: s, line: line})
+					i += len(s)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("line %d: unexpected character %q", line, c)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, line: line}), nil
+}
+
+// lexString reads a quoted string and returns its
+// value and the number of bytes it took in the source
+func lexString(src string) (string, int, error) {
+	quote := src[0]
+	var sb strings.Builder
+	for i := 1; i < len(src); i++ {
+		switch c := src[i]; c {
+		case quote:
+			return sb.String(), i + 1, nil
+		case '\n':
+			return "", 0, fmt.Errorf("unfinished string")
+		case '\\':
+			i++
+			if i == len(src) {
+				return "", 0, fmt.Errorf("unfinished string")
+			}
+			switch src[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(src[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unfinished string")
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,435 @@
+package script
+
+import "fmt"
+
+// the nodes of the syntax tree
+type (
+	expr interface{}
+	stat interface{}
+
+	literal  struct{ value value }
+	nameExpr struct {
+		name string
+		line int
+	}
+	fieldExpr struct {
+		object expr
+		name   string
+		line   int
+	}
+	indexExpr struct {
+		object, index expr
+		line          int
+	}
+	callExpr struct {
+		fn   expr
+		args []expr
+		line int
+	}
+	unaryExpr struct {
+		op      string
+		operand expr
+		line    int
+	}
+	binaryExpr struct {
+		op          string
+		left, right expr
+		line        int
+	}
+
+	localStat struct {
+		name  string
+		value expr
+	}
+	assignStat struct {
+		target, value expr
+		line          int
+	}
+	callStat struct{ call *callExpr }
+	ifStat   struct {
+		conds  []expr
+		blocks [][]stat
+		// otherwise is the else block, nil when missing
+		otherwise []stat
+	}
+	forStat struct {
+		name           string
+		from, to, step expr
+		body           []stat
+		line           int
+	}
+	whileStat struct {
+		cond expr
+		body []stat
+	}
+	breakStat  struct{}
+	returnStat struct{ value expr }
+)
+
+// parser builds the syntax tree of a script
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse compiles the source code into the statements of the script
+func parse(src string) ([]stat, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	block, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return block, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is checks whether the next token is the given keyword or symbol
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokenKeyword || t.kind == tokenSymbol) && t.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if t := p.next(); (t.kind != tokenKeyword && t.kind != tokenSymbol) || t.text != text {
+		return p.errorf(t, "expected %q instead of %s", text, t)
+	}
+	return nil
+}
+
+func (p *parser) name() (string, error) {
+	t := p.next()
+	if t.kind != tokenName {
+		return "", p.errorf(t, "expected a name instead of %s", t)
+	}
+	return t.text, nil
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", t.line, fmt.Sprintf(format, args...))
+}
+
+// blockEnd checks whether the current block ends
+func (p *parser) blockEnd() bool {
+	return p.peek().kind == tokenEOF || p.is("end") || p.is("else") || p.is("elseif")
+}
+
+func (p *parser) block() ([]stat, error) {
+	block := []stat{}
+	for !p.blockEnd() {
+		if p.accept(";") {
+			continue
+		}
+		if p.accept("return") {
+			var value expr
+			if !p.blockEnd() && !p.is(";") {
+				var err error
+				if value, err = p.expr(0); err != nil {
+					return nil, err
+				}
+			}
+			p.accept(";")
+			block = append(block, &returnStat{value})
+			if !p.blockEnd() {
+				return nil, p.errorf(p.peek(), "return must be the last statement of a block")
+			}
+			break
+		}
+
+		s, err := p.stat()
+		if err != nil {
+			return nil, err
+		}
+		block = append(block, s)
+	}
+	return block, nil
+}
+
+func (p *parser) stat() (stat, error) {
+	t := p.peek()
+	switch {
+	case p.accept("local"):
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		var value expr = &literal{nil}
+		if p.accept("=") {
+			if value, err = p.expr(0); err != nil {
+				return nil, err
+			}
+		}
+		return &localStat{name, value}, nil
+	case p.accept("if"):
+		return p.ifStat()
+	case p.accept("for"):
+		return p.forStat(t.line)
+	case p.accept("while"):
+		cond, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		body, err := p.doBlock()
+		if err != nil {
+			return nil, err
+		}
+		return &whileStat{cond, body}, nil
+	case p.accept("break"):
+		return &breakStat{}, nil
+	}
+
+	target, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("=") {
+		switch target.(type) {
+		case *nameExpr, *fieldExpr, *indexExpr:
+		default:
+			return nil, p.errorf(t, "cannot assign to this expression")
+		}
+		value, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		return &assignStat{target, value, t.line}, nil
+	}
+	if call, ok := target.(*callExpr); ok {
+		return &callStat{call}, nil
+	}
+	return nil, p.errorf(t, "expected a statement instead of %s", t)
+}
+
+func (p *parser) ifStat() (stat, error) {
+	s := &ifStat{}
+	for {
+		cond, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("then"); err != nil {
+			return nil, err
+		}
+		block, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		s.conds, s.blocks = append(s.conds, cond), append(s.blocks, block)
+
+		if !p.accept("elseif") {
+			break
+		}
+	}
+
+	if p.accept("else") {
+		block, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		s.otherwise = block
+	}
+	return s, p.expect("end")
+}
+
+func (p *parser) forStat(line int) (stat, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	s := &forStat{name: name, step: &literal{1.0}, line: line}
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	if s.from, err = p.expr(0); err != nil {
+		return nil, err
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	if s.to, err = p.expr(0); err != nil {
+		return nil, err
+	}
+	if p.accept(",") {
+		if s.step, err = p.expr(0); err != nil {
+			return nil, err
+		}
+	}
+	s.body, err = p.doBlock()
+	return s, err
+}
+
+// doBlock parses a "do ... end" block
+func (p *parser) doBlock() ([]stat, error) {
+	if err := p.expect("do"); err != nil {
+		return nil, err
+	}
+	block, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	return block, p.expect("end")
+}
+
+// binary operators by precedence; ".." is right associative
+var precedence = map[string]int{
+	"or":  1,
+	"and": 2,
+	"<":   3,
+	">":   3,
+	"<=":  3,
+	">=":  3,
+	"==":  3,
+	"~=":  3,
+	"..":  4,
+	"+":   5,
+	"-":   5,
+	"*":   6,
+	"/":   6,
+	"%":   6,
+}
+
+const unaryPrecedence = 7
+
+// expr parses an expression made of operators binding tighter than limit
+func (p *parser) expr(limit int) (expr, error) {
+	var left expr
+	t := p.peek()
+	if p.is("not") || p.is("-") || p.is("#") {
+		p.next()
+		operand, err := p.expr(unaryPrecedence)
+		if err != nil {
+			return nil, err
+		}
+		left = &unaryExpr{t.text, operand, t.line}
+	} else {
+		var err error
+		if left, err = p.operand(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		t := p.peek()
+		prec, ok := precedence[t.text]
+		if !ok || (t.kind != tokenSymbol && t.kind != tokenKeyword) || prec <= limit {
+			return left, nil
+		}
+		p.next()
+
+		next := prec
+		if t.text == ".." {
+			next = prec - 1
+		}
+		right, err := p.expr(next)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{t.text, left, right, t.line}
+	}
+}
+
+func (p *parser) operand() (expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenNumber:
+		p.next()
+		return &literal{t.num}, nil
+	case t.kind == tokenString:
+		p.next()
+		return &literal{t.text}, nil
+	case p.accept("true"):
+		return &literal{true}, nil
+	case p.accept("false"):
+		return &literal{false}, nil
+	case p.accept("nil"):
+		return &literal{nil}, nil
+	}
+	return p.primary()
+}
+
+// primary parses names and parenthesized expressions
+// followed by field accesses, indexes and calls
+func (p *parser) primary() (expr, error) {
+	var e expr
+	t := p.next()
+	switch {
+	case t.kind == tokenName:
+		e = &nameExpr{t.text, t.line}
+	case t.kind == tokenSymbol && t.text == "(":
+		inner, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		e = inner
+	default:
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+
+	for {
+		t := p.peek()
+		switch {
+		case p.accept("."):
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			e = &fieldExpr{e, name, t.line}
+		case p.accept("["):
+			index, err := p.expr(0)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			e = &indexExpr{e, index, t.line}
+		case p.accept("("):
+			args := []expr{}
+			for !p.is(")") {
+				arg, err := p.expr(0)
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if !p.accept(",") {
+					break
+				}
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			e = &callExpr{e, args, t.line}
+		default:
+			return e, nil
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Package script lets designers write skills in a small subset of Lua:
+// local and global variables, if / elseif / else, numeric for and while
+// loops, arithmetic, comparisons, "and" / "or" / "not", ".." and "#".
+//
+// Every time the skill fires, the script runs with a sandboxed view of
+// the duel: self and opponent (read-only players), attack (whose
+// accuracy and hits can be changed) and the functions rand(), min(a, b),
+// max(a, b), floor(x), add_hit(damage) and used(description). Global
+// variables are kept between the runs of the same player's skill
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+func init() {
+	core.RegisterSkill("Script", func() core.Skill { return &Skill{} })
+}
+
+// Skill is a skill written as a script; scripts read from a file are
+// reloaded, when the file changes, every time the skill is given to a
+// player (new players are summoned for every duel)
+type Skill struct {
+	Name        string
+	Description string
+	// Defensive skills report the used skills
+	// as defensive skills of the attack
+	Defensive bool
+	// Path is the file the script is read from
+	Path string
+
+	// source is the code of the scripts that aren't read from a file
+	source string
+
+	mu      sync.Mutex
+	program []stat
+	modTime time.Time
+	err     error
+}
+
+// Compile creates a skill running the given source code
+func Compile(name, source string) (*Skill, error) {
+	program, err := parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("script %s: %v", name, err)
+	}
+	return &Skill{Name: name, source: source, program: program}, nil
+}
+
+// Open creates a skill running the script read from the file;
+// the skill is named after the file
+func Open(path string) (*Skill, error) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	s := &Skill{Name: name, Path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the script again if the file changed since it was read;
+// the previous version is kept when the new one can't be compiled
+func (s *Skill) Reload() error {
+	if s.Path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.program != nil && info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	source, err := os.ReadFile(s.Path)
+	if err != nil {
+		return err
+	}
+	program, err := parse(string(source))
+	if err != nil {
+		return fmt.Errorf("script %s: %v", s.Path, err)
+	}
+	s.program, s.modTime = program, info.ModTime()
+	return nil
+}
+
+// Err returns the last error of the script, if any
+func (s *Skill) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// GetDescription returns the long description of the skill
+func (s *Skill) GetDescription() string {
+	if s.Description != "" {
+		return s.Description
+	}
+	return s.Name
+}
+
+// GetModifier reloads the script if needed and returns an attack modifier
+// running it; the attack is left as the script left it if it fails.
+// The errors are logged and kept for Err
+func (s *Skill) GetModifier(player *core.Player) core.AttackModifier {
+	if err := s.Reload(); err != nil {
+		s.fail(err)
+	}
+
+	m := &machine{globals: map[string]value{}}
+	return func(attack *core.Attack) *core.Attack {
+		s.mu.Lock()
+		program := s.program
+		s.mu.Unlock()
+
+		m.host = s.host(player, attack)
+		if _, err := m.run(program); err != nil {
+			s.fail(fmt.Errorf("script %s: %v", s.Name, err))
+		}
+		return attack
+	}
+}
+
+// fail keeps the error and logs it, unless it is the same
+// as the last one, so that a broken script is reported once
+// rather than on every attack
+func (s *Skill) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil || s.err.Error() != err.Error() {
+		log.Print(err)
+	}
+	s.err = err
+}
+
+// host returns the values given to the script
+func (s *Skill) host(player *core.Player, attack *core.Attack) map[string]value {
+	var opponent value
+	if o := player.Opponent(); o != nil {
+		opponent = playerView{o}
+	}
+
+	return map[string]value{
+		"self":     playerView{player},
+		"opponent": opponent,
+		"attack":   attackView{attack},
+
+		"rand": builtin(func(args []value) (value, error) {
+			return rand.Float64(), nil
+		}),
+		"min":   numbers2(math.Min),
+		"max":   numbers2(math.Max),
+		"floor": numbers1(math.Floor),
+		"add_hit": builtin(func(args []value) (value, error) {
+			damage, err := number(args, 0)
+			if err != nil {
+				return nil, err
+			}
+			attack.Hits = append(attack.Hits, core.NewHit(damage))
+			return nil, nil
+		}),
+		"used": builtin(func(args []value) (value, error) {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("used expects a description")
+			}
+			description, ok := concatenable(args[0])
+			if !ok {
+				return nil, fmt.Errorf("used expects a description")
+			}
+			if s.Defensive {
+				attack.UsedDefensiveSkills = append(attack.UsedDefensiveSkills, description)
+			} else {
+				attack.UsedOffensiveSkills = append(attack.UsedOffensiveSkills, description)
+			}
+			return nil, nil
+		}),
+	}
+}
+
+// number returns the i-th argument, which must be a number
+func number(args []value, i int) (float64, error) {
+	if i >= len(args) {
+		return 0, fmt.Errorf("missing argument %d", i+1)
+	}
+	n, ok := args[i].(float64)
+	if !ok {
+		return 0, fmt.Errorf("argument %d must be a number", i+1)
+	}
+	return n, nil
+}
+
+func numbers1(f func(float64) float64) builtin {
+	return func(args []value) (value, error) {
+		x, err := number(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return f(x), nil
+	}
+}
+
+func numbers2(f func(float64, float64) float64) builtin {
+	return func(args []value) (value, error) {
+		x, err := number(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		y, err := number(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return f(x, y), nil
+	}
+}
+
+// UnmarshalJSON decodes the configuration of the skill:
+// {"name": ..., "description": ..., "defensive": ..., "path": ...} or,
+// instead of the path, the source code: {"source": ...}
+func (s *Skill) UnmarshalJSON(data []byte) error {
+	var config struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Defensive   bool   `json:"defensive"`
+		Path        string `json:"path"`
+		Source      string `json:"source"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+
+	var skill *Skill
+	var err error
+	if config.Path != "" {
+		skill, err = Open(config.Path)
+	} else {
+		skill, err = Compile(config.Name, config.Source)
+	}
+	if err != nil {
+		return err
+	}
+
+	if config.Name != "" {
+		s.Name = config.Name
+	} else {
+		s.Name = skill.Name
+	}
+	s.Description, s.Defensive = config.Description, config.Defensive
+	s.Path, s.source, s.program, s.modTime = skill.Path, skill.source, skill.program, skill.modTime
+	return nil
+}
+
+// MarshalJSON encodes the configuration of the skill, as read by
+// UnmarshalJSON; the global variables of the scripts aren't encoded
+func (s *Skill) MarshalJSON() ([]byte, error) {
+	config := struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Defensive   bool   `json:"defensive,omitempty"`
+		Path        string `json:"path,omitempty"`
+		Source      string `json:"source,omitempty"`
+	}{Name: s.Name, Description: s.Description, Defensive: s.Defensive, Path: s.Path}
+	if s.Path == "" {
+		config.Source = s.source
+	}
+	return json.Marshal(config)
+}
@@ -0,0 +1,196 @@
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+func TestSkill_GetModifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		defensive bool
+		want      *core.Attack
+	}{
+		{
+			name:   "adds hits like Critical Strike",
+			source: "add_hit(self.strength)\nused('CriticalStrike(2x)')",
+			want: &core.Attack{
+				Hits:                []core.Hit{core.NewHit(50), core.NewHit(50)},
+				UsedOffensiveSkills: []string{"CriticalStrike(2x)"},
+				UsedDefensiveSkills: []string{},
+			},
+		},
+		{
+			name:      "reduces the damage like Resilience",
+			source:    "for i = 1, #attack.hits do attack.hits[i].damage = attack.hits[i].damage / 2 end\nused('Resilience')",
+			defensive: true,
+			want: &core.Attack{
+				Hits:                []core.Hit{core.NewHit(25)},
+				UsedOffensiveSkills: []string{},
+				UsedDefensiveSkills: []string{"Resilience"},
+			},
+		},
+		{
+			name:   "reads the opponent and changes elemental damage",
+			source: "if opponent.defence > 20 then attack.hits[1].fire = 10; attack.hits[1].penetration = 5 end",
+			want: &core.Attack{
+				Hits: []core.Hit{{
+					PotentialDamage:     50,
+					ElementalDamage:     core.Damage{core.Fire: 10},
+					Penetration:         5,
+					UsedOffensiveSkills: []string{},
+					UsedDefensiveSkills: []string{},
+				}},
+				UsedOffensiveSkills: []string{},
+				UsedDefensiveSkills: []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skill, err := Compile("test", tt.source)
+			if err != nil {
+				t.Fatalf("Compile() returned an unexpected error: %v", err)
+			}
+			skill.Defensive = tt.defensive
+
+			player := core.NewPlayer("Scripter", core.PlayerStats{Health: 100, Strength: 50}, core.PlayerSkills{})
+			opponent := core.NewPlayer("Peanut", core.PlayerStats{Health: 100, Defence: 30}, core.PlayerSkills{})
+			dm := &core.DuelMaster{Rounds: 0, PlayerOne: player, PlayerTwo: opponent}
+			dm.StartDuel()
+
+			got := skill.GetModifier(player)(core.NewAttack(50))
+			if err := skill.Err(); err != nil {
+				t.Fatalf("the script failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetModifier() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkill_Sandbox(t *testing.T) {
+	skill, err := Compile("cheat", "self.health = 1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	player := core.NewPlayer("Cheater", core.PlayerStats{Health: 100}, core.PlayerSkills{})
+	skill.GetModifier(player)(core.NewAttack(10))
+	if player.Health != 100 || skill.Err() == nil || !strings.Contains(skill.Err().Error(), "read-only") {
+		t.Errorf("scripts should not change players; got %.2f health and error %v", player.Health, skill.Err())
+	}
+}
+
+func TestSkill_LogsErrors(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	skill, err := Compile("broken", "attack.accuracy = nil + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modifier := skill.GetModifier(core.NewPlayer("Hero", core.PlayerStats{}, core.PlayerSkills{}))
+	for i := 0; i < 3; i++ {
+		modifier(core.NewAttack(10))
+	}
+	if n := strings.Count(out.String(), "script broken:"); n != 1 {
+		t.Errorf("the error was logged %d times, want once; log:\n%s", n, out.String())
+	}
+}
+
+func TestSkill_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rage.lua")
+	if err := os.WriteFile(path, []byte("used('v1')"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	skill, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned an unexpected error: %v", err)
+	}
+	if skill.GetDescription() != "rage" {
+		t.Errorf("GetDescription() = %q, want the file name", skill.GetDescription())
+	}
+
+	player := core.NewPlayer("Hero", core.PlayerStats{}, core.PlayerSkills{})
+	used := func() []string {
+		return skill.GetModifier(player)(core.NewAttack(10)).UsedOffensiveSkills
+	}
+	if got := used(); !reflect.DeepEqual(got, []string{"v1"}) {
+		t.Errorf("used %v, want v1", got)
+	}
+
+	later := time.Now().Add(time.Minute)
+	os.WriteFile(path, []byte("used('v2')"), 0644)
+	os.Chtimes(path, later, later)
+	if got := used(); !reflect.DeepEqual(got, []string{"v2"}) {
+		t.Errorf("used %v after the script changed, want v2", got)
+	}
+
+	os.WriteFile(path, []byte("used('v3'"), 0644)
+	os.Chtimes(path, later.Add(time.Minute), later.Add(time.Minute))
+	if got := used(); !reflect.DeepEqual(got, []string{"v2"}) || skill.Err() == nil {
+		t.Errorf("used %v after a broken change, want v2 to be kept and an error", got)
+	}
+}
+
+func TestSkill_Config(t *testing.T) {
+	config := core.SkillConfig{}
+	data := `{"type": "Script", "params": {"name": "Double Tap", "defensive": false, "source": "add_hit(self.strength)"}}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	skill, err := config.Build()
+	if err != nil {
+		t.Fatalf("Build() returned an unexpected error: %v", err)
+	}
+	player := core.NewPlayer("Hero", core.PlayerStats{Strength: 20}, core.PlayerSkills{})
+	if got := skill.GetModifier(player)(core.NewAttack(20)); skill.GetDescription() != "Double Tap" || len(got.Hits) != 2 {
+		t.Errorf("Build() = %q with %d hits, want Double Tap with 2 hits", skill.GetDescription(), len(got.Hits))
+	}
+
+	config.Params = json.RawMessage(`{"source": "add_hit("}`)
+	if _, err := config.Build(); err == nil {
+		t.Errorf("Build() should return an error for invalid scripts")
+	}
+}
+
+func TestSkill_MarshalJSON(t *testing.T) {
+	skill, err := Compile("Double Tap", "add_hit(self.strength)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	skill.Defensive = true
+
+	config, err := core.NewSkillConfig(skill)
+	if err != nil {
+		t.Fatalf("NewSkillConfig() returned an unexpected error: %v", err)
+	}
+	if want := `{"name":"Double Tap","defensive":true,"source":"add_hit(self.strength)"}`; config.Type != "Script" || string(config.Params) != want {
+		t.Errorf("NewSkillConfig() = %s %s, want Script %s", config.Type, config.Params, want)
+	}
+
+	built, err := config.Build()
+	if err != nil {
+		t.Fatalf("Build() returned an unexpected error: %v", err)
+	}
+	player := core.NewPlayer("Hero", core.PlayerStats{Strength: 20}, core.PlayerSkills{})
+	if got := built.GetModifier(player)(core.NewAttack(20)); !built.(*Skill).Defensive || len(got.Hits) != 2 {
+		t.Errorf("the decoded skill made %d hits, want 2", len(got.Hits))
+	}
+}
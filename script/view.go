@@ -0,0 +1,167 @@
+package script
+
+import (
+	"fmt"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// playerView is the read-only view of a player given to scripts
+type playerView struct {
+	p *core.Player
+}
+
+func (pv playerView) get(field string) (value, error) {
+	p := pv.p
+	switch field {
+	case "name":
+		return p.Name, nil
+	case "level":
+		return float64(p.Level), nil
+	case "health":
+		return p.Health, nil
+	case "starting_health":
+		return p.StartingHealth(), nil
+	case "strength":
+		return p.Strength, nil
+	case "defence":
+		return p.EffectiveDefence(), nil
+	case "speed":
+		return p.Speed, nil
+	case "luck":
+		return p.Luck, nil
+	case "accuracy":
+		return p.Accuracy, nil
+	case "evasion":
+		return p.Evasion, nil
+	case "crit_chance":
+		return p.CritChance, nil
+	case "round":
+		return float64(p.Round()), nil
+	case "mana":
+		return p.Resource(core.Mana), nil
+	case "stamina":
+		return p.Resource(core.Stamina), nil
+	case "rage":
+		return p.Resource(core.Rage), nil
+	}
+	return nil, fmt.Errorf("players have no field %s", field)
+}
+
+func (pv playerView) set(field string, v value) error {
+	return fmt.Errorf("players are read-only")
+}
+
+// attackView lets scripts change the attack going through the pipe
+type attackView struct {
+	a *core.Attack
+}
+
+func (av attackView) get(field string) (value, error) {
+	switch field {
+	case "accuracy":
+		return av.a.Accuracy, nil
+	case "hits":
+		return hitsView(av), nil
+	}
+	return nil, fmt.Errorf("attacks have no field %s", field)
+}
+
+func (av attackView) set(field string, v value) error {
+	if field != "accuracy" {
+		return fmt.Errorf("the %s of attacks can't be changed", field)
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return fmt.Errorf("accuracy must be a number")
+	}
+	av.a.Accuracy = n
+	return nil
+}
+
+// hitsView is the list of hits of an attack
+type hitsView attackView
+
+func (hv hitsView) index(i int) (value, error) {
+	return hitView{a: hv.a, i: i - 1}, nil
+}
+
+func (hv hitsView) length() int {
+	return len(hv.a.Hits)
+}
+
+// hitView refers to the hit by index since
+// the hits move when hits are added
+type hitView struct {
+	a *core.Attack
+	i int
+}
+
+// elements maps the fields of hits to elemental damage types;
+// "true" is a keyword, so true damage is named true_damage
+var elements = map[string]core.DamageType{
+	"fire":        core.Fire,
+	"frost":       core.Frost,
+	"poison":      core.Poison,
+	"true_damage": core.True,
+}
+
+func (hv hitView) hit() (*core.Hit, error) {
+	if hv.i < 0 || hv.i >= len(hv.a.Hits) {
+		return nil, fmt.Errorf("the hit was removed")
+	}
+	return &hv.a.Hits[hv.i], nil
+}
+
+func (hv hitView) get(field string) (value, error) {
+	h, err := hv.hit()
+	if err != nil {
+		return nil, err
+	}
+
+	switch field {
+	case "damage":
+		return h.PotentialDamage, nil
+	case "total":
+		return h.TotalPotentialDamage(), nil
+	case "penetration":
+		return h.Penetration, nil
+	case "penetration_ratio":
+		return h.PenetrationRatio, nil
+	case "sunder":
+		return h.Sunder, nil
+	}
+	if t, ok := elements[field]; ok {
+		return h.ElementalDamage[t], nil
+	}
+	return nil, fmt.Errorf("hits have no field %s", field)
+}
+
+func (hv hitView) set(field string, v value) error {
+	h, err := hv.hit()
+	if err != nil {
+		return err
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return fmt.Errorf("the %s of hits must be a number", field)
+	}
+
+	switch field {
+	case "damage":
+		h.PotentialDamage = n
+	case "penetration":
+		h.Penetration = n
+	case "penetration_ratio":
+		h.PenetrationRatio = n
+	case "sunder":
+		h.Sunder = n
+	default:
+		t, ok := elements[field]
+		if !ok {
+			return fmt.Errorf("the %s of hits can't be changed", field)
+		}
+		h.AddDamage(t, n-h.ElementalDamage[t])
+	}
+	return nil
+}
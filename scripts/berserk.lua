@@ -0,0 +1,19 @@
+-- Berserk: the lower the hero's health, the harder he hits;
+-- below a third of his health he strikes once more, at most every
+-- other attack (strikes is a global, kept between attacks)
+local missing = 1 - self.health / self.starting_health
+for i = 1, #attack.hits do
+	local hit = attack.hits[i]
+	hit.damage = hit.damage * (1 + missing)
+end
+
+if missing > 0.66 and (strikes or 0) == 0 then
+	add_hit(self.strength)
+	strikes = 1
+	used("Berserk(extra strike)")
+elseif missing > 0.25 then
+	strikes = 0
+	used("Berserk(+" .. floor(missing * 100) .. "% damage)")
+else
+	strikes = 0
+end
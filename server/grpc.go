@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pfzero/battle-simulator/core"
+	battlepb "github.com/pfzero/battle-simulator/proto"
+)
+
+// NewGRPC creates a gRPC server serving the Battle service of
+// proto/battle.proto; its duels and simulations are the server's:
+// they run one at a time with the HTTP ones and are kept with them
+func NewGRPC(s *Server, opts ...grpc.ServerOption) *grpc.Server {
+	g := grpc.NewServer(append([]grpc.ServerOption{grpc.MaxRecvMsgSize(maxBody)}, opts...)...)
+	battlepb.RegisterBattleServer(g, &battle{server: s})
+	return g
+}
+
+// battle implements the Battle service
+type battle struct {
+	battlepb.UnimplementedBattleServer
+	server *Server
+}
+
+// RunDuel fights the duel of the request and streams its events
+func (b *battle) RunDuel(req *battlepb.DuelRequest, srv battlepb.Battle_RunDuelServer) error {
+	spec := duelRequest(req.GetPlayers(), req.GetRounds(), req.GetSeed())
+	one, two, err := b.server.templates(spec)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	out := newGRPCStream(srv)
+	defer out.close()
+	b.server.sendDuel(spec, one, two, out)
+	return nil
+}
+
+// Simulate simulates the duels of the request and streams its progress
+func (b *battle) Simulate(req *battlepb.SimulationRequest, srv battlepb.Battle_SimulateServer) error {
+	spec := SimulationSpec{
+		DuelSpec: duelRequest(req.GetPlayers(), req.GetRounds(), req.GetSeed()),
+		Duels:    int(req.GetDuels()),
+	}
+	one, two, err := b.server.simulationTemplates(spec)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	out := newGRPCStream(srv)
+	defer out.close()
+	b.server.sendSimulation(spec, one, two, out)
+	return nil
+}
+
+// newGRPCStream sends the messages on the stream of the rpc;
+// once the client is gone, the messages are dropped
+func newGRPCStream(srv grpc.ServerStream) *stream {
+	return newStream(func(message interface{}) {
+		srv.SendMsg(message)
+	})
+}
+
+// duelRequest turns the fields of a request into the spec of its duel
+func duelRequest(players []*battlepb.FighterSpec, rounds int32, seed int64) DuelSpec {
+	spec := DuelSpec{Rounds: int(rounds), Seed: SeedSpec(seed)}
+	for _, p := range players {
+		spec.Players = append(spec.Players, fighterRequest(p))
+	}
+	return spec
+}
+
+func fighterRequest(p *battlepb.FighterSpec) FighterSpec {
+	stats := p.GetStats()
+	fs := FighterSpec{
+		Name: p.GetName(),
+		Stats: StatsSpec{
+			Health:         rangeRequest(stats.GetHealth()),
+			Strength:       rangeRequest(stats.GetStrength()),
+			Defence:        rangeRequest(stats.GetDefence()),
+			Speed:          rangeRequest(stats.GetSpeed()),
+			Luck:           rangeRequest(stats.GetLuck()),
+			Accuracy:       rangeRequest(stats.GetAccuracy()),
+			Evasion:        rangeRequest(stats.GetEvasion()),
+			CritChance:     rangeRequest(stats.GetCritChance()),
+			CritMultiplier: rangeRequest(stats.GetCritMultiplier()),
+		},
+		Offensive: skillRequests(p.GetOffensive()),
+		Defensive: skillRequests(p.GetDefensive()),
+	}
+	if len(p.GetResistances()) > 0 {
+		fs.Resistances = core.Resistances{}
+		for damageType, resistance := range p.GetResistances() {
+			fs.Resistances[core.DamageType(damageType)] = resistance
+		}
+	}
+	return fs
+}
+
+func rangeRequest(r *battlepb.StatRange) RangeSpec {
+	return RangeSpec{Min: r.GetMin(), Max: r.GetMax()}
+}
+
+func skillRequests(skills []*battlepb.SkillConfig) []core.SkillConfig {
+	configs := []core.SkillConfig{}
+	for _, skill := range skills {
+		config := core.SkillConfig{Type: skill.GetType()}
+		if skill.GetParams() != "" {
+			config.Params = json.RawMessage(skill.GetParams())
+		}
+		configs = append(configs, config)
+	}
+	return configs
+}
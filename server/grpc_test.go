@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/pfzero/battle-simulator/internal/testutil"
+	battlepb "github.com/pfzero/battle-simulator/proto"
+)
+
+// newTestClient serves the Battle service of a test server in memory
+func newTestClient(t *testing.T) (*Server, battlepb.BattleClient) {
+	t.Helper()
+	s := New(testutil.Template("Hero", 100, 60, 0, 2), testutil.Template("Villain", 100, 10, 50, 1), nil)
+	l := bufconn.Listen(1 << 20)
+	g := NewGRPC(s)
+	go g.Serve(l)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return s, battlepb.NewBattleClient(conn)
+}
+
+func TestGRPC_RunDuel(t *testing.T) {
+	s, client := newTestClient(t)
+
+	stream, err := client.RunDuel(context.Background(), &battlepb.DuelRequest{
+		Players: []*battlepb.FighterSpec{
+			{
+				Name:      "Na'arun",
+				Stats:     &battlepb.StatsSpec{Health: &battlepb.StatRange{Min: 70, Max: 100}, Strength: &battlepb.StatRange{Min: 75, Max: 75}},
+				Offensive: []*battlepb.SkillConfig{{Type: "CriticalStrike", Params: `{"DoubleStrikeChance": 0.1}`}},
+			},
+			{Name: "Peanut", Stats: &battlepb.StatsSpec{Health: &battlepb.StatRange{Min: 80, Max: 80}, Strength: &battlepb.StatRange{Min: 60, Max: 90}}},
+		},
+		Seed: 42,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := []*battlepb.DuelMessage{}
+	for {
+		m, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, m)
+	}
+
+	if len(messages) < 3 || len(messages[0].GetFighters().GetFighters()) != 2 {
+		t.Fatalf("RunDuel() streamed %v, want the fighters first", messages)
+	}
+	result := messages[len(messages)-1].GetResult()
+	saved, err := s.History.Get(1)
+	if result == nil || err != nil || result.Id != 1 || result.Seed != 42 || len(saved.Replay) != len(messages)-2 {
+		t.Fatalf("RunDuel() ended with %v; the history has %+v, %v", messages[len(messages)-1], saved, err)
+	}
+	for i, e := range saved.Replay {
+		if messages[i+1].GetEvent().GetType().String() == "TYPE_UNSPECIFIED" || messages[i+1].GetEvent().GetRound() != int32(e.Round) {
+			t.Errorf("streamed event %d = %v, want %+v", i, messages[i+1].GetEvent(), e)
+		}
+	}
+}
+
+func TestGRPC_Simulate(t *testing.T) {
+	_, client := newTestClient(t)
+
+	stream, err := client.Simulate(context.Background(), &battlepb.SimulationRequest{Duels: 40, Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress := 0
+	var result *battlepb.SimulationResult
+	for {
+		m, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.GetProgress() != nil {
+			progress++
+		}
+		if m.GetResult() != nil {
+			result = m.GetResult()
+		}
+	}
+	// the hero knocks the villain out in every duel
+	if progress != 20 || result.GetStats().GetPlayerOneWins() != 40 || result.GetSeed() != 7 {
+		t.Errorf("Simulate() streamed %d progress messages and the result %v", progress, result)
+	}
+}
+
+func TestGRPC_InvalidRequests(t *testing.T) {
+	_, client := newTestClient(t)
+
+	scripted := &battlepb.FighterSpec{
+		Name:      "Scripter",
+		Stats:     &battlepb.StatsSpec{Health: &battlepb.StatRange{Min: 1, Max: 1}},
+		Offensive: []*battlepb.SkillConfig{{Type: "Script", Params: `{"path": "/etc/passwd"}`}},
+	}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"too many rounds", func() error {
+			stream, err := client.RunDuel(context.Background(), &battlepb.DuelRequest{Rounds: 5000})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}},
+		{"scripted skill", func() error {
+			stream, err := client.RunDuel(context.Background(), &battlepb.DuelRequest{Players: []*battlepb.FighterSpec{scripted, {Name: "B", Stats: scripted.Stats}}})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}},
+		{"no duels", func() error {
+			stream, err := client.Simulate(context.Background(), &battlepb.SimulationRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("the call returned %v, want %s", err, codes.InvalidArgument)
+			}
+		})
+	}
+}
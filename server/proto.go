@@ -0,0 +1,100 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pfzero/battle-simulator/history"
+	battlepb "github.com/pfzero/battle-simulator/proto"
+	"github.com/pfzero/battle-simulator/simulation"
+)
+
+// The functions of this file build the messages of the Battle service of
+// proto/battle.proto, sent by the gRPC service and, written in the
+// canonical JSON mapping of protocol buffers (protojson), by the HTTP streams
+
+func newFighters(fighters []history.Fighter) *battlepb.Fighters {
+	return &battlepb.Fighters{Fighters: newFighterList(fighters)}
+}
+
+func newFighterList(fighters []history.Fighter) []*battlepb.Fighter {
+	list := []*battlepb.Fighter{}
+	for _, f := range fighters {
+		s := f.Stats
+		list = append(list, &battlepb.Fighter{
+			Name:  f.Name,
+			Level: int32(f.Level),
+			Stats: &battlepb.PlayerStats{
+				Health:         s.Health,
+				Strength:       s.Strength,
+				Defence:        s.Defence,
+				Speed:          s.Speed,
+				Luck:           s.Luck,
+				Accuracy:       s.Accuracy,
+				Evasion:        s.Evasion,
+				CritChance:     s.CritChance,
+				CritMultiplier: s.CritMultiplier,
+			},
+			Offensive: f.Offensive,
+			Defensive: f.Defensive,
+			Equipment: f.Equipment,
+		})
+	}
+	return list
+}
+
+func newEvent(e history.Event) *battlepb.Event {
+	event := &battlepb.Event{
+		Round:    int32(e.Round),
+		Type:     battlepb.Event_Type(battlepb.Event_Type_value[strings.ToUpper(string(e.Type))]),
+		Player:   e.Player,
+		Opponent: e.Opponent,
+		Action:   e.Action,
+		Skills:   e.Skills,
+		Health:   e.Health,
+	}
+	for _, hit := range e.Hits {
+		event.Hits = append(event.Hits, &battlepb.Hit{Damage: hit.Damage, Evaded: hit.Evaded, Skills: hit.Skills})
+	}
+	return event
+}
+
+func newDuelResult(r *history.Record) *battlepb.DuelResult {
+	return &battlepb.DuelResult{
+		Id:       r.ID,
+		Time:     r.Time.Format(time.RFC3339Nano),
+		Rounds:   int32(r.Rounds),
+		Knockout: r.Knockout,
+		Fled:     r.Fled,
+		Winner:   r.Winner,
+		Loser:    r.Loser,
+		Seed:     r.Seed,
+		Fighters: newFighterList(r.Fighters),
+	}
+}
+
+func newSimulationStats(s simulation.Stats) *battlepb.SimulationStats {
+	return &battlepb.SimulationStats{
+		Duels:         int32(s.Duels),
+		PlayerOneWins: int32(s.PlayerOneWins),
+		PlayerTwoWins: int32(s.PlayerTwoWins),
+		Ties:          int32(s.Ties),
+		TotalRounds:   int32(s.TotalRounds),
+	}
+}
+
+func newProgress(stats simulation.Stats, total int) *battlepb.Progress {
+	return &battlepb.Progress{Done: int32(stats.Duels), Total: int32(total), Stats: newSimulationStats(stats)}
+}
+
+func newSimulationResult(s Simulation) *battlepb.SimulationResult {
+	return &battlepb.SimulationResult{
+		Id:        s.ID,
+		Time:      s.Time.Format(time.RFC3339Nano),
+		PlayerOne: s.PlayerOne,
+		PlayerTwo: s.PlayerTwo,
+		Rounds:    int32(s.Rounds),
+		Seed:      s.Seed,
+		Stats:     newSimulationStats(s.Stats),
+	}
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// protoField is a field of a message of proto/battle.proto
+type protoField struct {
+	kind     string
+	repeated bool
+	optional bool
+	// oneof is the oneof the field belongs to, if any
+	oneof string
+}
+
+// protoFile holds the messages, by name, with their fields by JSON
+// name, and the enums, with their values, of a proto file
+type protoFile struct {
+	messages map[string]map[string]protoField
+	enums    map[string][]string
+}
+
+var (
+	protoBlock = regexp.MustCompile(`^(message|enum|oneof|service)\s+(\w+)\s*\{$`)
+	protoEntry = regexp.MustCompile(`^(optional\s+|repeated\s+)?(map<\w+,\s*\w+>|\w+)\s+(\w+)\s*=\s*\d+;$`)
+	protoValue = regexp.MustCompile(`^(\w+)\s*=\s*\d+;$`)
+)
+
+// readProto reads the messages and enums of the proto file; it only
+// supports what battle.proto uses
+func readProto(t *testing.T, path string) protoFile {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	pf := protoFile{messages: map[string]map[string]protoField{}, enums: map[string][]string{}}
+	type block struct{ kind, name string }
+	blocks := []block{}
+	message := func() string {
+		for i := len(blocks) - 1; i >= 0; i-- {
+			if blocks[i].kind == "message" {
+				return blocks[i].name
+			}
+		}
+		return ""
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if m := protoBlock.FindStringSubmatch(line); m != nil {
+			blocks = append(blocks, block{m[1], m[2]})
+			if m[1] == "message" {
+				pf.messages[m[2]] = map[string]protoField{}
+			}
+			continue
+		}
+		if line == "}" {
+			blocks = blocks[:len(blocks)-1]
+			continue
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		switch current := blocks[len(blocks)-1]; current.kind {
+		case "enum":
+			if m := protoValue.FindStringSubmatch(line); m != nil {
+				pf.enums[current.name] = append(pf.enums[current.name], m[1])
+			}
+		case "message", "oneof":
+			if m := protoEntry.FindStringSubmatch(line); m != nil {
+				field := protoField{kind: m[2], repeated: strings.HasPrefix(m[1], "repeated"), optional: strings.HasPrefix(m[1], "optional")}
+				if current.kind == "oneof" {
+					field.oneof = current.name
+				}
+				pf.messages[message()][jsonName(m[3])] = field
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return pf
+}
+
+// jsonName is the lowerCamelCase name of the field in protojson
+func jsonName(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// check checks that the JSON value is the canonical protojson form of
+// the message: known fields only, of the right JSON type, with 64-bit
+// integers as strings, enums by name and no default values
+func (pf protoFile) check(path, message string, v interface{}) []string {
+	fields, ok := pf.messages[message]
+	if !ok {
+		return []string{fmt.Sprintf("%s: unknown message %s", path, message)}
+	}
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s: %s isn't an object", path, message)}
+	}
+
+	errs := []string{}
+	oneofs := map[string]int{}
+	for name, value := range object {
+		field, ok := fields[name]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s.%s: %s has no such field", path, name, message))
+			continue
+		}
+		if field.oneof != "" {
+			oneofs[field.oneof]++
+		}
+		if !field.repeated {
+			errs = append(errs, pf.checkValue(path+"."+name, field, value)...)
+			continue
+		}
+		list, ok := value.([]interface{})
+		if !ok || len(list) == 0 {
+			errs = append(errs, fmt.Sprintf("%s.%s: repeated fields are non-empty lists", path, name))
+			continue
+		}
+		for i, item := range list {
+			errs = append(errs, pf.checkValue(fmt.Sprintf("%s.%s[%d]", path, name, i), field, item)...)
+		}
+	}
+	for oneof, n := range oneofs {
+		if n > 1 {
+			errs = append(errs, fmt.Sprintf("%s: %d fields of the oneof %s are set", path, n, oneof))
+		}
+	}
+	return errs
+}
+
+func (pf protoFile) checkValue(path string, field protoField, v interface{}) []string {
+	invalid := func(format string) []string {
+		return []string{fmt.Sprintf("%s: %v isn't "+format, path, v)}
+	}
+
+	switch field.kind {
+	case "double":
+		n, ok := v.(json.Number)
+		if f, err := n.Float64(); !ok || err != nil || (f == 0 && !field.optional) {
+			return invalid("a non-zero number")
+		}
+	case "int32":
+		n, ok := v.(json.Number)
+		if i, err := n.Int64(); !ok || err != nil || i == 0 {
+			return invalid("a non-zero integer")
+		}
+	case "int64":
+		s, ok := v.(string)
+		if i, err := strconv.ParseInt(s, 10, 64); !ok || err != nil || i == 0 {
+			return invalid("a non-zero integer written as a string")
+		}
+	case "string":
+		if s, ok := v.(string); !ok || s == "" {
+			return invalid("a non-empty string")
+		}
+	case "bool":
+		if b, ok := v.(bool); !ok || !b {
+			return invalid("true")
+		}
+	default:
+		if strings.HasPrefix(field.kind, "map<") {
+			if _, ok := v.(map[string]interface{}); !ok {
+				return invalid("an object")
+			}
+			return nil
+		}
+		if values, ok := pf.enums[field.kind]; ok {
+			for _, value := range values[1:] {
+				if v == value {
+					return nil
+				}
+			}
+			return invalid(fmt.Sprintf("a value of %s", field.kind))
+		}
+		return pf.check(path, field.kind, v)
+	}
+	return nil
+}
+
+// streamJSON reads the messages of a stream as generic JSON values
+func streamJSON(t *testing.T, url, body string) []interface{} {
+	t.Helper()
+	values := []interface{}{}
+	messages(t, url, body, func(d *json.Decoder) error {
+		d.UseNumber()
+		var v interface{}
+		err := d.Decode(&v)
+		values = append(values, v)
+		return err
+	})
+	return values
+}
+
+func TestStreams_Protojson(t *testing.T) {
+	pf := readProto(t, "../proto/battle.proto")
+	if len(pf.messages["Event"]) == 0 || len(pf.enums["Type"]) == 0 {
+		t.Fatalf("the proto file wasn't read: %+v", pf)
+	}
+	// the JSON of the history isn't protojson
+	for _, data := range []string{
+		`{"fighters": [{"name": "Hero"}]}`,
+		`{"event": {"round": 1, "type": "round"}}`,
+		`{"result": {"id": 1, "rounds": 3}}`,
+		`{"result": {"id": "1", "knockout": false}}`,
+		`{"progress": {"done": 2, "stats": {"playerOneWins": 2, "Ties": 0}}}`,
+	} {
+		decoder := json.NewDecoder(strings.NewReader(data))
+		decoder.UseNumber()
+		var v interface{}
+		if err := decoder.Decode(&v); err != nil {
+			t.Fatal(err)
+		}
+		message := "DuelMessage"
+		if strings.Contains(data, "progress") {
+			message = "SimulationMessage"
+		}
+		if errs := pf.check(message, message, v); len(errs) == 0 {
+			t.Errorf("%s was accepted as a %s", data, message)
+		}
+	}
+
+	ts := newTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		message string
+	}{
+		{"duel", "/duels/stream", duelSpec, "DuelMessage"},
+		{"simulation", "/simulations/stream", `{"duels": 40, "seed": "7"}`, "SimulationMessage"},
+		// the request itself written as protojson
+		{"protojson request", "/duels/stream", `{
+			"players": [
+				{
+					"name": "Na'arun",
+					"stats": {"health": {"min": 70, "max": 100}, "strength": {"min": 75, "max": 75}, "evasion": {"max": 0.2}},
+					"offensive": [{"type": "CriticalStrike", "params": "{\"DoubleStrikeChance\": 0.1}"}]
+				},
+				{"name": "Peanut", "stats": {"health": {"min": 80, "max": 80}, "strength": {"min": 60, "max": 90}}}
+			],
+			"rounds": 20,
+			"seed": "42"
+		}`, "DuelMessage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := streamJSON(t, ts.URL+tt.path, tt.body)
+			if len(values) < 2 {
+				t.Fatalf("POST %s streamed %d messages", tt.path, len(values))
+			}
+			for i, v := range values {
+				for _, err := range pf.check(fmt.Sprintf("%s[%d]", tt.message, i), tt.message, v) {
+					t.Error(err)
+				}
+				if object, ok := v.(map[string]interface{}); !ok || len(object) != 1 {
+					t.Errorf("message %d = %v, want exactly one field of the oneof set", i, v)
+				}
+				if object, _ := v.(map[string]interface{}); object["error"] != nil {
+					t.Errorf("POST %s streamed an error: %v", tt.path, object["error"])
+				}
+			}
+		})
+	}
+
+	// the messages streamed are the ones saved
+	var saved struct {
+		Seed int64 `json:"seed"`
+	}
+	if do(t, http.MethodGet, ts.URL+"/duels/2", "", &saved); saved.Seed != 42 {
+		t.Errorf("the duel of the protojson request has the seed %d, want 42", saved.Seed)
+	}
+}
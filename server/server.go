@@ -0,0 +1,320 @@
+// Package server exposes the engine as an HTTP JSON API and as the
+// Battle gRPC service of proto/battle.proto for running duels and
+// simulations, as a long-lived service
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/history"
+	"github.com/pfzero/battle-simulator/metrics"
+	"github.com/pfzero/battle-simulator/simulation"
+)
+
+// maxDuels bounds the duels of a single simulation request
+const maxDuels = 100000
+
+// maxBody bounds the size of the requests
+const maxBody = 1 << 20
+
+// maxSimulations bounds the simulations kept by the server;
+// the oldest ones are forgotten
+const maxSimulations = 1000
+
+// Server runs the requested duels and simulations; every duel is
+// instrumented and the metrics are served on /metrics
+//
+// The routes are:
+//
+//	POST /duels              fights the duel of a DuelSpec and returns its history.Record
+//	GET  /duels/{id}         returns a past duel
+//	POST /simulations        simulates the duels of a SimulationSpec
+//	GET  /simulations/{id}   returns one of the last simulations
+//	POST /duels/stream       streams the events of a duel as they happen
+//	POST /simulations/stream streams the progress of a simulation
+//	GET  /metrics            serves the metrics in the Prometheus text format
+type Server struct {
+	// PlayerOne and PlayerTwo fight when the requests don't describe the players
+	PlayerOne core.PlayerTemplate
+	PlayerTwo core.PlayerTemplate
+
+	Rounds        int
+	DamageFormula core.DamageFormula
+
+	Metrics *metrics.Metrics
+	// History keeps the duels; defaults to a history kept in memory,
+	// which only keeps the last duels
+	History *history.Store
+
+	mux *http.ServeMux
+
+	// running serializes the duels, which all share the random numbers
+	// of math/rand, so that the seeded ones can be replayed; the streams
+	// are written without holding it
+	running sync.Mutex
+
+	// simulations are the last simulations, with increasing IDs
+	// from lastSimulation-len(simulations)+1 to lastSimulation
+	mu             sync.RWMutex
+	simulations    []Simulation
+	lastSimulation int64
+}
+
+// New creates a server with duels of 20 rounds between the 2 templates by default
+func New(one, two core.PlayerTemplate, formula core.DamageFormula) *Server {
+	s := &Server{
+		PlayerOne:     one,
+		PlayerTwo:     two,
+		Rounds:        20,
+		DamageFormula: formula,
+		Metrics:       metrics.New(),
+		History:       history.New(),
+		mux:           http.NewServeMux(),
+	}
+	s.mux.Handle("/metrics", s.Metrics)
+	s.mux.HandleFunc("/duels", s.duel)
+	s.mux.HandleFunc("/duels/", s.getDuel)
+	s.mux.HandleFunc("/duels/stream", s.streamDuel)
+	s.mux.HandleFunc("/simulations", s.simulate)
+	s.mux.HandleFunc("/simulations/", s.getSimulation)
+	s.mux.HandleFunc("/simulations/stream", s.streamSimulation)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Simulation is the result of a simulation request
+type Simulation struct {
+	ID        int64            `json:"id"`
+	Time      time.Time        `json:"time"`
+	PlayerOne string           `json:"playerOne"`
+	PlayerTwo string           `json:"playerTwo"`
+	Rounds    int              `json:"rounds"`
+	Seed      int64            `json:"seed"`
+	Stats     simulation.Stats `json:"stats"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed on %s", r.Method, r.URL.Path))
+	return false
+}
+
+// decode reads the JSON body of the request; an empty body is the zero value
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid request: %v", err)
+	}
+	return nil
+}
+
+// id reads the ID at the end of the path
+func id(r *http.Request, prefix string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, prefix), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid ID %q", strings.TrimPrefix(r.URL.Path, prefix))
+	}
+	return id, nil
+}
+
+func (s *Server) rounds(spec DuelSpec) int {
+	if spec.Rounds == 0 {
+		return s.Rounds
+	}
+	return spec.Rounds
+}
+
+// seed reseeds math/rand with the seed of the spec or with a new one,
+// which is returned; it must be called while running
+func seed(spec DuelSpec) int64 {
+	seed := int64(spec.Seed)
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rand.Seed(seed)
+	return seed
+}
+
+// duelSpec reads and validates the duel spec of the request
+func (s *Server) duelSpec(w http.ResponseWriter, r *http.Request) (DuelSpec, core.PlayerTemplate, core.PlayerTemplate, bool) {
+	var spec DuelSpec
+	if err := decode(w, r, &spec); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return spec, core.PlayerTemplate{}, core.PlayerTemplate{}, false
+	}
+	one, two, err := s.templates(spec)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return spec, core.PlayerTemplate{}, core.PlayerTemplate{}, false
+	}
+	return spec, one, two, true
+}
+
+// fight fights the duel, records it with the recorder and saves it in the history
+func (s *Server) fight(spec DuelSpec, one, two core.PlayerTemplate, recorder *history.Recorder) (*history.Record, error) {
+	s.running.Lock()
+	seed := seed(spec)
+	dm := &core.DuelMaster{
+		Rounds:        s.rounds(spec),
+		PlayerOne:     one.Summon(),
+		PlayerTwo:     two.Summon(),
+		DamageFormula: s.DamageFormula,
+	}
+	result := dm.StartDuel(core.Commentators{s.Metrics.Collector(), recorder})
+	s.running.Unlock()
+
+	record := history.NewRecord(result, recorder)
+	record.Seed = seed
+	return record, s.History.Save(record)
+}
+
+// duel fights the duel of the spec
+func (s *Server) duel(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodPost) {
+		return
+	}
+	spec, one, two, ok := s.duelSpec(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := s.fight(spec, one, two, &history.Recorder{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, record)
+}
+
+func (s *Server) getDuel(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+
+	id, err := id(r, "/duels/")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	record, err := s.History.Get(id)
+	if errors.Is(err, history.ErrNotFound) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("duel %d not found", id))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
+
+// simulationSpec reads and validates the simulation spec of the request
+func (s *Server) simulationSpec(w http.ResponseWriter, r *http.Request) (SimulationSpec, core.PlayerTemplate, core.PlayerTemplate, bool) {
+	var spec SimulationSpec
+	if err := decode(w, r, &spec); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return spec, core.PlayerTemplate{}, core.PlayerTemplate{}, false
+	}
+	one, two, err := s.simulationTemplates(spec)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return spec, core.PlayerTemplate{}, core.PlayerTemplate{}, false
+	}
+	return spec, one, two, true
+}
+
+// run simulates the duels and keeps the result; progress, when set,
+// is called with the stats after every duel
+func (s *Server) run(spec SimulationSpec, one, two core.PlayerTemplate, progress func(simulation.Stats)) Simulation {
+	sim := &simulation.Simulation{
+		PlayerOne:     one,
+		PlayerTwo:     two,
+		Duels:         spec.Duels,
+		Rounds:        s.rounds(spec.DuelSpec),
+		DamageFormula: s.DamageFormula,
+		Commentator:   s.Metrics.Collector(),
+		Progress:      progress,
+	}
+	s.running.Lock()
+	seed := seed(spec.DuelSpec)
+	stats := sim.Run()
+	s.running.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSimulation++
+	result := Simulation{
+		ID:        s.lastSimulation,
+		Time:      time.Now().UTC(),
+		PlayerOne: one.Name,
+		PlayerTwo: two.Name,
+		Rounds:    sim.Rounds,
+		Seed:      seed,
+		Stats:     stats,
+	}
+	s.simulations = append(s.simulations, result)
+	if len(s.simulations) > maxSimulations {
+		s.simulations = s.simulations[1:]
+	}
+	return result
+}
+
+// simulate runs the duels of the spec
+func (s *Server) simulate(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodPost) {
+		return
+	}
+	spec, one, two, ok := s.simulationSpec(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.run(spec, one, two, nil))
+}
+
+func (s *Server) getSimulation(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+
+	id, err := id(r, "/simulations/")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := int64(len(s.simulations)) - 1 - (s.lastSimulation - id)
+	if i < 0 || i >= int64(len(s.simulations)) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("simulation %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.simulations[i])
+}
@@ -0,0 +1,385 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pfzero/battle-simulator/history"
+	"github.com/pfzero/battle-simulator/internal/testutil"
+	battlepb "github.com/pfzero/battle-simulator/proto"
+	_ "github.com/pfzero/battle-simulator/script"
+	"github.com/pfzero/battle-simulator/simulation"
+)
+
+func newTestServer() *httptest.Server {
+	// the hero knocks the villain out on round 10
+	return httptest.NewServer(New(testutil.Template("Hero", 100, 60, 0, 2), testutil.Template("Villain", 100, 10, 50, 1), nil))
+}
+
+// do sends the request and decodes the response into v
+func do(t *testing.T, method, url, body string, v interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if v != nil {
+		if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s returned invalid JSON: %v", method, url, err)
+		}
+	}
+	return res.StatusCode
+}
+
+const duelSpec = `{
+	"players": [
+		{
+			"name": "Na'arun",
+			"stats": {"health": {"min": 70, "max": 100}, "strength": 75, "defence": 50, "speed": 45, "evasion": 0.2},
+			"offensive": [{"type": "CriticalStrike", "params": {"DoubleStrikeChance": 0.1, "TripleStrikeChance": 0.01}}],
+			"defensive": [{"type": "Resilience", "params": {"Chance": 0.2, "DamageReduction": 0.5}}]
+		},
+		{
+			"name": "Peanut",
+			"stats": {"health": 80, "strength": {"min": 60, "max": 90}, "defence": 50, "speed": 50, "evasion": 0.3}
+		}
+	],
+	"rounds": 20,
+	"seed": 42
+}`
+
+func TestServer_Duel(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	var record history.Record
+	if status := do(t, http.MethodPost, ts.URL+"/duels", "", &record); status != http.StatusCreated {
+		t.Fatalf("POST /duels = %d, want %d", status, http.StatusCreated)
+	}
+	if record.ID != 1 || record.Winner != "Hero" || record.Rounds != 10 || !record.Knockout || record.Seed == 0 {
+		t.Errorf("POST /duels = %s, want the server's hero to win on round 10", &record)
+	}
+	if last := record.Replay[len(record.Replay)-1]; last.Type != history.KnockoutEvent || last.Player != "Hero" {
+		t.Errorf("POST /duels replay ends with %+v, want the knockout", last)
+	}
+
+	// the same seed fights the same duel
+	var first, second history.Record
+	do(t, http.MethodPost, ts.URL+"/duels", duelSpec, &first)
+	do(t, http.MethodPost, ts.URL+"/duels", duelSpec, &second)
+	if first.ID != 2 || second.ID != 3 || first.Seed != 42 || !reflect.DeepEqual(first.Replay, second.Replay) {
+		t.Errorf("POST /duels with a seed fought different duels:\n%+v\n%+v", first, second)
+	}
+	if first.Fighters[0].Name != "Na'arun" && first.Fighters[1].Name != "Na'arun" {
+		t.Errorf("POST /duels fighters = %+v, want the requested players", first.Fighters)
+	}
+
+	var got history.Record
+	if status := do(t, http.MethodGet, ts.URL+"/duels/2", "", &got); status != http.StatusOK || got.ID != 2 || !reflect.DeepEqual(got.Replay, first.Replay) {
+		t.Errorf("GET /duels/2 = %d %s, want the second duel", status, &got)
+	}
+	if status := do(t, http.MethodGet, ts.URL+"/duels/9", "", nil); status != http.StatusNotFound {
+		t.Errorf("GET /duels/9 = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestServer_HistoryError(t *testing.T) {
+	s := New(testutil.Template("Hero", 100, 60, 0, 2), testutil.Template("Villain", 100, 10, 50, 1), nil)
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.History = store
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	do(t, http.MethodPost, ts.URL+"/duels", "", nil)
+	store.Close()
+	if status := do(t, http.MethodGet, ts.URL+"/duels/1", "", nil); status != http.StatusInternalServerError {
+		t.Errorf("GET /duels/1 = %d, want %d", status, http.StatusInternalServerError)
+	}
+}
+
+func TestServer_Simulate(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	var got Simulation
+	if status := do(t, http.MethodPost, ts.URL+"/simulations", `{"duels": 5}`, &got); status != http.StatusCreated {
+		t.Fatalf("POST /simulations = %d, want %d", status, http.StatusCreated)
+	}
+	want := simulation.Stats{Duels: 5, PlayerOneWins: 5, TotalRounds: 50}
+	if got.ID != 1 || got.PlayerOne != "Hero" || got.Stats != want {
+		t.Errorf("POST /simulations = %+v, want %+v", got, want)
+	}
+
+	var seeded [2]Simulation
+	for i := range seeded {
+		do(t, http.MethodPost, ts.URL+"/simulations", `{"duels": 200, "seed": 7, "players": `+duelSpec[strings.Index(duelSpec, "["):strings.Index(duelSpec, `"rounds"`)]+`"rounds": 10}`, &seeded[i])
+	}
+	if seeded[0].Stats.Duels != 200 || seeded[0].Stats != seeded[1].Stats || seeded[0].Rounds != 10 {
+		t.Errorf("POST /simulations with a seed = %+v and %+v, want the same stats", seeded[0].Stats, seeded[1].Stats)
+	}
+
+	var past Simulation
+	if status := do(t, http.MethodGet, ts.URL+"/simulations/2", "", &past); status != http.StatusOK || past != seeded[0] {
+		t.Errorf("GET /simulations/2 = %d %+v, want %+v", status, past, seeded[0])
+	}
+	if status := do(t, http.MethodGet, ts.URL+"/simulations/4", "", nil); status != http.StatusNotFound {
+		t.Errorf("GET /simulations/4 = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestServer_ForgetsSimulations(t *testing.T) {
+	s := New(testutil.Template("Hero", 100, 60, 0, 2), testutil.Template("Villain", 100, 10, 50, 1), nil)
+	for i := 0; i <= maxSimulations; i++ {
+		s.run(SimulationSpec{Duels: 1}, s.PlayerOne, s.PlayerTwo, nil)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	if status := do(t, http.MethodGet, ts.URL+"/simulations/1", "", nil); status != http.StatusNotFound {
+		t.Errorf("GET /simulations/1 = %d, want the oldest simulation to be forgotten", status)
+	}
+	var last Simulation
+	if status := do(t, http.MethodGet, ts.URL+fmt.Sprintf("/simulations/%d", maxSimulations+1), "", &last); status != http.StatusOK || last.ID != maxSimulations+1 {
+		t.Errorf("GET /simulations/%d = %d %+v, want the last simulation", maxSimulations+1, status, last)
+	}
+}
+
+func TestServer_InvalidRequests(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		body      string
+		want      int
+		wantError string
+	}{
+		{name: "wrong method", method: http.MethodGet, path: "/duels", want: http.StatusMethodNotAllowed, wantError: "GET is not allowed"},
+		{name: "malformed JSON", method: http.MethodPost, path: "/duels", body: "{", want: http.StatusBadRequest, wantError: "invalid request"},
+		{name: "unknown field", method: http.MethodPost, path: "/duels", body: `{"round": 3}`, want: http.StatusBadRequest, wantError: `unknown field "round"`},
+		{name: "one player", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "needs 2 players"},
+		{name: "no name", method: http.MethodPost, path: "/duels", body: `{"players": [{"stats": {"health": 1}}, {"name": "B", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "players[0]: the fighter has no name"},
+		{name: "no health", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": 1}}, {"name": "B"}]}`, want: http.StatusBadRequest, wantError: "players[1]: B: the health must be above 0"},
+		{name: "reversed range", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": {"min": 9, "max": 1}}}, {"name": "B", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "health range [9, 1] is reversed"},
+		{name: "out of domain", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": 1, "luck": 5}, "defensive": [{"type": "Resilience", "params": {"Chance": 0.2, "DamageReduction": 1.5}}]}, {"name": "B", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "players[0]: A: Stats.Luck is 5, outside [0, 1]; Skills.DefensiveSkills[0].DamageReduction is 1.5, outside [0, 1]"},
+		{name: "invalid stat", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": "a lot"}}]}`, want: http.StatusBadRequest, wantError: "a stat must be a number or a range"},
+		{name: "unknown skill", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": 1}, "offensive": [{"type": "Fireball"}]}, {"name": "B", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "unknown skill type \"Fireball\""},
+		{name: "script", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": 1}, "offensive": [{"type": "Costly", "params": {"skill": {"type": "Script", "params": {"path": "/etc/passwd"}}}}]}, {"name": "B", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "Script skills can't be used"},
+		{name: "script with another case", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": 1}, "offensive": [{"type": "Triggered", "params": {"skill": {"Type": "Script", "params": {"path": "/etc/passwd"}}}}]}, {"name": "B", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "Script skills can't be used"},
+		{name: "script written as protojson", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": 1}, "defensive": [{"TYPE": "Script", "params": "{\"path\": \"/etc/passwd\"}"}]}, {"name": "B", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "Script skills can't be used"},
+		{name: "same names", method: http.MethodPost, path: "/duels", body: `{"players": [{"name": "A", "stats": {"health": 1}}, {"name": "A", "stats": {"health": 1}}]}`, want: http.StatusBadRequest, wantError: "different names"},
+		{name: "too many rounds", method: http.MethodPost, path: "/duels", body: `{"rounds": 5000}`, want: http.StatusBadRequest, wantError: "rounds must be within"},
+		{name: "no duels", method: http.MethodPost, path: "/simulations", body: `{}`, want: http.StatusBadRequest, wantError: "duels must be within"},
+		{name: "invalid ID", method: http.MethodGet, path: "/duels/abc", want: http.StatusBadRequest, wantError: "invalid ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				Error string `json:"error"`
+			}
+			if status := do(t, tt.method, ts.URL+tt.path, tt.body, &got); status != tt.want || !strings.Contains(got.Error, tt.wantError) {
+				t.Errorf("%s %s = %d %q, want %d %q", tt.method, tt.path, status, got.Error, tt.want, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	for i := 0; i < 2; i++ {
+		do(t, http.MethodPost, ts.URL+"/duels", "", nil)
+	}
+
+	res, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("GET /metrics has content type %q", res.Header.Get("Content-Type"))
+	}
+	for _, line := range []string{`battle_duels_total{outcome="knockout"} 2`, "battle_rounds_total 20", "battle_hits_total 38"} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("GET /metrics is missing %q:\n%s", line, body)
+		}
+	}
+}
+
+// messages reads the newline delimited JSON messages of a stream
+func messages(t *testing.T, url, body string, v func(*json.Decoder) error) int {
+	t.Helper()
+	res, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); res.StatusCode == http.StatusOK && ct != "application/x-ndjson" {
+		t.Errorf("POST %s has content type %q", url, ct)
+	}
+
+	decoder := json.NewDecoder(res.Body)
+	count := 0
+	for decoder.More() {
+		if err := v(decoder); err != nil {
+			t.Fatalf("POST %s streamed invalid JSON: %v", url, err)
+		}
+		count++
+	}
+	return count
+}
+
+// decodeProto decodes the next protojson message of the stream
+func decodeProto(d *json.Decoder, m proto.Message) error {
+	var data json.RawMessage
+	if err := d.Decode(&data); err != nil {
+		return err
+	}
+	return protojson.Unmarshal(data, m)
+}
+
+func TestServer_StreamDuel(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	var stream []*battlepb.DuelMessage
+	messages(t, ts.URL+"/duels/stream", duelSpec, func(d *json.Decoder) error {
+		m := &battlepb.DuelMessage{}
+		err := decodeProto(d, m)
+		stream = append(stream, m)
+		return err
+	})
+	if len(stream) < 3 || len(stream[0].GetFighters().GetFighters()) != 2 || stream[len(stream)-1].GetResult() == nil {
+		t.Fatalf("POST /duels/stream = %v, want the fighters, the events and the result", stream)
+	}
+
+	// the streamed events are the replay of the saved duel
+	result := stream[len(stream)-1].GetResult()
+	var saved history.Record
+	do(t, http.MethodGet, ts.URL+"/duels/1", "", &saved)
+	if saved.ID != result.Id || saved.Seed != 42 || len(saved.Replay) != len(stream)-2 {
+		t.Fatalf("GET /duels/1 = %+v, want the %d streamed events", saved, len(stream)-2)
+	}
+	for i, e := range saved.Replay {
+		if !proto.Equal(stream[i+1].GetEvent(), newEvent(e)) {
+			t.Errorf("streamed event %d = %v, want %+v", i, stream[i+1].GetEvent(), e)
+		}
+	}
+
+	var got struct {
+		Error string `json:"error"`
+	}
+	if status := do(t, http.MethodPost, ts.URL+"/duels/stream", `{"rounds": -1}`, &got); status != http.StatusBadRequest || got.Error == "" {
+		t.Errorf("POST /duels/stream with an invalid spec = %d %q", status, got.Error)
+	}
+}
+
+func TestServer_StreamSimulation(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	var progress []*battlepb.Progress
+	var result *battlepb.SimulationResult
+	count := messages(t, ts.URL+"/simulations/stream", `{"duels": 40}`, func(d *json.Decoder) error {
+		m := &battlepb.SimulationMessage{}
+		err := decodeProto(d, m)
+		if m.GetProgress() != nil {
+			progress = append(progress, m.GetProgress())
+		}
+		if m.GetResult() != nil {
+			result = m.GetResult()
+		}
+		return err
+	})
+
+	if count != 21 || len(progress) != 20 || result == nil {
+		t.Fatalf("POST /simulations/stream streamed %d messages, want 20 progress updates and the result", count)
+	}
+	for i, p := range progress {
+		if p.Done != int32(2*(i+1)) || p.Total != 40 || p.Stats.PlayerOneWins != p.Done {
+			t.Errorf("progress %d = %v", i, p)
+		}
+	}
+	if result.Id != 1 || !proto.Equal(result.Stats, progress[19].Stats) {
+		t.Errorf("POST /simulations/stream result = %v, want the last progress", result)
+	}
+}
+
+// stalledWriter is a streaming client that stops reading the response
+// until released
+type stalledWriter struct {
+	header  http.Header
+	writing sync.Once
+	stalled chan struct{}
+	release chan struct{}
+}
+
+func (w *stalledWriter) Header() http.Header { return w.header }
+func (w *stalledWriter) WriteHeader(int)     {}
+func (w *stalledWriter) Write(b []byte) (int, error) {
+	w.writing.Do(func() { close(w.stalled) })
+	<-w.release
+	return len(b), nil
+}
+
+func TestServer_StalledStream(t *testing.T) {
+	s := New(testutil.Template("Hero", 100, 60, 0, 2), testutil.Template("Villain", 100, 10, 50, 1), nil)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	w := &stalledWriter{header: http.Header{}, stalled: make(chan struct{}), release: make(chan struct{})}
+	streamed := make(chan struct{})
+	go func() {
+		defer close(streamed)
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/simulations/stream", strings.NewReader(`{"duels": 200}`)))
+	}()
+	<-w.stalled
+
+	// the duels of the other clients aren't held up by the stalled one
+	fought := make(chan error, 1)
+	go func() {
+		res, err := http.Post(ts.URL+"/duels", "application/json", strings.NewReader(duelSpec))
+		if err == nil {
+			res.Body.Close()
+		}
+		fought <- err
+	}()
+	select {
+	case err := <-fought:
+		if err != nil {
+			t.Errorf("POST /duels returned an unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("POST /duels waited for the stalled stream")
+	}
+
+	close(w.release)
+	<-streamed
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// maxRounds bounds the rounds of the requested duels
+const maxRounds = 1000
+
+// RangeSpec is a stat written either as a fixed value, e.g. 80,
+// or as a range, e.g. {"min": 70, "max": 90}; like in the JSON of
+// the protocol buffers, the ends left out of a range are 0
+type RangeSpec core.StatRange
+
+// UnmarshalJSON reads a fixed value or a range
+func (rs *RangeSpec) UnmarshalJSON(data []byte) error {
+	var v float64
+	if err := json.Unmarshal(data, &v); err == nil {
+		*rs = RangeSpec{Min: v, Max: v}
+		return nil
+	}
+
+	var r struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return errors.New(`a stat must be a number or a range like {"min": 70, "max": 90}`)
+	}
+	*rs = RangeSpec{Min: r.Min, Max: r.Max}
+	return nil
+}
+
+// SeedSpec is a seed written as a number or, like 64-bit
+// integers in the JSON of the protocol buffers, as a string
+type SeedSpec int64
+
+// UnmarshalJSON reads a number or a string holding one
+func (ss *SeedSpec) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		data = []byte(s)
+	}
+	var v int64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.New("the seed must be an integer")
+	}
+	*ss = SeedSpec(v)
+	return nil
+}
+
+// skillConfigs reads the params written, like in the JSON of the
+// protocol buffers, as strings holding the JSON of the params
+func skillConfigs(configs []core.SkillConfig) ([]core.SkillConfig, error) {
+	read := []core.SkillConfig{}
+	for _, config := range configs {
+		var params string
+		if err := json.Unmarshal(config.Params, &params); err == nil {
+			if !json.Valid([]byte(params)) {
+				return nil, fmt.Errorf("the params of %s aren't valid JSON", config.Type)
+			}
+			config.Params = json.RawMessage(params)
+		}
+		read = append(read, config)
+	}
+	return read, nil
+}
+
+// StatsSpec holds the stats of a fighter
+type StatsSpec struct {
+	Health   RangeSpec `json:"health"`
+	Strength RangeSpec `json:"strength"`
+	Defence  RangeSpec `json:"defence"`
+	Speed    RangeSpec `json:"speed"`
+	Luck     RangeSpec `json:"luck"`
+
+	Accuracy       RangeSpec `json:"accuracy"`
+	Evasion        RangeSpec `json:"evasion"`
+	CritChance     RangeSpec `json:"critChance"`
+	CritMultiplier RangeSpec `json:"critMultiplier"`
+}
+
+// FighterSpec describes a fighter; the skills are written
+// like in the configuration files, e.g.
+// {"type": "CriticalStrike", "params": {"DoubleStrikeChance": 0.1}},
+// or with the params as a string, like in the JSON of the protocol buffers
+type FighterSpec struct {
+	Name        string             `json:"name"`
+	Stats       StatsSpec          `json:"stats"`
+	Offensive   []core.SkillConfig `json:"offensive,omitempty"`
+	Defensive   []core.SkillConfig `json:"defensive,omitempty"`
+	Resistances core.Resistances   `json:"resistances,omitempty"`
+}
+
+// Template validates the spec and builds the fighter's template;
+// the stats and skill parameters must be within their domains
+func (fs FighterSpec) Template() (core.PlayerTemplate, error) {
+	if fs.Name == "" {
+		return core.PlayerTemplate{}, errors.New("the fighter has no name")
+	}
+
+	stats := []struct {
+		name string
+		RangeSpec
+	}{
+		{"health", fs.Stats.Health},
+		{"strength", fs.Stats.Strength},
+		{"defence", fs.Stats.Defence},
+		{"speed", fs.Stats.Speed},
+		{"luck", fs.Stats.Luck},
+		{"accuracy", fs.Stats.Accuracy},
+		{"evasion", fs.Stats.Evasion},
+		{"critChance", fs.Stats.CritChance},
+		{"critMultiplier", fs.Stats.CritMultiplier},
+	}
+	for _, s := range stats {
+		if s.Min > s.Max {
+			return core.PlayerTemplate{}, fmt.Errorf("%s: the %s range [%v, %v] is reversed", fs.Name, s.name, s.Min, s.Max)
+		}
+	}
+	if fs.Stats.Health.Min <= 0 {
+		return core.PlayerTemplate{}, fmt.Errorf("%s: the health must be above 0", fs.Name)
+	}
+
+	offensiveConfigs, err := skillConfigs(fs.Offensive)
+	if err != nil {
+		return core.PlayerTemplate{}, fmt.Errorf("%s: %v", fs.Name, err)
+	}
+	defensiveConfigs, err := skillConfigs(fs.Defensive)
+	if err != nil {
+		return core.PlayerTemplate{}, fmt.Errorf("%s: %v", fs.Name, err)
+	}
+	for _, configs := range [][]core.SkillConfig{offensiveConfigs, defensiveConfigs} {
+		if err := checkSkills(configs); err != nil {
+			return core.PlayerTemplate{}, fmt.Errorf("%s: %v", fs.Name, err)
+		}
+	}
+
+	offensive, err := core.BuildSkills(offensiveConfigs)
+	if err != nil {
+		return core.PlayerTemplate{}, fmt.Errorf("%s: %v", fs.Name, err)
+	}
+	defensive, err := core.BuildSkills(defensiveConfigs)
+	if err != nil {
+		return core.PlayerTemplate{}, fmt.Errorf("%s: %v", fs.Name, err)
+	}
+
+	template := core.PlayerTemplate{
+		Name: fs.Name,
+		Stats: core.StatRanges{
+			Health:         core.StatRange(fs.Stats.Health),
+			Strength:       core.StatRange(fs.Stats.Strength),
+			Defence:        core.StatRange(fs.Stats.Defence),
+			Speed:          core.StatRange(fs.Stats.Speed),
+			Luck:           core.StatRange(fs.Stats.Luck),
+			Accuracy:       core.StatRange(fs.Stats.Accuracy),
+			Evasion:        core.StatRange(fs.Stats.Evasion),
+			CritChance:     core.StatRange(fs.Stats.CritChance),
+			CritMultiplier: core.StatRange(fs.Stats.CritMultiplier),
+		},
+		Skills:      core.PlayerSkills{OffensiveSkills: offensive, DefensiveSkills: defensive},
+		Resistances: fs.Resistances,
+	}
+	if err := template.Validate(); err != nil {
+		return core.PlayerTemplate{}, err
+	}
+	return template, nil
+}
+
+// remoteSkills are the skill types the fighters of the requests may
+// use; the other registered types, e.g. the scripts, which are read
+// from the server's files, can't be used in requests
+var remoteSkills = map[string]bool{
+	"CriticalStrike":      true,
+	"Resilience":          true,
+	"Luck":                true,
+	"ElementalInfusion":   true,
+	"ElementalConversion": true,
+	"PiercingStrike":      true,
+	"SunderArmor":         true,
+	"Costly":              true,
+	"Triggered":           true,
+	"Declarative":         true,
+}
+
+// checkSkills checks, before any skill is built, that the configurations
+// only use remote skills at any depth, including the skills wrapped by
+// other skills; like encoding/json, the type fields are matched whatever
+// the case of their name
+func checkSkills(configs []core.SkillConfig) error {
+	data, err := json.Marshal(configs)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	for _, t := range skillTypes(v) {
+		if core.IsSkillType(t) && !remoteSkills[t] {
+			return fmt.Errorf("%s skills can't be used in requests", t)
+		}
+	}
+	return nil
+}
+
+// skillTypes returns the values of the type fields of the JSON value
+func skillTypes(v interface{}) []string {
+	types := []string{}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if t, ok := child.(string); ok && strings.EqualFold(key, "type") {
+				types = append(types, t)
+			}
+			types = append(types, skillTypes(child)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			types = append(types, skillTypes(child)...)
+		}
+	}
+	return types
+}
+
+// DuelSpec describes a duel; the server's fighters fight
+// when no players are given and a random seed is used
+// when the seed isn't set
+type DuelSpec struct {
+	Players []FighterSpec `json:"players,omitempty"`
+	Rounds  int           `json:"rounds,omitempty"`
+	Seed    SeedSpec      `json:"seed,omitempty"`
+}
+
+// SimulationSpec describes a simulation of many duels
+type SimulationSpec struct {
+	DuelSpec
+	Duels int `json:"duels"`
+}
+
+// templates validates the spec and returns the templates of the fighters
+func (s *Server) templates(spec DuelSpec) (core.PlayerTemplate, core.PlayerTemplate, error) {
+	if spec.Rounds < 0 || spec.Rounds > maxRounds {
+		return core.PlayerTemplate{}, core.PlayerTemplate{}, fmt.Errorf("rounds must be within [1, %d]", maxRounds)
+	}
+
+	switch len(spec.Players) {
+	case 0:
+		return s.PlayerOne, s.PlayerTwo, nil
+	case 2:
+	default:
+		return core.PlayerTemplate{}, core.PlayerTemplate{}, fmt.Errorf("a duel needs 2 players, got %d", len(spec.Players))
+	}
+
+	one, err := spec.Players[0].Template()
+	if err != nil {
+		return core.PlayerTemplate{}, core.PlayerTemplate{}, fmt.Errorf("players[0]: %v", err)
+	}
+	two, err := spec.Players[1].Template()
+	if err != nil {
+		return core.PlayerTemplate{}, core.PlayerTemplate{}, fmt.Errorf("players[1]: %v", err)
+	}
+	if one.Name == two.Name {
+		return core.PlayerTemplate{}, core.PlayerTemplate{}, fmt.Errorf("the players must have different names, both are %s", one.Name)
+	}
+	return one, two, nil
+}
+
+// simulationTemplates validates the spec and returns the templates of the fighters
+func (s *Server) simulationTemplates(spec SimulationSpec) (core.PlayerTemplate, core.PlayerTemplate, error) {
+	if spec.Duels <= 0 || spec.Duels > maxDuels {
+		return core.PlayerTemplate{}, core.PlayerTemplate{}, fmt.Errorf("duels must be within [1, %d]", maxDuels)
+	}
+	return s.templates(spec.DuelSpec)
+}
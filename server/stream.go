@@ -0,0 +1,172 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/history"
+	battlepb "github.com/pfzero/battle-simulator/proto"
+	"github.com/pfzero/battle-simulator/simulation"
+)
+
+// The streams serve the rpcs of the Battle service of proto/battle.proto
+// over HTTP, for the clients without gRPC (see grpc.go): the request is
+// the JSON of the rpc's request and every line of the response is a
+// message of the rpc, in the canonical JSON mapping of protocol buffers
+// (protojson), written as soon as it happens
+
+// progressUpdates is the number of progress messages of a simulation stream
+const progressUpdates = 20
+
+// stream writes the messages of a stream; the messages are queued and
+// written by their own goroutine, so that slow clients don't hold up
+// the duels, which run one at a time
+type stream struct {
+	write func(message interface{})
+
+	mu     sync.Mutex
+	queue  []interface{}
+	closed bool
+	// ready signals the messages queued and done the end of the writes
+	ready chan struct{}
+	done  chan struct{}
+}
+
+func newStream(write func(message interface{})) *stream {
+	s := &stream{
+		write: write,
+		ready: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// newHTTPStream writes the protojson form of the messages,
+// one per line, flushing every one of them
+func newHTTPStream(w http.ResponseWriter) *stream {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	return newStream(func(message interface{}) {
+		data, err := protojson.Marshal(message.(proto.Message))
+		if err != nil {
+			return
+		}
+		w.Write(append(data, '\n'))
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	})
+}
+
+// send queues the message; it never blocks
+func (s *stream) send(message interface{}) {
+	s.mu.Lock()
+	s.queue = append(s.queue, message)
+	s.mu.Unlock()
+	s.signal()
+}
+
+// close waits for the queued messages to be written;
+// it must be called before the handler returns
+func (s *stream) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.signal()
+	<-s.done
+}
+
+func (s *stream) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// run writes the queued messages until the stream is closed
+func (s *stream) run() {
+	defer close(s.done)
+	for range s.ready {
+		s.mu.Lock()
+		queue, closed := s.queue, s.closed
+		s.queue = nil
+		s.mu.Unlock()
+
+		for _, message := range queue {
+			s.write(message)
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+// streamDuel fights the duel of the spec and streams its events
+func (s *Server) streamDuel(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodPost) {
+		return
+	}
+	spec, one, two, ok := s.duelSpec(w, r)
+	if !ok {
+		return
+	}
+
+	out := newHTTPStream(w)
+	defer out.close()
+	s.sendDuel(spec, one, two, out)
+}
+
+// streamSimulation simulates the duels of the spec and streams its progress
+func (s *Server) streamSimulation(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodPost) {
+		return
+	}
+	spec, one, two, ok := s.simulationSpec(w, r)
+	if !ok {
+		return
+	}
+
+	out := newHTTPStream(w)
+	defer out.close()
+	s.sendSimulation(spec, one, two, out)
+}
+
+// sendDuel fights the duel and sends the fighters, its events and its
+// result, or the error saving it, as DuelMessages
+func (s *Server) sendDuel(spec DuelSpec, one, two core.PlayerTemplate, out *stream) {
+	recorder := &history.Recorder{
+		OnFighters: func(fighters []history.Fighter) {
+			out.send(&battlepb.DuelMessage{Message: &battlepb.DuelMessage_Fighters{Fighters: newFighters(fighters)}})
+		},
+		OnEvent: func(e history.Event) {
+			out.send(&battlepb.DuelMessage{Message: &battlepb.DuelMessage_Event{Event: newEvent(e)}})
+		},
+	}
+
+	record, err := s.fight(spec, one, two, recorder)
+	if err != nil {
+		out.send(&battlepb.DuelMessage{Message: &battlepb.DuelMessage_Error{Error: err.Error()}})
+		return
+	}
+	out.send(&battlepb.DuelMessage{Message: &battlepb.DuelMessage_Result{Result: newDuelResult(record)}})
+}
+
+// sendSimulation simulates the duels and sends progressUpdates
+// progress messages and the result as SimulationMessages
+func (s *Server) sendSimulation(spec SimulationSpec, one, two core.PlayerTemplate, out *stream) {
+	every := spec.Duels / progressUpdates
+	if every == 0 {
+		every = 1
+	}
+	result := s.run(spec, one, two, func(stats simulation.Stats) {
+		if stats.Duels%every == 0 || stats.Duels == spec.Duels {
+			out.send(&battlepb.SimulationMessage{Message: &battlepb.SimulationMessage_Progress{Progress: newProgress(stats, spec.Duels)}})
+		}
+	})
+	out.send(&battlepb.SimulationMessage{Message: &battlepb.SimulationMessage_Result{Result: newSimulationResult(result)}})
+}
@@ -0,0 +1,501 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// maxStates bounds the number of distinct states of a round
+const maxStates = 1 << 20
+
+// ErrTooManyStates is returned when a duel has too many distinct
+// health states to be solved exactly
+var ErrTooManyStates = errors.New("simulation: the duel has too many states to be solved exactly")
+
+// Exact computes the exact outcome of the duels between fighters with
+// fixed stats; the duel is a Markov chain over the health of both
+// fighters (and the cooldown of their Resilience skills) whose
+// transitions are the possible attacks and their probabilities
+//
+// Only the fighters that always attack and whose randomness comes from
+// evasion, critical hits and the Critical Strike, Piercing Strike, Luck and
+// Resilience skills are supported; their stat ranges must be single values
+type Exact struct {
+	PlayerOne core.PlayerTemplate
+	PlayerTwo core.PlayerTemplate
+
+	Rounds int
+
+	// DamageFormula must be deterministic (Subtractive, Mitigation
+	// or MinimumDamage); defaults to the Subtractive formula
+	DamageFormula core.DamageFormula
+}
+
+// Outcome holds the probabilities of the results of a duel
+type Outcome struct {
+	PlayerOneWins float64 `json:"playerOneWins"`
+	PlayerTwoWins float64 `json:"playerTwoWins"`
+	Ties          float64 `json:"ties"`
+	// Rounds is the expected number of rounds of a duel
+	// and RoundsVariance is its variance
+	Rounds         float64 `json:"rounds"`
+	RoundsVariance float64 `json:"roundsVariance"`
+	// States is the largest number of distinct states of a round
+	States int `json:"states"`
+}
+
+func (o Outcome) String() string {
+	return fmt.Sprintf("%.4f%% / %.4f%% wins, %.4f%% ties, %.4f rounds on average (%d states)",
+		o.PlayerOneWins*100, o.PlayerTwoWins*100, o.Ties*100, o.Rounds, o.States)
+}
+
+// Check compares the results of simulated duels with the exact outcome;
+// it returns an error listing the rates that are more than the given number
+// of standard errors away from their exact values
+func (o Outcome) Check(s Stats, sigmas float64) error {
+	if s.Duels == 0 {
+		return errors.New("simulation: there are no simulated duels to check")
+	}
+
+	n := float64(s.Duels)
+	checks := []struct {
+		name     string
+		got      float64
+		want     float64
+		variance float64
+	}{
+		{"player one wins", s.PlayerOneWinRate(), o.PlayerOneWins, o.PlayerOneWins * (1 - o.PlayerOneWins)},
+		{"player two wins", s.PlayerTwoWinRate(), o.PlayerTwoWins, o.PlayerTwoWins * (1 - o.PlayerTwoWins)},
+		{"ties", s.TieRate(), o.Ties, o.Ties * (1 - o.Ties)},
+		{"rounds", s.AverageRounds(), o.Rounds, o.RoundsVariance},
+	}
+
+	mismatches := []string{}
+	for _, c := range checks {
+		// outcomes that are certain must always happen
+		tolerance := math.Max(1e-9, sigmas*math.Sqrt(math.Max(0, c.variance)/n))
+		if math.Abs(c.got-c.want) > tolerance {
+			mismatches = append(mismatches, fmt.Sprintf("%s %.4f, want %.4f ± %.4f", c.name, c.got, c.want, tolerance))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("simulation: the simulated duels don't match the exact outcome: %s", strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// hit is the part of a core.Hit the exact solver follows
+type hit struct {
+	potential   float64
+	penetration float64
+	ratio       float64
+}
+
+// branch is one of the possible attacks, with its probability;
+// cooldowns holds the defender's Resilience skills used on it
+type branch struct {
+	p         float64
+	hits      []hit
+	cooldowns uint64
+}
+
+// fighter is a player template reduced to what the solver needs
+type fighter struct {
+	stats core.PlayerStats
+	// resistance is the physical damage resistance
+	resistance float64
+
+	offensive []core.Skill
+	defensive []core.Skill
+}
+
+// chance converts the threshold of a rand.Float64 comparison into a probability
+func chance(p float64) float64 {
+	return math.Max(0, math.Min(1, p))
+}
+
+// newFighter checks that the template is supported by the solver
+func newFighter(t core.PlayerTemplate) (*fighter, error) {
+	ranges := []struct {
+		name string
+		core.StatRange
+	}{
+		{"health", t.Stats.Health},
+		{"strength", t.Stats.Strength},
+		{"defence", t.Stats.Defence},
+		{"speed", t.Stats.Speed},
+		{"luck", t.Stats.Luck},
+		{"accuracy", t.Stats.Accuracy},
+		{"evasion", t.Stats.Evasion},
+		{"crit chance", t.Stats.CritChance},
+		{"crit multiplier", t.Stats.CritMultiplier},
+	}
+	for _, r := range ranges {
+		if r.Min != r.Max {
+			return nil, fmt.Errorf("simulation: %s has a random %s; the exact solver needs fixed stats", t.Name, r.name)
+		}
+	}
+
+	switch {
+	case len(t.Equipment) > 0:
+		return nil, fmt.Errorf("simulation: %s has equipment, which the exact solver doesn't support", t.Name)
+	case len(t.Abilities) > 0:
+		return nil, fmt.Errorf("simulation: %s has abilities, which the exact solver doesn't support", t.Name)
+	case len(t.Pools) > 0:
+		return nil, fmt.Errorf("simulation: %s has resource pools, which the exact solver doesn't support", t.Name)
+	case t.Strategy != nil:
+		return nil, fmt.Errorf("simulation: %s has a strategy; the exact solver needs fighters that always attack", t.Name)
+	}
+
+	for _, skill := range t.Skills.OffensiveSkills {
+		switch skill.(type) {
+		case *core.CriticalStrike, *core.PiercingStrike:
+		default:
+			return nil, fmt.Errorf("simulation: the exact solver doesn't support %s's offensive skill %s", t.Name, skill.GetDescription())
+		}
+	}
+	resiliences := 0
+	for _, skill := range t.Skills.DefensiveSkills {
+		switch skill.(type) {
+		case *core.Luck:
+		case *core.Resilience:
+			resiliences++
+		default:
+			return nil, fmt.Errorf("simulation: the exact solver doesn't support %s's defensive skill %s", t.Name, skill.GetDescription())
+		}
+	}
+	if resiliences > 64 {
+		return nil, fmt.Errorf("simulation: %s has more than 64 Resilience skills", t.Name)
+	}
+
+	return &fighter{
+		stats:      fixedStats(t.Stats),
+		resistance: t.Resistances[core.Physical],
+		offensive:  t.Skills.OffensiveSkills,
+		defensive:  t.Skills.DefensiveSkills,
+	}, nil
+}
+
+// fixedStats returns the stats of a template with fixed ranges
+func fixedStats(sr core.StatRanges) core.PlayerStats {
+	return core.PlayerStats{
+		Health:   sr.Health.Min,
+		Strength: sr.Strength.Min,
+		Defence:  sr.Defence.Min,
+		Speed:    sr.Speed.Min,
+		Luck:     sr.Luck.Min,
+
+		Accuracy:       sr.Accuracy.Min,
+		Evasion:        sr.Evasion.Min,
+		CritChance:     sr.CritChance.Min,
+		CritMultiplier: sr.CritMultiplier.Min,
+	}
+}
+
+// deterministic checks that the formula doesn't use randomness
+func deterministic(formula core.DamageFormula) bool {
+	switch f := formula.(type) {
+	case nil, core.Subtractive, core.Mitigation:
+		return true
+	case core.MinimumDamage:
+		return f.Formula != nil && deterministic(f.Formula)
+	}
+	return false
+}
+
+// attacks returns the possible attacks of the attacker, before they're defended
+func (f *fighter) attacks() []branch {
+	branches := []branch{{p: 1, hits: []hit{{potential: f.stats.Strength}}}}
+	for _, skill := range f.offensive {
+		next := []branch{}
+		for _, b := range branches {
+			switch s := skill.(type) {
+			case *core.CriticalStrike:
+				double, triple := chance(s.DoubleStrikeChance), chance(s.TripleStrikeChance)
+				next = append(next,
+					branch{p: b.p * (1 - double), hits: b.hits},
+					branch{p: b.p * double * (1 - triple), hits: append(copyHits(b.hits), hit{potential: f.stats.Strength})},
+					branch{p: b.p * double * triple, hits: append(copyHits(b.hits), hit{potential: f.stats.Strength}, hit{potential: f.stats.Strength})},
+				)
+			case *core.PiercingStrike:
+				pierced := copyHits(b.hits)
+				for i := range pierced {
+					pierced[i].penetration += s.Penetration
+					pierced[i].ratio = math.Min(1, pierced[i].ratio+s.Ratio)
+				}
+				c := chance(s.Chance)
+				next = append(next, branch{p: b.p * (1 - c), hits: b.hits}, branch{p: b.p * c, hits: pierced})
+			}
+		}
+		branches = next
+	}
+
+	if f.stats.CritChance <= 0 {
+		return prune(branches)
+	}
+	multiplier := f.stats.CritMultiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	return prune(perHit(branches, chance(f.stats.CritChance), func(h *hit) { h.potential *= multiplier }))
+}
+
+// defend returns the possible attacks once defended by the defender,
+// whose Resilience skills have the given cooldowns
+func (f *fighter) defend(attacks []branch, accuracy float64, cooldowns uint64) []branch {
+	branches := make([]branch, len(attacks))
+	for i, b := range attacks {
+		branches[i] = branch{p: b.p, hits: b.hits, cooldowns: cooldowns}
+	}
+
+	evade := func(h *hit) { h.potential = 0 }
+	branches = perHit(branches, core.MissChance(accuracy, f.stats.Evasion), evade)
+
+	resilience := 0
+	for _, skill := range f.defensive {
+		switch s := skill.(type) {
+		case *core.Luck:
+			branches = perHit(branches, chance(s.Chance), evade)
+		case *core.Resilience:
+			bit := uint64(1) << uint(resilience)
+			resilience++
+
+			next := []branch{}
+			for _, b := range branches {
+				// the skill can't be used 2 turns in a row
+				if b.cooldowns&bit != 0 {
+					next = append(next, branch{p: b.p, hits: b.hits, cooldowns: b.cooldowns &^ bit})
+					continue
+				}
+				reduced := copyHits(b.hits)
+				for i := range reduced {
+					reduced[i].potential -= s.DamageReduction * reduced[i].potential
+				}
+				c := chance(s.Chance)
+				next = append(next,
+					branch{p: b.p * (1 - c), hits: b.hits, cooldowns: b.cooldowns},
+					branch{p: b.p * c, hits: reduced, cooldowns: b.cooldowns | bit},
+				)
+			}
+			branches = next
+		}
+		branches = prune(branches)
+	}
+	return branches
+}
+
+// damage returns the damage the hit does to the fighter
+func (f *fighter) damage(h hit, formula core.DamageFormula) float64 {
+	if h.potential == 0 {
+		return 0
+	}
+	defence := math.Max(0, f.stats.Defence)
+	if defence > 0 {
+		defence = math.Max(0, defence*(1-h.ratio)-h.penetration)
+	}
+	return math.Max(0, formula.Damage(h.potential, defence)*(1-f.resistance))
+}
+
+// perHit branches every attack on every hit being changed with the given probability
+func perHit(branches []branch, p float64, change func(*hit)) []branch {
+	if p == 0 {
+		return branches
+	}
+	for i := 0; ; i++ {
+		next, more := []branch{}, false
+		for _, b := range branches {
+			if i >= len(b.hits) {
+				next = append(next, b)
+				continue
+			}
+			more = true
+			changed := copyHits(b.hits)
+			change(&changed[i])
+			next = append(next,
+				branch{p: b.p * (1 - p), hits: b.hits, cooldowns: b.cooldowns},
+				branch{p: b.p * p, hits: changed, cooldowns: b.cooldowns},
+			)
+		}
+		if !more {
+			return branches
+		}
+		branches = prune(next)
+	}
+}
+
+// prune drops the impossible branches
+func prune(branches []branch) []branch {
+	pruned := branches[:0]
+	for _, b := range branches {
+		if b.p > 0 {
+			pruned = append(pruned, b)
+		}
+	}
+	return pruned
+}
+
+func copyHits(hits []hit) []hit {
+	return append([]hit{}, hits...)
+}
+
+// transition is a possible result of a turn: the damages of
+// the hits and the cooldowns of the defender's Resilience skills
+type transition struct {
+	p         float64
+	damages   []float64
+	cooldowns uint64
+}
+
+// duelist follows a fighter and the transitions of the turns taken against him
+type duelist struct {
+	*fighter
+	turns map[uint64][]transition
+}
+
+// turn returns the possible results of the attacker's turn
+// against the defender with the given cooldowns
+func turn(attacker, defender *duelist, cooldowns uint64, attacks []branch, formula core.DamageFormula) []transition {
+	if transitions, ok := defender.turns[cooldowns]; ok {
+		return transitions
+	}
+
+	transitions := []transition{}
+	for _, b := range defender.defend(attacks, attacker.stats.Accuracy, cooldowns) {
+		damages := make([]float64, len(b.hits))
+		for i, h := range b.hits {
+			damages[i] = defender.damage(h, formula)
+		}
+		transitions = append(transitions, transition{p: b.p, damages: damages, cooldowns: b.cooldowns})
+	}
+	defender.turns[cooldowns] = transitions
+	return transitions
+}
+
+// takeHits returns the health left after the hits; the hits
+// stop once the defender is knocked out
+func takeHits(health float64, damages []float64) float64 {
+	for _, damage := range damages {
+		if health <= 0 {
+			break
+		}
+		health = math.Max(0, health-damage)
+	}
+	return health
+}
+
+// state is the state of the duel at the start of a round;
+// the first index is the fighter that attacks first
+type state struct {
+	health    [2]float64
+	cooldowns [2]uint64
+}
+
+// Solve computes the exact outcome of the duels
+func (e *Exact) Solve() (Outcome, error) {
+	formula := e.DamageFormula
+	if !deterministic(formula) {
+		return Outcome{}, errors.New("simulation: the exact solver needs a deterministic damage formula")
+	}
+	if formula == nil {
+		formula = core.Subtractive{}
+	}
+
+	one, err := newFighter(e.PlayerOne)
+	if err != nil {
+		return Outcome{}, err
+	}
+	two, err := newFighter(e.PlayerTwo)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	// the same order as the DuelMaster's
+	fighters := [2]*duelist{{fighter: one, turns: map[uint64][]transition{}}, {fighter: two, turns: map[uint64][]transition{}}}
+	firstIsOne := true
+	if one.stats.Speed < two.stats.Speed || (one.stats.Speed == two.stats.Speed && one.stats.Luck < two.stats.Luck) {
+		fighters[0], fighters[1] = fighters[1], fighters[0]
+		firstIsOne = false
+	}
+	attacks := [2][]branch{fighters[0].attacks(), fighters[1].attacks()}
+
+	var wins [2]float64
+	var rounds, squares float64
+	end := func(winner int, p float64, round int) {
+		wins[winner] += p
+		rounds += p * float64(round)
+		squares += p * float64(round*round)
+	}
+
+	outcome := Outcome{}
+	states := map[state]float64{{health: [2]float64{one.stats.Health, two.stats.Health}}: 1}
+	if !firstIsOne {
+		states = map[state]float64{{health: [2]float64{two.stats.Health, one.stats.Health}}: 1}
+	}
+
+	limit := int(math.Max(0, float64(e.Rounds)))
+	for round := 1; round <= limit && len(states) > 0; round++ {
+		if len(states) > maxStates {
+			return Outcome{}, ErrTooManyStates
+		}
+		if len(states) > outcome.States {
+			outcome.States = len(states)
+		}
+
+		next := map[state]float64{}
+		for s, p := range states {
+			// fighters can only start knocked out; the DuelMaster
+			// checks the defender first
+			if s.health[1] <= 0 {
+				end(0, p, round)
+				continue
+			}
+			if s.health[0] <= 0 {
+				end(1, p, round)
+				continue
+			}
+
+			// the first fighter attacks, then the second one
+			for _, first := range turn(fighters[0], fighters[1], s.cooldowns[1], attacks[0], formula) {
+				pf := p * first.p
+				after := s
+				after.health[1] = takeHits(s.health[1], first.damages)
+				after.cooldowns[1] = first.cooldowns
+				if after.health[1] <= 0 {
+					end(0, pf, round)
+					continue
+				}
+
+				for _, second := range turn(fighters[1], fighters[0], after.cooldowns[0], attacks[1], formula) {
+					ps := pf * second.p
+					last := after
+					last.health[0] = takeHits(after.health[0], second.damages)
+					last.cooldowns[0] = second.cooldowns
+					if last.health[0] <= 0 {
+						end(1, ps, round)
+						continue
+					}
+					next[last] += ps
+				}
+			}
+		}
+		states = next
+	}
+
+	for _, p := range states {
+		outcome.Ties += p
+	}
+	rounds += outcome.Ties * float64(limit)
+	squares += outcome.Ties * float64(limit*limit)
+
+	outcome.PlayerOneWins, outcome.PlayerTwoWins = wins[0], wins[1]
+	if !firstIsOne {
+		outcome.PlayerOneWins, outcome.PlayerTwoWins = wins[1], wins[0]
+	}
+	outcome.Rounds = rounds
+	outcome.RoundsVariance = math.Max(0, squares-rounds*rounds)
+	return outcome, nil
+}
@@ -0,0 +1,166 @@
+package simulation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/internal/testutil"
+)
+
+func evasive(t core.PlayerTemplate, evasion float64) core.PlayerTemplate {
+	t.Stats.Evasion = core.StatRange{Min: evasion, Max: evasion}
+	return t
+}
+
+func TestExact_Solve(t *testing.T) {
+	tests := []struct {
+		name string
+		e    *Exact
+		want Outcome
+	}{
+		{
+			name: "the faster fighter knocks the other out on round 10",
+			e: &Exact{
+				PlayerOne: testutil.Template("Hero", 100, 60, 0, 2),
+				PlayerTwo: testutil.Template("Villain", 100, 10, 50, 1),
+				Rounds:    20,
+			},
+			want: Outcome{PlayerOneWins: 1, Rounds: 10},
+		},
+		{
+			name: "fighters weaker than the opponent's defence tie",
+			e: &Exact{
+				PlayerOne: testutil.Template("Hero", 100, 40, 50, 1),
+				PlayerTwo: testutil.Template("Villain", 100, 40, 50, 2),
+				Rounds:    20,
+			},
+			want: Outcome{Ties: 1, Rounds: 20},
+		},
+		{
+			// every hit knocks the defender out and misses half of the times:
+			// the duel ends on every turn with a 50% chance
+			name: "one-hit fighters with 50% evasion",
+			e: &Exact{
+				PlayerOne: evasive(testutil.Template("Hero", 100, 100, 0, 1), 0.5),
+				PlayerTwo: evasive(testutil.Template("Villain", 100, 100, 0, 2), 0.5),
+				Rounds:    3,
+			},
+			want: Outcome{
+				PlayerOneWins:  0.328125,
+				PlayerTwoWins:  0.65625,
+				Ties:           0.015625,
+				Rounds:         1.3125,
+				RoundsVariance: 0.75*1 + 0.1875*4 + 0.0625*9 - 1.3125*1.3125,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.e.Solve()
+			if err != nil {
+				t.Fatalf("Solve() returned an unexpected error: %v", err)
+			}
+			got.States = 0
+			for _, v := range [][2]float64{
+				{got.PlayerOneWins, tt.want.PlayerOneWins},
+				{got.PlayerTwoWins, tt.want.PlayerTwoWins},
+				{got.Ties, tt.want.Ties},
+				{got.Rounds, tt.want.Rounds},
+				{got.RoundsVariance, tt.want.RoundsVariance},
+			} {
+				if math.Abs(v[0]-v[1]) > 1e-9 {
+					t.Errorf("Solve() = %+v, want %+v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestExact_MatchesSimulation checks that StartDuel follows the
+// probabilities of every supported source of randomness
+func TestExact_MatchesSimulation(t *testing.T) {
+	rand.Seed(1)
+
+	hero := testutil.Template("Hero", 120, 45, 10, 2)
+	hero.Stats.CritChance = core.StatRange{Min: 0.2, Max: 0.2}
+	hero.Stats.Accuracy = core.StatRange{Min: 0.1, Max: 0.1}
+	hero.Skills = core.PlayerSkills{
+		OffensiveSkills: []core.Skill{
+			&core.PiercingStrike{Chance: 0.3, Penetration: 5, Ratio: 0.5},
+			&core.CriticalStrike{DoubleStrikeChance: 0.2, TripleStrikeChance: 0.5},
+		},
+		DefensiveSkills: []core.Skill{&core.Luck{Chance: 0.1}},
+	}
+
+	villain := evasive(testutil.Template("Villain", 150, 40, 20, 1), 0.3)
+	villain.Resistances = core.Resistances{core.Physical: 0.1}
+	villain.Skills = core.PlayerSkills{
+		DefensiveSkills: []core.Skill{&core.Resilience{Chance: 0.4, DamageReduction: 0.5}},
+	}
+
+	formulas := []core.DamageFormula{
+		nil,
+		core.MinimumDamage{Formula: core.Mitigation{K: 50}, Ratio: 0.2},
+	}
+	for _, formula := range formulas {
+		exact := &Exact{PlayerOne: hero, PlayerTwo: villain, Rounds: 8, DamageFormula: formula}
+		outcome, err := exact.Solve()
+		if err != nil {
+			t.Fatalf("Solve() returned an unexpected error: %v", err)
+		}
+		if total := outcome.PlayerOneWins + outcome.PlayerTwoWins + outcome.Ties; math.Abs(total-1) > 1e-9 {
+			t.Errorf("Solve() = %s, the probabilities add up to %v", outcome, total)
+		}
+
+		sim := &Simulation{PlayerOne: hero, PlayerTwo: villain, Duels: 20000, Rounds: 8, DamageFormula: formula}
+		if err := outcome.Check(sim.Run(), 4); err != nil {
+			t.Errorf("%v (exact %s)", err, outcome)
+		}
+	}
+}
+
+func TestExact_Unsupported(t *testing.T) {
+	hero, villain := testutil.Template("Hero", 100, 60, 0, 2), testutil.Template("Villain", 100, 10, 50, 1)
+
+	random := hero
+	random.Stats.Strength = core.StatRange{Min: 50, Max: 70}
+	sundering := hero
+	sundering.Skills.OffensiveSkills = []core.Skill{&core.SunderArmor{Chance: 0.5, Amount: 5}}
+	defending := villain
+	defending.Strategy = core.Defensive{}
+
+	tests := []struct {
+		name string
+		e    *Exact
+	}{
+		{name: "random stats", e: &Exact{PlayerOne: random, PlayerTwo: villain, Rounds: 20}},
+		{name: "unsupported skill", e: &Exact{PlayerOne: sundering, PlayerTwo: villain, Rounds: 20}},
+		{name: "strategy", e: &Exact{PlayerOne: hero, PlayerTwo: defending, Rounds: 20}},
+		{name: "random damage", e: &Exact{PlayerOne: hero, PlayerTwo: villain, Rounds: 20, DamageFormula: core.Variance{Formula: core.Subtractive{}, Spread: 0.1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.e.Solve(); err == nil {
+				t.Errorf("Solve() should return an error")
+			}
+		})
+	}
+}
+
+func TestOutcome_Check(t *testing.T) {
+	outcome := Outcome{PlayerOneWins: 0.5, PlayerTwoWins: 0.5, Rounds: 5, RoundsVariance: 1}
+	if err := outcome.Check(Stats{Duels: 100, PlayerOneWins: 52, PlayerTwoWins: 48, TotalRounds: 510}, 3); err != nil {
+		t.Errorf("Check() returned an unexpected error: %v", err)
+	}
+	if err := outcome.Check(Stats{Duels: 100, PlayerOneWins: 80, PlayerTwoWins: 20, TotalRounds: 500}, 3); err == nil {
+		t.Errorf("Check() should reject 80%% wins for a fair duel")
+	}
+	if err := outcome.Check(Stats{Duels: 100, PlayerOneWins: 50, PlayerTwoWins: 49, Ties: 1, TotalRounds: 500}, 3); err == nil {
+		t.Errorf("Check() should reject ties that can't happen")
+	}
+}
@@ -0,0 +1,103 @@
+package simulation
+
+import (
+	"fmt"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// Simulation runs many duels between fighters summoned from
+// 2 templates and aggregates their results
+type Simulation struct {
+	PlayerOne core.PlayerTemplate
+	PlayerTwo core.PlayerTemplate
+
+	Duels  int
+	Rounds int
+
+	// DamageFormula is passed to every duel;
+	// defaults to the Subtractive formula
+	DamageFormula core.DamageFormula
+
+	// Commentator, when set, is passed to every duel
+	Commentator core.Commentator
+	// Progress, when set, is called with the stats after every duel
+	Progress func(Stats)
+}
+
+// Stats represents the aggregated results of a simulation
+type Stats struct {
+	Duels         int `json:"duels"`
+	PlayerOneWins int `json:"playerOneWins"`
+	PlayerTwoWins int `json:"playerTwoWins"`
+	Ties          int `json:"ties"`
+	TotalRounds   int `json:"totalRounds"`
+}
+
+// PlayerOneWinRate returns the percentage of duels won by the first player
+func (s Stats) PlayerOneWinRate() float64 {
+	return s.rate(s.PlayerOneWins)
+}
+
+// PlayerTwoWinRate returns the percentage of duels won by the second player
+func (s Stats) PlayerTwoWinRate() float64 {
+	return s.rate(s.PlayerTwoWins)
+}
+
+// TieRate returns the percentage of duels that ended with a tie
+func (s Stats) TieRate() float64 {
+	return s.rate(s.Ties)
+}
+
+// AverageRounds returns the average number of rounds of a duel
+func (s Stats) AverageRounds() float64 {
+	return s.rate(s.TotalRounds)
+}
+
+func (s Stats) rate(n int) float64 {
+	if s.Duels == 0 {
+		return 0
+	}
+	return float64(n) / float64(s.Duels)
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("%d duels: %.2f%% / %.2f%% wins, %.2f%% ties, %.2f rounds on average",
+		s.Duels, s.PlayerOneWinRate()*100, s.PlayerTwoWinRate()*100, s.TieRate()*100, s.AverageRounds())
+}
+
+// Run plays all the duels of the simulation
+func (s *Simulation) Run() Stats {
+	stats := Stats{}
+	for i := 0; i < s.Duels; i++ {
+		dm := &core.DuelMaster{
+			Rounds:        s.Rounds,
+			PlayerOne:     s.PlayerOne.Summon(),
+			PlayerTwo:     s.PlayerTwo.Summon(),
+			DamageFormula: s.DamageFormula,
+		}
+
+		var result *core.DuelResult
+		if s.Commentator != nil {
+			result = dm.StartDuel(s.Commentator)
+		} else {
+			result = dm.StartDuel()
+		}
+		stats.Duels++
+		stats.TotalRounds += result.Rounds
+
+		switch {
+		case result.IsTie():
+			stats.Ties++
+		case result.Winner == dm.PlayerOne:
+			stats.PlayerOneWins++
+		default:
+			stats.PlayerTwoWins++
+		}
+
+		if s.Progress != nil {
+			s.Progress(stats)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,67 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/internal/testutil"
+)
+
+func TestSimulation_Run(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *Simulation
+		want Stats
+	}{
+		{
+			name: "the faster one-hit fighter wins every duel",
+			s: &Simulation{
+				PlayerOne: testutil.Template("Hero", 100, 200, 0, 1),
+				PlayerTwo: testutil.Template("Villain", 100, 200, 0, 2),
+				Duels:     10,
+				Rounds:    20,
+			},
+			want: Stats{Duels: 10, PlayerTwoWins: 10, TotalRounds: 10},
+		},
+		{
+			name: "fighters weaker than the opponent's defence tie with the subtractive formula",
+			s: &Simulation{
+				PlayerOne: testutil.Template("Hero", 100, 40, 50, 1),
+				PlayerTwo: testutil.Template("Villain", 100, 40, 50, 2),
+				Duels:     5,
+				Rounds:    20,
+			},
+			want: Stats{Duels: 5, Ties: 5, TotalRounds: 100},
+		},
+		{
+			name: "fighters weaker than the opponent's defence deal damage with the mitigation formula",
+			s: &Simulation{
+				PlayerOne:     testutil.Template("Hero", 100, 40, 50, 1),
+				PlayerTwo:     testutil.Template("Villain", 100, 40, 50, 2),
+				Duels:         5,
+				Rounds:        20,
+				DamageFormula: core.Mitigation{K: 50},
+			},
+			// every hit does 20 damage, so the villain knocks the hero out on round 5
+			want: Stats{Duels: 5, PlayerTwoWins: 5, TotalRounds: 25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Run(); got != tt.want {
+				t.Errorf("Run() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStats_Rates(t *testing.T) {
+	s := Stats{Duels: 4, PlayerOneWins: 2, PlayerTwoWins: 1, Ties: 1, TotalRounds: 30}
+	if s.PlayerOneWinRate() != 0.5 || s.PlayerTwoWinRate() != 0.25 || s.TieRate() != 0.25 || s.AverageRounds() != 7.5 {
+		t.Errorf("rates of %+v are wrong: %s", s, s)
+	}
+	if (Stats{}).PlayerOneWinRate() != 0 {
+		t.Errorf("rates of an empty simulation should be 0")
+	}
+}
@@ -0,0 +1,95 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// Human is a strategy that lets a person choose the player's actions:
+// on every turn it writes the state of the duel and the available
+// actions to Out and waits for the choice on In
+type Human struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// NewHuman creates a human strategy reading the choices
+// from in and writing the prompts to out
+func NewHuman(in io.Reader, out io.Writer) *Human {
+	return &Human{in: bufio.NewScanner(in), out: out}
+}
+
+// option is an action the human can choose
+type option struct {
+	action      core.Action
+	description string
+}
+
+func (h *Human) options(self *core.Player) []option {
+	options := []option{
+		{core.Action{Kind: core.AttackAction}, "Attack"},
+		{core.Action{Kind: core.DefendAction}, "Defend (raise your defence until your next turn)"},
+		{core.Action{Kind: core.HealAction}, "Heal"},
+		{core.Action{Kind: core.FleeAction}, "Flee (lose the duel)"},
+	}
+	for _, ability := range self.ReadyAbilities() {
+		options = append(options, option{
+			core.Action{Kind: core.AbilityAction, Ability: ability},
+			"Use " + ability.Skill.GetDescription(),
+		})
+	}
+	return options
+}
+
+func (h *Human) presentPlayer(p *core.Player) {
+	fmt.Fprintf(h.out, "  %s: %.2f / %.2f health, %.2f strength, %.2f defence",
+		p.Name, p.Health, p.StartingHealth(), p.Strength, p.EffectiveDefence())
+	for _, r := range core.ResourceTypes {
+		if pool, ok := p.Pools[r]; ok {
+			fmt.Fprintf(h.out, ", %.2f / %.2f %s", p.Resource(r), pool.Max, r)
+		}
+	}
+	fmt.Fprintln(h.out)
+}
+
+// ChooseAction presents the duel and waits for a valid choice;
+// the player flees when there is no more input
+func (h *Human) ChooseAction(state core.DuelState) core.Action {
+	fmt.Fprintf(h.out, "\nRound %d of %d\n", state.Round, state.Rounds)
+	h.presentPlayer(state.Self)
+	h.presentPlayer(state.Opponent)
+
+	for _, ability := range state.Self.Abilities {
+		if turns := state.Self.Cooldown(ability); turns > 0 {
+			fmt.Fprintf(h.out, "  %s is ready in %d turns\n", ability.Skill.GetDescription(), turns)
+		} else if !state.Self.CanAfford(ability.Cost) {
+			fmt.Fprintf(h.out, "  %s needs %s\n", ability.Skill.GetDescription(), ability.Cost)
+		}
+	}
+
+	options := h.options(state.Self)
+	for {
+		fmt.Fprintf(h.out, "What will %s do?\n", state.Self.Name)
+		for i, o := range options {
+			fmt.Fprintf(h.out, "  %d) %s\n", i+1, o.description)
+		}
+		fmt.Fprint(h.out, "> ")
+
+		if !h.in.Scan() {
+			fmt.Fprintln(h.out)
+			return core.Action{Kind: core.FleeAction}
+		}
+
+		choice, err := strconv.Atoi(strings.TrimSpace(h.in.Text()))
+		if err != nil || choice < 1 || choice > len(options) {
+			fmt.Fprintf(h.out, "Please choose a number between 1 and %d\n", len(options))
+			continue
+		}
+		return options[choice-1].action
+	}
+}
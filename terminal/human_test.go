@@ -0,0 +1,70 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+func TestHuman_ChooseAction(t *testing.T) {
+	slam := &core.Ability{Skill: &core.CriticalStrike{DoubleStrikeChance: 1}, Cooldown: 2}
+
+	tests := []struct {
+		name  string
+		input string
+		want  core.Action
+	}{
+		{name: "chooses an attack", input: "1\n", want: core.Action{Kind: core.AttackAction}},
+		{name: "chooses to defend", input: "2\n", want: core.Action{Kind: core.DefendAction}},
+		{name: "chooses to heal", input: " 3 \n", want: core.Action{Kind: core.HealAction}},
+		{name: "chooses an ability", input: "5\n", want: core.Action{Kind: core.AbilityAction, Ability: slam}},
+		{name: "asks again on invalid input", input: "fight\n9\n2\n", want: core.Action{Kind: core.DefendAction}},
+		{name: "flees when the input ends", input: "", want: core.Action{Kind: core.FleeAction}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			self := core.NewPlayer("Hero", core.PlayerStats{Health: 100, Strength: 50}, core.PlayerSkills{})
+			self.Abilities = []*core.Ability{slam}
+			opponent := core.NewPlayer("Peanut", core.PlayerStats{Health: 80}, core.PlayerSkills{})
+
+			out := &bytes.Buffer{}
+			h := NewHuman(strings.NewReader(tt.input), out)
+			got := h.ChooseAction(core.DuelState{Round: 2, Rounds: 20, Self: self, Opponent: opponent})
+			if got != tt.want {
+				t.Errorf("ChooseAction() = %+v, want %+v", got, tt.want)
+			}
+
+			for _, want := range []string{"Round 2 of 20", "Hero: 100.00", "Peanut: 80.00", "5) Use Critical Strike"} {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("ChooseAction() wrote %q, want it to contain %q", out.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestHuman_Duel(t *testing.T) {
+	slam := &core.Ability{Skill: &core.CriticalStrike{DoubleStrikeChance: 1}, Cooldown: 2}
+	hero := core.NewPlayer("Hero", core.PlayerStats{Health: 100, Strength: 30, Speed: 2}, core.PlayerSkills{})
+	hero.Abilities = []*core.Ability{slam}
+
+	out := &bytes.Buffer{}
+	hero.Strategy = NewHuman(strings.NewReader("5\n1\n"), out)
+
+	villain := core.NewPlayer("Peanut", core.PlayerStats{Health: 90, Strength: 10, Speed: 1}, core.PlayerSkills{})
+	villain.Strategy = core.Aggressive{}
+
+	dm := &core.DuelMaster{Rounds: 20, PlayerOne: hero, PlayerTwo: villain}
+	result := dm.StartDuel()
+
+	// 60 damage with the ability and 30 with the plain attack
+	if result.Winner != hero || result.Rounds != 2 {
+		t.Errorf("StartDuel() = %+v, want the hero to win in 2 rounds", result)
+	}
+	if !strings.Contains(out.String(), "ready in 2 turns") {
+		t.Errorf("the ability's cooldown should be presented; got %q", out.String())
+	}
+}
@@ -0,0 +1,169 @@
+package tournament
+
+import "fmt"
+
+// bracketOrder returns the seeds (1-based) in bracket slot order for a
+// bracket of the given size (power of 2), so that the top seeds meet
+// as late as possible: 1 vs 8, 4 vs 5, 2 vs 7, 3 vs 6
+func bracketOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		n := len(order) * 2
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// seedBracket places the entrants within the first round slots;
+// slots without an entrant (nil) are byes, which go to the top seeds
+func (r *run) seedBracket() []*entrant {
+	size := 1
+	for size < len(r.entrants) {
+		size *= 2
+	}
+
+	slots := make([]*entrant, size)
+	for i, seed := range bracketOrder(size) {
+		if seed <= len(r.entrants) {
+			slots[i] = r.entrants[seed-1]
+		}
+	}
+	return slots
+}
+
+// playBracketRound plays the matches between neighbouring slots
+// and returns the slots of the next round together with the losers
+func (r *run) playBracketRound(stage string, slots []*entrant) (next, losers []*entrant) {
+	for i := 0; i+1 < len(slots); i += 2 {
+		home, away := slots[i], slots[i+1]
+		switch {
+		case home == nil:
+			if away != nil {
+				r.bye(away)
+			}
+			next = append(next, away)
+		case away == nil:
+			r.bye(home)
+			next = append(next, home)
+		default:
+			winner, loser := r.playMatch(stage, home, away, true)
+			next = append(next, winner)
+			losers = append(losers, loser)
+		}
+	}
+	return next, losers
+}
+
+// playPool pairs the entrants of the pool in order; when the pool has
+// an odd size, the best seeded entrant gets a bye
+func (r *run) playPool(stage string, pool []*entrant) (winners, losers []*entrant) {
+	if len(pool)%2 == 1 {
+		best := 0
+		for i, e := range pool {
+			if e.seed < pool[best].seed {
+				best = i
+			}
+		}
+		r.bye(pool[best])
+		winners = append(winners, pool[best])
+		pool = append(append([]*entrant{}, pool[:best]...), pool[best+1:]...)
+	}
+
+	for i := 0; i+1 < len(pool); i += 2 {
+		winner, loser := r.playMatch(stage, pool[i], pool[i+1], true)
+		winners = append(winners, winner)
+		losers = append(losers, loser)
+	}
+	return winners, losers
+}
+
+func (r *run) playSingleElimination() {
+	slots := r.seedBracket()
+	for round := 1; len(slots) > 1; round++ {
+		r.stage++
+
+		var losers []*entrant
+		slots, losers = r.playBracketRound(fmt.Sprintf("Round %d", round), slots)
+		r.eliminate(losers...)
+	}
+
+	r.stage++
+	r.eliminate(slots[0])
+}
+
+// playDoubleElimination plays a winners bracket seeded like a single
+// elimination tournament; losers drop in the losers bracket and are
+// eliminated on their second lost match. The grand final is replayed
+// (bracket reset) if the losers bracket champion wins it
+func (r *run) playDoubleElimination() {
+	winners := r.seedBracket()
+	var lowerBracket []*entrant
+
+	for round, lowerRound := 1, 1; len(winners) > 1; round++ {
+		var dropped, losers []*entrant
+
+		r.stage++
+		winners, dropped = r.playBracketRound(fmt.Sprintf("Winners Round %d", round), winners)
+
+		// the losers of the winners bracket play against the
+		// survivors of the losers bracket
+		if len(lowerBracket) > 0 && len(dropped) > 0 {
+			r.stage++
+			stage := fmt.Sprintf("Losers Round %d", lowerRound)
+			lowerRound++
+
+			var survivors []*entrant
+			for i, e := range lowerBracket {
+				j := len(dropped) - 1 - i
+				if j < 0 {
+					r.bye(e)
+					survivors = append(survivors, e)
+					continue
+				}
+				winner, loser := r.playMatch(stage, e, dropped[j], true)
+				survivors = append(survivors, winner)
+				r.eliminate(loser)
+			}
+			for i := 0; i < len(dropped)-len(lowerBracket); i++ {
+				r.bye(dropped[i])
+				survivors = append(survivors, dropped[i])
+			}
+			lowerBracket = survivors
+		} else {
+			lowerBracket = append(lowerBracket, dropped...)
+		}
+
+		// the losers bracket is consolidated until it has as many
+		// entrants as the next winners round drops
+		target := len(winners) / 2
+		if target < 1 {
+			target = 1
+		}
+		for len(lowerBracket) > target {
+			r.stage++
+			stage := fmt.Sprintf("Losers Round %d", lowerRound)
+			lowerRound++
+
+			lowerBracket, losers = r.playPool(stage, lowerBracket)
+			r.eliminate(losers...)
+		}
+	}
+
+	champion := winners[0]
+	if len(lowerBracket) > 0 {
+		r.stage++
+		winner, loser := r.playMatch("Grand Final", champion, lowerBracket[0], true)
+		if winner != champion {
+			winner, loser = r.playMatch("Grand Final Reset", champion, lowerBracket[0], true)
+		}
+		r.eliminate(loser)
+		champion = winner
+	}
+
+	r.stage++
+	r.eliminate(champion)
+}
@@ -0,0 +1,49 @@
+package tournament
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+func Test_bracketOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		want []int
+	}{
+		{name: "a single slot", size: 1, want: []int{1}},
+		{name: "4 slots", size: 4, want: []int{1, 4, 2, 3}},
+		{name: "8 slots", size: 8, want: []int{1, 8, 4, 5, 2, 7, 3, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bracketOrder(tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bracketOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_run_seedBracket(t *testing.T) {
+	r := &run{}
+	for i, name := range []string{"A", "B", "C", "D", "E", "F"} {
+		r.entrants = append(r.entrants, &entrant{seed: i + 1, template: core.PlayerTemplate{Name: name}})
+	}
+
+	var got []string
+	for _, e := range r.seedBracket() {
+		name := "-"
+		if e != nil {
+			name = e.template.Name
+		}
+		got = append(got, name)
+	}
+
+	want := []string{"A", "-", "D", "E", "B", "-", "C", "F"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("seedBracket() = %v, want %v", got, want)
+	}
+}
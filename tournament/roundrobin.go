@@ -0,0 +1,46 @@
+package tournament
+
+import "fmt"
+
+// points awarded for a round robin match
+const (
+	winPoints  = 3
+	drawPoints = 1
+)
+
+// playRoundRobin plays a match between every pair of entrants;
+// the rounds are scheduled with the circle method so that every
+// entrant plays at most once per round
+func (r *run) playRoundRobin() {
+	slots := append([]*entrant{}, r.entrants...)
+	if len(slots)%2 == 1 {
+		slots = append(slots, nil)
+	}
+
+	n := len(slots)
+	for round := 1; round < n; round++ {
+		stage := fmt.Sprintf("Round %d", round)
+		for i := 0; i < n/2; i++ {
+			home, away := slots[i], slots[n-1-i]
+			switch {
+			case home == nil:
+				r.bye(away)
+			case away == nil:
+				r.bye(home)
+			default:
+				winner, _ := r.playMatch(stage, home, away, false)
+				if winner == nil {
+					home.standing.Points += drawPoints
+					away.standing.Points += drawPoints
+				} else {
+					winner.standing.Points += winPoints
+				}
+			}
+		}
+
+		// keep the first slot fixed and rotate the others
+		last := slots[n-1]
+		copy(slots[2:], slots[1:n-1])
+		slots[1] = last
+	}
+}
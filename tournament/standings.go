@@ -0,0 +1,116 @@
+package tournament
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Match represents the result of a best-of-N series
+type Match struct {
+	Stage    string `json:"stage"`
+	Home     string `json:"home"`
+	Away     string `json:"away"`
+	HomeWins int    `json:"homeWins"`
+	AwayWins int    `json:"awayWins"`
+	Ties     int    `json:"ties"`
+	// Winner is empty if the match ended with a draw
+	Winner string `json:"winner,omitempty"`
+}
+
+// Standing represents the final position of a fighter in the tournament
+type Standing struct {
+	Place int    `json:"place"`
+	Name  string `json:"name"`
+	Seed  int    `json:"seed"`
+
+	MatchWins   int `json:"matchWins"`
+	MatchLosses int `json:"matchLosses"`
+	MatchDraws  int `json:"matchDraws"`
+	Byes        int `json:"byes"`
+	Points      int `json:"points"`
+
+	DuelWins   int `json:"duelWins"`
+	DuelLosses int `json:"duelLosses"`
+	DuelTies   int `json:"duelTies"`
+}
+
+// Standings represents the final table of a tournament
+// together with every match that was played
+type Standings struct {
+	Format  string     `json:"format"`
+	BestOf  int        `json:"bestOf"`
+	Table   []Standing `json:"standings"`
+	Matches []Match    `json:"matches"`
+}
+
+// Champion returns the winner of the tournament
+func (s *Standings) Champion() Standing {
+	return s.Table[0]
+}
+
+// JSON exports the standings as indented JSON
+func (s *Standings) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// WriteText writes the standings as a text table
+func (s *Standings) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Tournament: %s (best of %d)\n", s.Format, s.BestOf)
+	fmt.Fprintln(tw, "Place\tFighter\tSeed\tMatches (W-L-D)\tDuels (W-L-T)\tByes\tPoints")
+	for _, st := range s.Table {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d-%d-%d\t%d-%d-%d\t%d\t%d\n",
+			st.Place, st.Name, st.Seed,
+			st.MatchWins, st.MatchLosses, st.MatchDraws,
+			st.DuelWins, st.DuelLosses, st.DuelTies,
+			st.Byes, st.Points,
+		)
+	}
+	return tw.Flush()
+}
+
+func (s *Standings) String() string {
+	var b strings.Builder
+	s.WriteText(&b)
+	return b.String()
+}
+
+// standings ranks the entrants; elimination tournaments rank by the
+// stage in which the entrants were knocked out (sharing the place
+// with the ones knocked out in the same stage), round robin ones by
+// points, then by duel difference and seed
+func (r *run) standings() *Standings {
+	entrants := append([]*entrant{}, r.entrants...)
+
+	roundRobin := r.Format == RoundRobin
+	sort.SliceStable(entrants, func(i, j int) bool {
+		a, b := entrants[i], entrants[j]
+		if roundRobin {
+			if a.standing.Points != b.standing.Points {
+				return a.standing.Points > b.standing.Points
+			}
+			da := a.standing.DuelWins - a.standing.DuelLosses
+			db := b.standing.DuelWins - b.standing.DuelLosses
+			if da != db {
+				return da > db
+			}
+		} else if a.eliminated != b.eliminated {
+			return a.eliminated > b.eliminated
+		}
+		return a.seed < b.seed
+	})
+
+	s := &Standings{Format: r.Format.String(), BestOf: r.bestOf(), Matches: r.matches}
+	for i, e := range entrants {
+		e.standing.Place = i + 1
+		if !roundRobin && i > 0 && e.eliminated == entrants[i-1].eliminated {
+			e.standing.Place = s.Table[i-1].Place
+		}
+		s.Table = append(s.Table, *e.standing)
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package tournament
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+func TestStandings_Export(t *testing.T) {
+	tournament := &Tournament{
+		Format: SingleElimination,
+		Rounds: 20,
+		Roster: []core.PlayerTemplate{fighter("Na`arun The Wicked", 2), fighter("Peanut", 1)},
+	}
+
+	s, err := tournament.Run()
+	if err != nil {
+		t.Fatalf("Run() returned an unexpected error: %v", err)
+	}
+
+	if s.Champion().Name != "Na`arun The Wicked" {
+		t.Errorf("Champion() = %s, want Na`arun The Wicked", s.Champion().Name)
+	}
+
+	data, err := s.JSON()
+	if err != nil {
+		t.Fatalf("JSON() returned an unexpected error: %v", err)
+	}
+	decoded := &Standings{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("JSON() returned invalid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, s) {
+		t.Errorf("JSON() didn't round trip; got %+v want %+v", decoded, s)
+	}
+
+	text := s.String()
+	for _, want := range []string{"single-elimination", "Na`arun The Wicked", "Peanut", "1-0-0"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("String() = %q, want it to contain %q", text, want)
+		}
+	}
+}
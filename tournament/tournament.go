@@ -0,0 +1,207 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pfzero/battle-simulator/core"
+)
+
+// Format represents the way the fighters are paired within a tournament
+type Format int
+
+// the supported tournament formats
+const (
+	SingleElimination Format = iota
+	DoubleElimination
+	RoundRobin
+)
+
+func (f Format) String() string {
+	switch f {
+	case SingleElimination:
+		return "single-elimination"
+	case DoubleElimination:
+		return "double-elimination"
+	case RoundRobin:
+		return "round-robin"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// Tournament runs duels between the fighters of a roster
+// until the final standings are known
+type Tournament struct {
+	Format Format
+	// BestOf is the number of duels played in every match (series);
+	// a match ends as soon as one of the fighters wins the majority
+	BestOf int
+	// Rounds is the number of rounds of every duel
+	Rounds int
+	// DamageFormula is passed to every duel
+	DamageFormula core.DamageFormula
+	// Roster contains the fighters ordered by seed; the first one is the top seed
+	Roster []core.PlayerTemplate
+
+	// Commentator is passed to every duel of the tournament
+	Commentator core.Commentator
+}
+
+// entrant is a fighter registered in the tournament
+type entrant struct {
+	seed     int
+	template core.PlayerTemplate
+	standing *Standing
+
+	// eliminated holds the stage in which the entrant
+	// got knocked out of an elimination tournament
+	eliminated int
+}
+
+// run holds the state of a tournament while it is played
+type run struct {
+	*Tournament
+
+	entrants []*entrant
+	matches  []Match
+	stage    int
+}
+
+var (
+	// ErrRosterTooSmall is returned when there are less than 2 fighters in the roster
+	ErrRosterTooSmall = errors.New("tournament: the roster needs at least 2 fighters")
+	// ErrInvalidRounds is returned when the duels don't have any rounds
+	ErrInvalidRounds = errors.New("tournament: duels need at least one round")
+	// ErrInvalidBestOf is returned for a negative series length
+	ErrInvalidBestOf = errors.New("tournament: best-of must not be negative")
+)
+
+// Run plays the whole tournament and returns the final standings
+func (t *Tournament) Run() (*Standings, error) {
+	if len(t.Roster) < 2 {
+		return nil, ErrRosterTooSmall
+	}
+	if t.Rounds <= 0 {
+		return nil, ErrInvalidRounds
+	}
+	if t.BestOf < 0 {
+		return nil, ErrInvalidBestOf
+	}
+
+	r := &run{Tournament: t}
+	for i, template := range t.Roster {
+		e := &entrant{
+			seed:     i + 1,
+			template: template,
+			standing: &Standing{Name: template.Name, Seed: i + 1},
+		}
+		r.entrants = append(r.entrants, e)
+	}
+
+	switch t.Format {
+	case SingleElimination:
+		r.playSingleElimination()
+	case DoubleElimination:
+		r.playDoubleElimination()
+	case RoundRobin:
+		r.playRoundRobin()
+	default:
+		return nil, fmt.Errorf("tournament: unknown format %v", t.Format)
+	}
+
+	return r.standings(), nil
+}
+
+func (r *run) bestOf() int {
+	if r.BestOf == 0 {
+		return 1
+	}
+	return r.BestOf
+}
+
+// duel summons both fighters and lets them fight; it returns 1 if home
+// won, -1 if away won and 0 on a tie
+func (r *run) duel(home, away *entrant) int {
+	dm := &core.DuelMaster{
+		Rounds:    r.Rounds,
+		PlayerOne: home.template.Summon(),
+		PlayerTwo: away.template.Summon(),
+
+		DamageFormula: r.DamageFormula,
+	}
+
+	var result *core.DuelResult
+	if r.Commentator != nil {
+		result = dm.StartDuel(r.Commentator)
+	} else {
+		result = dm.StartDuel()
+	}
+
+	switch {
+	case result.IsTie():
+		home.standing.DuelTies++
+		away.standing.DuelTies++
+		return 0
+	case result.Winner == dm.PlayerOne:
+		home.standing.DuelWins++
+		away.standing.DuelLosses++
+		return 1
+	default:
+		away.standing.DuelWins++
+		home.standing.DuelLosses++
+		return -1
+	}
+}
+
+// playMatch plays a best-of-N series between the given entrants and
+// returns the winner; the winner is nil if the series ended with a draw.
+// Decisive matches can't end with a draw, in which case the higher seed wins
+func (r *run) playMatch(stage string, home, away *entrant, decisive bool) (winner, loser *entrant) {
+	match := Match{Stage: stage, Home: home.template.Name, Away: away.template.Name}
+
+	games := r.bestOf()
+	needed := games/2 + 1
+	for i := 0; i < games && match.HomeWins < needed && match.AwayWins < needed; i++ {
+		switch r.duel(home, away) {
+		case 1:
+			match.HomeWins++
+		case -1:
+			match.AwayWins++
+		default:
+			match.Ties++
+		}
+	}
+
+	switch {
+	case match.HomeWins > match.AwayWins:
+		winner, loser = home, away
+	case match.HomeWins < match.AwayWins:
+		winner, loser = away, home
+	case decisive && home.seed < away.seed:
+		winner, loser = home, away
+	case decisive:
+		winner, loser = away, home
+	}
+
+	if winner == nil {
+		home.standing.MatchDraws++
+		away.standing.MatchDraws++
+	} else {
+		match.Winner = winner.template.Name
+		winner.standing.MatchWins++
+		loser.standing.MatchLosses++
+	}
+
+	r.matches = append(r.matches, match)
+	return winner, loser
+}
+
+func (r *run) bye(e *entrant) {
+	e.standing.Byes++
+}
+
+func (r *run) eliminate(losers ...*entrant) {
+	for _, e := range losers {
+		e.eliminated = r.stage
+	}
+}
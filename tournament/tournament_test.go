@@ -0,0 +1,248 @@
+package tournament
+
+import (
+	"testing"
+
+	"github.com/pfzero/battle-simulator/core"
+	"github.com/pfzero/battle-simulator/internal/testutil"
+)
+
+// fighter creates a template whose strength in a duel is given
+// by its speed: every fighter knocks out the opponent with one hit
+func fighter(name string, speed float64) core.PlayerTemplate {
+	return testutil.Template(name, 100, 1000, 0, speed)
+}
+
+// pacifist creates a template that can't damage anyone
+func pacifist(name string) core.PlayerTemplate {
+	return core.PlayerTemplate{
+		Name: name,
+		Stats: core.StatRanges{
+			Health:  core.StatRange{Min: 100, Max: 100},
+			Defence: core.StatRange{Min: 10, Max: 10},
+		},
+	}
+}
+
+func places(s *Standings) map[string]int {
+	p := map[string]int{}
+	for _, st := range s.Table {
+		p[st.Name] = st.Place
+	}
+	return p
+}
+
+func TestTournament_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		t           *Tournament
+		wantMatches int
+		wantPlaces  map[string]int
+	}{
+		{
+			name: "single elimination crowns the strongest fighter even if it has the lowest seed",
+			t: &Tournament{
+				Format: SingleElimination,
+				Rounds: 20,
+				Roster: []core.PlayerTemplate{fighter("A", 1), fighter("B", 2), fighter("C", 3), fighter("D", 4)},
+			},
+			wantMatches: 3,
+			wantPlaces:  map[string]int{"D": 1, "C": 2, "A": 3, "B": 3},
+		},
+		{
+			name: "single elimination gives byes to the top seeds",
+			t: &Tournament{
+				Format: SingleElimination,
+				Rounds: 20,
+				Roster: []core.PlayerTemplate{fighter("A", 5), fighter("B", 4), fighter("C", 3), fighter("D", 2), fighter("E", 1)},
+			},
+			wantMatches: 4,
+			wantPlaces:  map[string]int{"A": 1, "B": 2, "C": 3, "D": 3, "E": 5},
+		},
+		{
+			name: "double elimination eliminates fighters after their second lost match",
+			t: &Tournament{
+				Format: DoubleElimination,
+				Rounds: 20,
+				Roster: []core.PlayerTemplate{fighter("A", 4), fighter("B", 3), fighter("C", 2), fighter("D", 1)},
+			},
+			wantMatches: 6,
+			wantPlaces:  map[string]int{"A": 1, "B": 2, "C": 3, "D": 4},
+		},
+		{
+			name: "double elimination gives byes to the top seeds",
+			t: &Tournament{
+				Format: DoubleElimination,
+				Rounds: 20,
+				Roster: []core.PlayerTemplate{fighter("A", 2), fighter("B", 1), fighter("C", 3)},
+			},
+			wantPlaces: map[string]int{"C": 1, "A": 2, "B": 3},
+		},
+		{
+			name: "round robin ranks the fighters by points",
+			t: &Tournament{
+				Format: RoundRobin,
+				Rounds: 20,
+				Roster: []core.PlayerTemplate{fighter("A", 1), fighter("B", 3), fighter("C", 2)},
+			},
+			wantMatches: 3,
+			wantPlaces:  map[string]int{"B": 1, "C": 2, "A": 3},
+		},
+		{
+			name: "drawn elimination matches are won by the higher seed",
+			t: &Tournament{
+				Format: SingleElimination,
+				Rounds: 2,
+				BestOf: 3,
+				Roster: []core.PlayerTemplate{pacifist("A"), pacifist("B")},
+			},
+			wantMatches: 1,
+			wantPlaces:  map[string]int{"A": 1, "B": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.t.Run()
+			if err != nil {
+				t.Fatalf("Run() returned an unexpected error: %v", err)
+			}
+			if tt.wantMatches > 0 && len(got.Matches) != tt.wantMatches {
+				t.Errorf("Run() played %d matches, want %d", len(got.Matches), tt.wantMatches)
+			}
+			gotPlaces := places(got)
+			for name, place := range tt.wantPlaces {
+				if gotPlaces[name] != place {
+					t.Errorf("Run() placed %s on %d, want %d", name, gotPlaces[name], place)
+				}
+			}
+		})
+	}
+}
+
+func TestTournament_Run_DoubleEliminationLosses(t *testing.T) {
+	tournament := &Tournament{
+		Format: DoubleElimination,
+		Rounds: 20,
+		Roster: []core.PlayerTemplate{
+			fighter("A", 6), fighter("B", 5), fighter("C", 4),
+			fighter("D", 3), fighter("E", 2), fighter("F", 1),
+		},
+	}
+
+	got, err := tournament.Run()
+	if err != nil {
+		t.Fatalf("Run() returned an unexpected error: %v", err)
+	}
+
+	for _, st := range got.Table {
+		wantLosses := 2
+		if st.Name == "A" {
+			wantLosses = 0
+		}
+		if st.MatchLosses != wantLosses {
+			t.Errorf("%s lost %d matches, want %d", st.Name, st.MatchLosses, wantLosses)
+		}
+	}
+}
+
+func TestTournament_Run_BestOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		t         *Tournament
+		wantWins  int
+		wantTies  int
+		wantDuels int
+	}{
+		{
+			name: "stops the series once a fighter won the majority",
+			t: &Tournament{
+				Format: SingleElimination,
+				Rounds: 20,
+				BestOf: 5,
+				Roster: []core.PlayerTemplate{fighter("A", 2), fighter("B", 1)},
+			},
+			wantWins:  3,
+			wantDuels: 3,
+		},
+		{
+			name: "plays all the duels of a series when they end with ties",
+			t: &Tournament{
+				Format: RoundRobin,
+				Rounds: 1,
+				BestOf: 3,
+				Roster: []core.PlayerTemplate{pacifist("A"), pacifist("B")},
+			},
+			wantTies:  3,
+			wantDuels: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.t.Run()
+			if err != nil {
+				t.Fatalf("Run() returned an unexpected error: %v", err)
+			}
+			m := got.Matches[0]
+			if m.HomeWins+m.AwayWins+m.Ties != tt.wantDuels {
+				t.Errorf("the match had %d duels, want %d", m.HomeWins+m.AwayWins+m.Ties, tt.wantDuels)
+			}
+			if m.HomeWins != tt.wantWins || m.Ties != tt.wantTies {
+				t.Errorf("the match ended %d-%d-%d, want %d wins and %d ties", m.HomeWins, m.AwayWins, m.Ties, tt.wantWins, tt.wantTies)
+			}
+		})
+	}
+}
+
+func TestTournament_Run_Draws(t *testing.T) {
+	tournament := &Tournament{
+		Format: RoundRobin,
+		Rounds: 1,
+		Roster: []core.PlayerTemplate{pacifist("A"), pacifist("B"), pacifist("C")},
+	}
+
+	got, err := tournament.Run()
+	if err != nil {
+		t.Fatalf("Run() returned an unexpected error: %v", err)
+	}
+
+	for _, st := range got.Table {
+		if st.Points != 2*drawPoints || st.MatchDraws != 2 || st.Byes != 1 {
+			t.Errorf("%s has %d points, %d draws and %d byes; want %d points, 2 draws and 1 bye",
+				st.Name, st.Points, st.MatchDraws, st.Byes, 2*drawPoints)
+		}
+	}
+}
+
+func TestTournament_Run_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		t    *Tournament
+		want error
+	}{
+		{
+			name: "needs at least 2 fighters",
+			t:    &Tournament{Rounds: 20, Roster: []core.PlayerTemplate{fighter("A", 1)}},
+			want: ErrRosterTooSmall,
+		},
+		{
+			name: "needs at least a round per duel",
+			t:    &Tournament{Roster: []core.PlayerTemplate{fighter("A", 1), fighter("B", 1)}},
+			want: ErrInvalidRounds,
+		},
+		{
+			name: "needs a positive best-of",
+			t:    &Tournament{Rounds: 20, BestOf: -1, Roster: []core.PlayerTemplate{fighter("A", 1), fighter("B", 1)}},
+			want: ErrInvalidBestOf,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.t.Run(); err != tt.want {
+				t.Errorf("Run() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}